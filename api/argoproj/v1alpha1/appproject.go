@@ -21,7 +21,11 @@ type AppProject struct {
 
 // AppProjectSpec is the specification of an AppProject
 type AppProjectSpec struct {
-	// Roles are user defined RBAC roles associated with this project
+	// Roles are user defined RBAC roles associated with this project. The
+	// controller relies on the list map semantics below when managing roles
+	// with server-side apply.
+	// +listType=map
+	// +listMapKey=name
 	Roles []ProjectRole `json:"roles,omitempty" protobuf:"bytes,1,rep,name=roles"`
 }
 
@@ -18,6 +18,7 @@ package v1alpha1
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 	"text/template"
 
@@ -59,6 +60,16 @@ type AccessBindingSpec struct {
 	// FriendlyName defines a name for this role
 	// +kubebuilder:validation:MaxLength=512
 	FriendlyName *string `json:"friendlyName,omitempty"`
+	// GroupTargets is the list of groups that the subjects of this binding
+	// are allowed to elevate as a whole. If empty, only individual access
+	// can be requested with this binding. Use "*" to allow any group.
+	// +optional
+	GroupTargets []string `json:"groupTargets,omitempty"`
+	// BreakGlass defines if this binding allows break-glass AccessRequests.
+	// Break-glass AccessRequests are only allowed by break-glass bindings
+	// and break-glass bindings only allow break-glass AccessRequests.
+	// +optional
+	BreakGlass bool `json:"breakGlass,omitempty"`
 }
 
 // RoleTemplateReference is a reference to a RoleTemplate
@@ -68,16 +79,20 @@ type RoleTemplateReference struct {
 	Name string `json:"name"`
 }
 
-// RenderSubjects renders the access bindings subjects when the If condition is evaluated to true
+// RenderSubjects renders the access bindings subjects when the If condition is evaluated to true.
+// The app is nil for project-scoped access requests, in which case only the project is
+// available to the subjects templates and the If condition.
 func (ab *AccessBinding) RenderSubjects(app, project *unstructured.Unstructured) ([]string, error) {
 	if len(ab.Spec.Subjects) == 0 {
 		return nil, nil
 	}
 
 	values := map[string]interface{}{
-		"app":         app.Object,
-		"application": app.Object,
-		"project":     project.Object,
+		"project": project.Object,
+	}
+	if app != nil {
+		values["app"] = app.Object
+		values["application"] = app.Object
 	}
 
 	if ab.Spec.If != nil {
@@ -110,6 +125,40 @@ func (ab *AccessBinding) RenderSubjects(app, project *unstructured.Unstructured)
 	return subjects, nil
 }
 
+// AllowsGroup returns true if the subjects of this binding are allowed to
+// request the elevation of the given group as a whole.
+func (ab *AccessBinding) AllowsGroup(group string) bool {
+	for _, target := range ab.Spec.GroupTargets {
+		if target == "*" || target == group {
+			return true
+		}
+	}
+	return false
+}
+
+// Grants returns true if this binding allows a user with the given groups
+// to request its role. If targetGroup is provided, it must be allowed by the
+// binding GroupTargets. Break-glass bindings only grant break-glass requests
+// and regular bindings only grant regular requests.
+func (ab *AccessBinding) Grants(groups []string, targetGroup string, breakGlass bool, app, project *unstructured.Unstructured) (bool, error) {
+	if ab.Spec.BreakGlass != breakGlass {
+		return false, nil
+	}
+	if targetGroup != "" && !ab.AllowsGroup(targetGroup) {
+		return false, nil
+	}
+	subjects, err := ab.RenderSubjects(app, project)
+	if err != nil {
+		return false, err
+	}
+	for _, subject := range subjects {
+		if slices.Contains(groups, subject) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func (ab *AccessBinding) execTemplate(
 	tmpl *template.Template,
 	values any,
@@ -115,3 +115,99 @@ func TestAccessBinding_RenderSubjects(t *testing.T) {
 		})
 	}
 }
+
+func TestAccessBinding_RenderSubjectsForProject(t *testing.T) {
+	project, err := utils.ToUnstructured(&argocd.AppProject{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "test",
+			Labels: map[string]string{"team": "payments"},
+		},
+	})
+	require.NoError(t, err)
+	t.Run("will render subjects using only the project", func(t *testing.T) {
+		ab := &api.AccessBinding{
+			Spec: api.AccessBindingSpec{
+				If:       ptr.To(`project.metadata.labels.team == "payments"`),
+				Subjects: []string{"{{ .project.metadata.labels.team }}-admins"},
+			},
+		}
+		got, err := ab.RenderSubjects(nil, project)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"payments-admins"}, got)
+	})
+	t.Run("will return error if subjects reference the application", func(t *testing.T) {
+		ab := &api.AccessBinding{
+			Spec: api.AccessBindingSpec{
+				Subjects: []string{`{{ index .application.metadata.annotations "test" }}`},
+			},
+		}
+		_, err := ab.RenderSubjects(nil, project)
+		assert.ErrorContains(t, err, "error rendering AccessBinding subjects")
+	})
+}
+
+func TestAccessBinding_AllowsGroup(t *testing.T) {
+	t.Run("will not allow groups if targets are not defined", func(t *testing.T) {
+		ab := &api.AccessBinding{}
+		assert.False(t, ab.AllowsGroup("on-call"))
+	})
+	t.Run("will allow only the defined targets", func(t *testing.T) {
+		ab := &api.AccessBinding{Spec: api.AccessBindingSpec{GroupTargets: []string{"on-call"}}}
+		assert.True(t, ab.AllowsGroup("on-call"))
+		assert.False(t, ab.AllowsGroup("admins"))
+	})
+	t.Run("will allow any group with wildcard", func(t *testing.T) {
+		ab := &api.AccessBinding{Spec: api.AccessBindingSpec{GroupTargets: []string{"*"}}}
+		assert.True(t, ab.AllowsGroup("admins"))
+	})
+}
+
+func TestAccessBinding_Grants(t *testing.T) {
+	project, err := utils.ToUnstructured(&argocd.AppProject{
+		ObjectMeta: metav1.ObjectMeta{Name: "some-project"},
+	})
+	require.NoError(t, err)
+	newBinding := func() *api.AccessBinding {
+		return &api.AccessBinding{Spec: api.AccessBindingSpec{
+			Subjects: []string{"devops", "{{.project.metadata.name}}-admins"},
+		}}
+	}
+	t.Run("will grant if a group matches a rendered subject", func(t *testing.T) {
+		granted, err := newBinding().Grants([]string{"devs", "some-project-admins"}, "", false, nil, project)
+		assert.NoError(t, err)
+		assert.True(t, granted)
+	})
+	t.Run("will not grant if no group matches", func(t *testing.T) {
+		granted, err := newBinding().Grants([]string{"devs"}, "", false, nil, project)
+		assert.NoError(t, err)
+		assert.False(t, granted)
+	})
+	t.Run("will not grant a target group not allowed", func(t *testing.T) {
+		ab := newBinding()
+		ab.Spec.GroupTargets = []string{"on-call"}
+		granted, err := ab.Grants([]string{"devops"}, "admins", false, nil, project)
+		assert.NoError(t, err)
+		assert.False(t, granted)
+		granted, err = ab.Grants([]string{"devops"}, "on-call", false, nil, project)
+		assert.NoError(t, err)
+		assert.True(t, granted)
+	})
+	t.Run("will only grant matching break-glass mode", func(t *testing.T) {
+		ab := newBinding()
+		granted, err := ab.Grants([]string{"devops"}, "", true, nil, project)
+		assert.NoError(t, err)
+		assert.False(t, granted)
+		ab.Spec.BreakGlass = true
+		granted, err = ab.Grants([]string{"devops"}, "", false, nil, project)
+		assert.NoError(t, err)
+		assert.False(t, granted)
+		granted, err = ab.Grants([]string{"devops"}, "", true, nil, project)
+		assert.NoError(t, err)
+		assert.True(t, granted)
+	})
+	t.Run("will return error if subjects can not be rendered", func(t *testing.T) {
+		ab := &api.AccessBinding{Spec: api.AccessBindingSpec{Subjects: []string{"{{.invalid"}}}
+		_, err := ab.Grants([]string{"devops"}, "", false, nil, project)
+		assert.Error(t, err)
+	})
+}
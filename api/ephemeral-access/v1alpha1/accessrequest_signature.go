@@ -0,0 +1,261 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// SignatureAnnotation is the annotation holding the HMAC signature added by
+// the backend to the AccessRequests it creates.
+const SignatureAnnotation = "ephemeral-access.argoproj-labs.io/signature"
+
+// ApprovalSignatureAnnotation is the annotation holding the HMAC signature
+// added by the backend when it records the approval decision.
+const ApprovalSignatureAnnotation = "ephemeral-access.argoproj-labs.io/approval-signature"
+
+// ReviewSignatureAnnotation is the annotation holding the HMAC signature
+// added by the backend when it records the break-glass review.
+const ReviewSignatureAnnotation = "ephemeral-access.argoproj-labs.io/review-signature"
+
+// ExtensionsSignatureAnnotation is the annotation holding the HMAC signature
+// added by the backend when it records an extension.
+const ExtensionsSignatureAnnotation = "ephemeral-access.argoproj-labs.io/extensions-signature"
+
+// ErrInvalidSignature is returned when the AccessRequest signature is
+// missing or does not match its contents.
+var ErrInvalidSignature = errors.New("invalid signature")
+
+// signedContent defines the AccessRequest fields covered by the signature.
+type signedContent struct {
+	Namespace string            `json:"namespace"`
+	Name      string            `json:"name"`
+	Spec      AccessRequestSpec `json:"spec"`
+}
+
+// signedApproval defines the fields covered by the approval signature. The
+// AccessRequest signature and UID are included so the approval can't be
+// copied to another AccessRequest nor replayed in a recreated one.
+type signedApproval struct {
+	Signature string    `json:"signature"`
+	UID       types.UID `json:"uid"`
+	Approval  *Approval `json:"approval"`
+}
+
+// signedReview defines the fields covered by the review signature. The
+// AccessRequest signature and UID are included so the review can't be
+// copied to another AccessRequest nor replayed in a recreated one.
+type signedReview struct {
+	Signature string    `json:"signature"`
+	UID       types.UID `json:"uid"`
+	Review    *Review   `json:"review"`
+}
+
+// signedExtensions defines the fields covered by the extensions signature.
+// The AccessRequest signature and UID are included so the extensions can't
+// be copied to another AccessRequest nor replayed in a recreated one.
+type signedExtensions struct {
+	Signature  string      `json:"signature"`
+	UID        types.UID   `json:"uid"`
+	Extensions []Extension `json:"extensions"`
+}
+
+// Sign will compute the HMAC-SHA256 signature of the ar using the given key
+// and store it in the SignatureAnnotation. The signature covers the ar
+// namespace, name and the spec fields defined when the AccessRequest is
+// created, so the ar must have a name. The fields updated afterwards
+// (approval, review, revocation and extensions) are not signed.
+func (ar *AccessRequest) Sign(key []byte) error {
+	if ar.GetName() == "" {
+		return errors.New("access request name must be provided")
+	}
+	signature, err := ar.signature(key)
+	if err != nil {
+		return err
+	}
+	ar.setAnnotation(SignatureAnnotation, signature)
+	return nil
+}
+
+// VerifySignature will verify if the signature stored in the ar
+// SignatureAnnotation was generated with the given key and matches the
+// ar contents. Returns ErrInvalidSignature otherwise.
+func (ar *AccessRequest) VerifySignature(key []byte) error {
+	expected, err := ar.signature(key)
+	if err != nil {
+		return err
+	}
+	return ar.verifyAnnotation(SignatureAnnotation, expected, "AccessRequest")
+}
+
+// SignApproval will compute the HMAC-SHA256 signature of the ar approval
+// using the given key and store it in the ApprovalSignatureAnnotation. The
+// ar must be signed and created first as the signature covers its UID.
+func (ar *AccessRequest) SignApproval(key []byte) error {
+	signature, err := ar.approvalSignature(key)
+	if err != nil {
+		return err
+	}
+	ar.setAnnotation(ApprovalSignatureAnnotation, signature)
+	return nil
+}
+
+// VerifyApprovalSignature will verify if the signature stored in the ar
+// ApprovalSignatureAnnotation was generated with the given key and matches
+// the ar approval. Returns ErrInvalidSignature otherwise.
+func (ar *AccessRequest) VerifyApprovalSignature(key []byte) error {
+	expected, err := ar.approvalSignature(key)
+	if err != nil {
+		return err
+	}
+	return ar.verifyAnnotation(ApprovalSignatureAnnotation, expected, "approval")
+}
+
+// SignReview will compute the HMAC-SHA256 signature of the ar review using
+// the given key and store it in the ReviewSignatureAnnotation. The ar must
+// be signed and created first as the signature covers its UID.
+func (ar *AccessRequest) SignReview(key []byte) error {
+	signature, err := ar.reviewSignature(key)
+	if err != nil {
+		return err
+	}
+	ar.setAnnotation(ReviewSignatureAnnotation, signature)
+	return nil
+}
+
+// VerifyReviewSignature will verify if the signature stored in the ar
+// ReviewSignatureAnnotation was generated with the given key and matches
+// the ar review. Returns ErrInvalidSignature otherwise.
+func (ar *AccessRequest) VerifyReviewSignature(key []byte) error {
+	expected, err := ar.reviewSignature(key)
+	if err != nil {
+		return err
+	}
+	return ar.verifyAnnotation(ReviewSignatureAnnotation, expected, "review")
+}
+
+// SignExtensions will compute the HMAC-SHA256 signature of all the ar
+// extensions using the given key and store it in the
+// ExtensionsSignatureAnnotation. The ar must be signed and created first as
+// the signature covers its UID.
+func (ar *AccessRequest) SignExtensions(key []byte) error {
+	signature, err := ar.extensionsSignature(key)
+	if err != nil {
+		return err
+	}
+	ar.setAnnotation(ExtensionsSignatureAnnotation, signature)
+	return nil
+}
+
+// VerifyExtensionsSignature will verify if the signature stored in the ar
+// ExtensionsSignatureAnnotation was generated with the given key and
+// matches the ar extensions. Returns ErrInvalidSignature otherwise.
+func (ar *AccessRequest) VerifyExtensionsSignature(key []byte) error {
+	expected, err := ar.extensionsSignature(key)
+	if err != nil {
+		return err
+	}
+	return ar.verifyAnnotation(ExtensionsSignatureAnnotation, expected, "extensions")
+}
+
+func (ar *AccessRequest) setAnnotation(key, value string) {
+	annotations := ar.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[key] = value
+	ar.SetAnnotations(annotations)
+}
+
+func (ar *AccessRequest) verifyAnnotation(annotation, expected, subject string) error {
+	signature, ok := ar.GetAnnotations()[annotation]
+	if !ok || signature == "" {
+		return fmt.Errorf("%w: missing %s annotation", ErrInvalidSignature, annotation)
+	}
+	if !hmac.Equal([]byte(signature), []byte(expected)) {
+		return fmt.Errorf("%w: signature does not match the %s", ErrInvalidSignature, subject)
+	}
+	return nil
+}
+
+func (ar *AccessRequest) signature(key []byte) (string, error) {
+	content := signedContent{
+		Namespace: ar.GetNamespace(),
+		Name:      ar.GetName(),
+		Spec:      *ar.Spec.DeepCopy(),
+	}
+	content.Spec.Approval = nil
+	content.Spec.Review = nil
+	content.Spec.Revocation = nil
+	content.Spec.Extensions = nil
+	return computeSignature(key, content)
+}
+
+func (ar *AccessRequest) approvalSignature(key []byte) (string, error) {
+	if ar.Spec.Approval == nil {
+		return "", errors.New("access request approval must be provided")
+	}
+	return computeSignature(key, signedApproval{
+		Signature: ar.GetAnnotations()[SignatureAnnotation],
+		UID:       ar.GetUID(),
+		Approval:  ar.Spec.Approval,
+	})
+}
+
+func (ar *AccessRequest) reviewSignature(key []byte) (string, error) {
+	if ar.Spec.Review == nil {
+		return "", errors.New("access request review must be provided")
+	}
+	return computeSignature(key, signedReview{
+		Signature: ar.GetAnnotations()[SignatureAnnotation],
+		UID:       ar.GetUID(),
+		Review:    ar.Spec.Review,
+	})
+}
+
+func (ar *AccessRequest) extensionsSignature(key []byte) (string, error) {
+	if len(ar.Spec.Extensions) == 0 {
+		return "", errors.New("access request extensions must be provided")
+	}
+	return computeSignature(key, signedExtensions{
+		Signature:  ar.GetAnnotations()[SignatureAnnotation],
+		UID:        ar.GetUID(),
+		Extensions: ar.Spec.Extensions,
+	})
+}
+
+// computeSignature returns the hex encoded HMAC-SHA256 of the JSON
+// representation of the given content.
+func computeSignature(key []byte, content any) (string, error) {
+	if len(key) == 0 {
+		return "", errors.New("signing key must be provided")
+	}
+	data, err := json.Marshal(content)
+	if err != nil {
+		return "", fmt.Errorf("error marshaling AccessRequest: %w", err)
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(data)
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
@@ -0,0 +1,335 @@
+package v1alpha1_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/argoproj-labs/ephemeral-access/api/ephemeral-access/v1alpha1"
+	"github.com/argoproj-labs/ephemeral-access/test/utils"
+)
+
+func TestAccessRequest_Signature(t *testing.T) {
+	key := []byte("some-secret")
+	newSignedAR := func(t *testing.T) *api.AccessRequest {
+		t.Helper()
+		ar := utils.NewAccessRequestCreated()
+		ar.Spec.Subject.Groups = []string{"group1"}
+		require.NoError(t, ar.Sign(key))
+		return ar
+	}
+	t.Run("will verify the signature of a signed AccessRequest", func(t *testing.T) {
+		// Given
+		ar := newSignedAR(t)
+
+		// When
+		err := ar.VerifySignature(key)
+
+		// Then
+		assert.NoError(t, err)
+		assert.NotEmpty(t, ar.GetAnnotations()[api.SignatureAnnotation])
+	})
+	t.Run("will ignore fields updated after the AccessRequest creation", func(t *testing.T) {
+		// Given
+		ar := newSignedAR(t)
+		ar.Spec.Approval = &api.Approval{Approver: "approver"}
+		ar.Spec.Review = &api.Review{Reviewer: "reviewer"}
+		ar.Spec.Revocation = &api.Revocation{Revoker: "revoker"}
+		ar.Spec.Extensions = []api.Extension{{Requester: "requester"}}
+		ar.Status.RequestState = api.GrantedStatus
+
+		// When
+		err := ar.VerifySignature(key)
+
+		// Then
+		assert.NoError(t, err)
+	})
+	t.Run("will return error if the signature is missing", func(t *testing.T) {
+		// Given
+		ar := utils.NewAccessRequestCreated()
+
+		// When
+		err := ar.VerifySignature(key)
+
+		// Then
+		assert.ErrorIs(t, err, api.ErrInvalidSignature)
+		assert.ErrorContains(t, err, "missing")
+	})
+	t.Run("will return error if the spec is modified", func(t *testing.T) {
+		// Given
+		ar := newSignedAR(t)
+		ar.Spec.Duration = metav1.Duration{Duration: 8 * time.Hour}
+
+		// When
+		err := ar.VerifySignature(key)
+
+		// Then
+		assert.ErrorIs(t, err, api.ErrInvalidSignature)
+	})
+	t.Run("will return error if the subject groups are modified", func(t *testing.T) {
+		// Given
+		ar := newSignedAR(t)
+		ar.Spec.Subject.Groups = append(ar.Spec.Subject.Groups, "admins")
+
+		// When
+		err := ar.VerifySignature(key)
+
+		// Then
+		assert.ErrorIs(t, err, api.ErrInvalidSignature)
+	})
+	t.Run("will return error if the signature is copied to another namespace", func(t *testing.T) {
+		// Given
+		ar := newSignedAR(t)
+		ar.SetNamespace("another-namespace")
+
+		// When
+		err := ar.VerifySignature(key)
+
+		// Then
+		assert.ErrorIs(t, err, api.ErrInvalidSignature)
+	})
+	t.Run("will return error if the spec is replayed under another name", func(t *testing.T) {
+		// Given
+		ar := newSignedAR(t)
+		ar.SetName("another-name")
+
+		// When
+		err := ar.VerifySignature(key)
+
+		// Then
+		assert.ErrorIs(t, err, api.ErrInvalidSignature)
+	})
+	t.Run("will return error if the name is empty", func(t *testing.T) {
+		// Given
+		ar := utils.NewAccessRequestCreated()
+		ar.SetName("")
+
+		// When
+		err := ar.Sign(key)
+
+		// Then
+		assert.ErrorContains(t, err, "access request name must be provided")
+	})
+	t.Run("will return error if signed with a different key", func(t *testing.T) {
+		// Given
+		ar := newSignedAR(t)
+
+		// When
+		err := ar.VerifySignature([]byte("another-secret"))
+
+		// Then
+		assert.ErrorIs(t, err, api.ErrInvalidSignature)
+	})
+	t.Run("will return error if the key is empty", func(t *testing.T) {
+		// Given
+		ar := utils.NewAccessRequestCreated()
+
+		// When
+		err := ar.Sign(nil)
+
+		// Then
+		assert.ErrorContains(t, err, "signing key must be provided")
+	})
+}
+
+func TestAccessRequest_ApprovalSignature(t *testing.T) {
+	key := []byte("some-secret")
+	newApprovedAR := func(t *testing.T) *api.AccessRequest {
+		t.Helper()
+		ar := utils.NewAccessRequestCreated()
+		ar.SetUID("some-uid")
+		require.NoError(t, ar.Sign(key))
+		ar.Spec.Approval = &api.Approval{
+			Decision: api.ApprovedDecision,
+			Approver: "approver",
+		}
+		require.NoError(t, ar.SignApproval(key))
+		return ar
+	}
+	t.Run("will verify the signature of a signed approval", func(t *testing.T) {
+		// Given
+		ar := newApprovedAR(t)
+
+		// When
+		err := ar.VerifyApprovalSignature(key)
+
+		// Then
+		assert.NoError(t, err)
+		assert.NotEmpty(t, ar.GetAnnotations()[api.ApprovalSignatureAnnotation])
+	})
+	t.Run("will return error if the approval is forged", func(t *testing.T) {
+		// Given
+		ar := utils.NewAccessRequestCreated()
+		require.NoError(t, ar.Sign(key))
+		ar.Spec.Approval = &api.Approval{Decision: api.ApprovedDecision, Approver: "approver"}
+
+		// When
+		err := ar.VerifyApprovalSignature(key)
+
+		// Then
+		assert.ErrorIs(t, err, api.ErrInvalidSignature)
+		assert.ErrorContains(t, err, "missing")
+	})
+	t.Run("will return error if the approval is modified after signed", func(t *testing.T) {
+		// Given
+		ar := newApprovedAR(t)
+		ar.Spec.Approval.Approver = "another-approver"
+
+		// When
+		err := ar.VerifyApprovalSignature(key)
+
+		// Then
+		assert.ErrorIs(t, err, api.ErrInvalidSignature)
+	})
+	t.Run("will return error if the approval is copied to another AccessRequest", func(t *testing.T) {
+		// Given
+		approved := newApprovedAR(t)
+		ar := utils.NewAccessRequestCreated()
+		ar.SetName("another-name")
+		require.NoError(t, ar.Sign(key))
+		ar.Spec.Approval = approved.Spec.Approval.DeepCopy()
+		ar.GetAnnotations()[api.ApprovalSignatureAnnotation] = approved.GetAnnotations()[api.ApprovalSignatureAnnotation]
+
+		// When
+		err := ar.VerifyApprovalSignature(key)
+
+		// Then
+		assert.ErrorIs(t, err, api.ErrInvalidSignature)
+	})
+	t.Run("will return error if the approval is replayed in a recreated AccessRequest", func(t *testing.T) {
+		// Given
+		ar := newApprovedAR(t)
+		ar.SetUID("another-uid")
+
+		// When
+		err := ar.VerifyApprovalSignature(key)
+
+		// Then
+		assert.ErrorIs(t, err, api.ErrInvalidSignature)
+	})
+	t.Run("will return error if there is no approval", func(t *testing.T) {
+		// Given
+		ar := utils.NewAccessRequestCreated()
+		require.NoError(t, ar.Sign(key))
+
+		// When
+		err := ar.SignApproval(key)
+
+		// Then
+		assert.Error(t, err)
+	})
+}
+
+func TestAccessRequest_ReviewSignature(t *testing.T) {
+	key := []byte("some-secret")
+	newReviewedAR := func(t *testing.T) *api.AccessRequest {
+		t.Helper()
+		ar := utils.NewAccessRequestCreated()
+		ar.Spec.BreakGlass = true
+		require.NoError(t, ar.Sign(key))
+		ar.Spec.Review = &api.Review{Reviewer: "reviewer"}
+		require.NoError(t, ar.SignReview(key))
+		return ar
+	}
+	t.Run("will verify the signature of a signed review", func(t *testing.T) {
+		// Given
+		ar := newReviewedAR(t)
+
+		// When
+		err := ar.VerifyReviewSignature(key)
+
+		// Then
+		assert.NoError(t, err)
+		assert.NotEmpty(t, ar.GetAnnotations()[api.ReviewSignatureAnnotation])
+	})
+	t.Run("will return error if the review is forged", func(t *testing.T) {
+		// Given
+		ar := utils.NewAccessRequestCreated()
+		require.NoError(t, ar.Sign(key))
+		ar.Spec.Review = &api.Review{Reviewer: "reviewer"}
+
+		// When
+		err := ar.VerifyReviewSignature(key)
+
+		// Then
+		assert.ErrorIs(t, err, api.ErrInvalidSignature)
+		assert.ErrorContains(t, err, "missing")
+	})
+	t.Run("will return error if the review is modified after signed", func(t *testing.T) {
+		// Given
+		ar := newReviewedAR(t)
+		ar.Spec.Review.Reviewer = "another-reviewer"
+
+		// When
+		err := ar.VerifyReviewSignature(key)
+
+		// Then
+		assert.ErrorIs(t, err, api.ErrInvalidSignature)
+	})
+}
+
+func TestAccessRequest_ExtensionsSignature(t *testing.T) {
+	key := []byte("some-secret")
+	newExtendedAR := func(t *testing.T) *api.AccessRequest {
+		t.Helper()
+		ar := utils.NewAccessRequestCreated()
+		ar.SetUID("some-uid")
+		require.NoError(t, ar.Sign(key))
+		ar.Spec.Extensions = []api.Extension{{
+			Duration:  metav1.Duration{Duration: time.Hour},
+			Requester: "requester",
+		}}
+		require.NoError(t, ar.SignExtensions(key))
+		return ar
+	}
+	t.Run("will verify the signature of signed extensions", func(t *testing.T) {
+		// Given
+		ar := newExtendedAR(t)
+
+		// When
+		err := ar.VerifyExtensionsSignature(key)
+
+		// Then
+		assert.NoError(t, err)
+		assert.NotEmpty(t, ar.GetAnnotations()[api.ExtensionsSignatureAnnotation])
+	})
+	t.Run("will return error if an extension is appended after signed", func(t *testing.T) {
+		// Given
+		ar := newExtendedAR(t)
+		ar.Spec.Extensions = append(ar.Spec.Extensions, api.Extension{
+			Duration:  metav1.Duration{Duration: time.Hour},
+			Requester: "requester",
+		})
+
+		// When
+		err := ar.VerifyExtensionsSignature(key)
+
+		// Then
+		assert.ErrorIs(t, err, api.ErrInvalidSignature)
+	})
+	t.Run("will return error if the extension requester is forged", func(t *testing.T) {
+		// Given
+		ar := newExtendedAR(t)
+		ar.Spec.Extensions[0].Requester = "another-requester"
+
+		// When
+		err := ar.VerifyExtensionsSignature(key)
+
+		// Then
+		assert.ErrorIs(t, err, api.ErrInvalidSignature)
+	})
+	t.Run("will return error if the extensions are replayed in a recreated AccessRequest", func(t *testing.T) {
+		// Given
+		ar := newExtendedAR(t)
+		ar.SetUID("another-uid")
+
+		// When
+		err := ar.VerifyExtensionsSignature(key)
+
+		// Then
+		assert.ErrorIs(t, err, api.ErrInvalidSignature)
+	})
+}
@@ -18,20 +18,26 @@ package v1alpha1
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // Status defines the different stages a given access request can be
 // at a given time.
-// +kubebuilder:validation:Enum=requested;granted;expired;denied;invalid
+// +kubebuilder:validation:Enum=requested;scheduled;granted;expired;denied;invalid;revoked
 type Status string
 
 const (
 	// RequestedStatus is the stage that defines the access request as pending
 	RequestedStatus Status = "requested"
 
+	// ScheduledStatus is the stage that defines the access request as
+	// waiting for its start time to be granted
+	ScheduledStatus Status = "scheduled"
+
 	// GrantedStatus is the stage that defines the access request as granted
 	GrantedStatus Status = "granted"
 
@@ -43,6 +49,46 @@ const (
 
 	// InvalidStatus is the used to identify invalid access requests
 	InvalidStatus Status = "invalid"
+
+	// RevokedStatus is the stage that defines the access request as revoked
+	// before its natural expiration
+	RevokedStatus Status = "revoked"
+)
+
+// Condition types maintained in the AccessRequest status.
+const (
+	// ConditionReady indicates if the last reconciliation of the
+	// AccessRequest concluded without errors
+	ConditionReady = "Ready"
+
+	// ConditionGranted indicates if the access is currently granted
+	ConditionGranted = "Granted"
+
+	// ConditionApplicationResolved indicates if the Argo CD Application
+	// referenced by the AccessRequest was retrieved successfully
+	ConditionApplicationResolved = "ApplicationResolved"
+
+	// ConditionProjectResolved indicates if the Argo CD AppProject
+	// referenced by a project-scoped AccessRequest was retrieved successfully
+	ConditionProjectResolved = "ProjectResolved"
+
+	// ConditionRoleTemplateResolved indicates if the RoleTemplate referenced
+	// by the AccessRequest was retrieved and rendered successfully
+	ConditionRoleTemplateResolved = "RoleTemplateResolved"
+
+	// ConditionProjectPatched indicates if the last attempt to update the
+	// subject in the Argo CD AppProject role succeeded
+	ConditionProjectPatched = "ProjectPatched"
+)
+
+// Labels added to break-glass AccessRequests.
+const (
+	// BreakGlassLabel identifies break-glass AccessRequests
+	BreakGlassLabel = "ephemeral-access.argoproj-labs.io/break-glass"
+
+	// PendingReviewLabel identifies break-glass AccessRequests that were
+	// not reviewed yet
+	PendingReviewLabel = "ephemeral-access.argoproj-labs.io/pending-review"
 )
 
 // AccessRequestSpec defines the desired state of AccessRequest
@@ -50,20 +96,138 @@ type AccessRequestSpec struct {
 	// Duration defines the ammount of time that the elevated access
 	// will be granted once approved
 	Duration metav1.Duration `json:"duration"`
+	// StartsAt defines when the access should start being granted. If not
+	// provided or in the past, the access is granted as soon as possible.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	StartsAt *metav1.Time `json:"startsAt,omitempty"`
 	// TargetRoleName defines the role name the user will be assigned
 	// to once the access is approved
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
 	Role TargetRole `json:"role"`
 	// Application defines the Argo CD Application to assign the elevated
-	// permission
-	// +kubebuilder:validation:Required
+	// permission. Must not be provided if Project is defined.
+	// +optional
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
 	Application TargetApplication `json:"application"`
+	// Project defines the Argo CD AppProject to assign the elevated
+	// permission covering all its Applications. The AppProject must live in
+	// the same namespace as the AccessRequest. Must not be provided if
+	// Application is defined.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	Project *TargetAppProject `json:"project,omitempty"`
 	// Subject defines the subject for this access request
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
 	Subject Subject `json:"subject"`
+	// Justification explains why the elevated access is needed
+	// +optional
+	// +kubebuilder:validation:MaxLength=1024
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	Justification string `json:"justification,omitempty"`
+	// TicketRef references the ticket (e.g. incident or change request)
+	// associated with the elevated access
+	// +optional
+	// +kubebuilder:validation:MaxLength=256
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	TicketRef string `json:"ticketRef,omitempty"`
+	// BreakGlass requests emergency access bypassing the approval and the
+	// plugin checks. Only allowed if the RoleTemplate allows break-glass
+	// access. Break-glass AccessRequests must be reviewed afterwards.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	BreakGlass bool `json:"breakGlass,omitempty"`
+	// Review records the post-hoc review of a break-glass AccessRequest
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	Review *Review `json:"review,omitempty"`
+	// Approval defines the decision made by an approver about this access
+	// request. It is only evaluated if the associated RoleTemplate requires
+	// manual approval.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	Approval *Approval `json:"approval,omitempty"`
+	// Revocation signals the controller to revoke this access request
+	// before its natural expiration.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Value is immutable"
+	Revocation *Revocation `json:"revocation,omitempty"`
+	// Extensions is the list of requests to extend the duration of a granted
+	// access. New extensions can only be appended to the list.
+	// +optional
+	// +kubebuilder:validation:MaxItems=100
+	// +kubebuilder:validation:XValidation:rule="size(self) >= size(oldSelf)",message="Extensions can only be appended"
+	Extensions []Extension `json:"extensions,omitempty"`
+}
+
+// Extension defines a request to extend the duration of a granted access
+type Extension struct {
+	// Duration is the additional amount of time requested
+	// +kubebuilder:validation:Required
+	Duration metav1.Duration `json:"duration"`
+	// Requester is the username of who requested the extension
+	// +kubebuilder:validation:Required
+	Requester string `json:"requester"`
+	// RequestedAt is the time the extension was requested
+	RequestedAt metav1.Time `json:"requestedAt"`
+}
+
+// Revocation defines the details about an early revocation
+type Revocation struct {
+	// Revoker is the username of who requested the revocation
+	// +kubebuilder:validation:Required
+	Revoker string `json:"revoker"`
+	// Reason is an optional explanation provided by the revoker
+	// +kubebuilder:validation:MaxLength=1024
+	Reason string `json:"reason,omitempty"`
+	// RevokedAt is the time the revocation was requested
+	RevokedAt metav1.Time `json:"revokedAt"`
+}
+
+// Review defines the details about the post-hoc review of a break-glass
+// AccessRequest
+type Review struct {
+	// Reviewer is the username of who reviewed the AccessRequest
+	// +kubebuilder:validation:Required
+	Reviewer string `json:"reviewer"`
+	// Comment is an optional note provided by the reviewer
+	// +kubebuilder:validation:MaxLength=1024
+	Comment string `json:"comment,omitempty"`
+	// ReviewedAt is the time the review was recorded
+	ReviewedAt metav1.Time `json:"reviewedAt"`
+}
+
+// ApprovalDecision defines the possible decisions an approver can make
+// +kubebuilder:validation:Enum=approved;denied
+type ApprovalDecision string
+
+const (
+	// ApprovedDecision is used when the approver approves the access request
+	ApprovedDecision ApprovalDecision = "approved"
+
+	// DeniedDecision is used when the approver denies the access request
+	DeniedDecision ApprovalDecision = "denied"
+)
+
+// Approval defines the details about the decision made by an approver
+type Approval struct {
+	// Decision is the decision made by the approver
+	// +kubebuilder:validation:Required
+	Decision ApprovalDecision `json:"decision"`
+	// Approver is the username of who made the decision
+	// +kubebuilder:validation:Required
+	Approver string `json:"approver"`
+	// ApproverGroups are the group claims of the approver when the decision
+	// was made. Used by the controller to prevent members of the elevated
+	// group from approving the access.
+	ApproverGroups []string `json:"approverGroups,omitempty"`
+	// Reason is an optional explanation provided by the approver
+	// +kubebuilder:validation:MaxLength=1024
+	Reason string `json:"reason,omitempty"`
+	// DecidedAt is the time the decision was made
+	DecidedAt metav1.Time `json:"decidedAt"`
 }
 
 // TargetApplication defines the Argo CD AppProject to assign the elevated permission
@@ -74,6 +238,14 @@ type TargetApplication struct {
 	Namespace string `json:"namespace"`
 }
 
+// TargetAppProject defines the Argo CD AppProject to assign the elevated
+// permission
+type TargetAppProject struct {
+	// Name refers to the Argo CD AppProject name
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+}
+
 // TargetRole defines the role that is requested
 type TargetRole struct {
 	// TemplateName defines the role template the user will be assigned
@@ -100,6 +272,25 @@ type TargetRoleTemplate struct {
 type Subject struct {
 	// Username refers to the entity requesting the elevated permission
 	Username string `json:"username"`
+	// Group, if provided, is the group that will be granted the elevated
+	// permission as a whole instead of the requesting user
+	// +optional
+	Group string `json:"group,omitempty"`
+	// Groups are the group claims of the requesting user when the
+	// AccessRequest was created. They are used by the controller to verify
+	// that an AccessBinding authorizes the request.
+	// +optional
+	Groups []string `json:"groups,omitempty"`
+}
+
+// Principal returns the value associated with the Argo CD AppProject role
+// groups when the access is granted: the group if provided, otherwise the
+// username.
+func (s Subject) Principal() string {
+	if s.Group != "" {
+		return s.Group
+	}
+	return s.Username
 }
 
 // AccessRequestStatus defines the observed state of AccessRequest
@@ -110,6 +301,24 @@ type AccessRequestStatus struct {
 	RoleTemplateHash string                 `json:"roleTemplateHash,omitempty"`
 	RoleName         string                 `json:"roleName,omitempty"`
 	History          []AccessRequestHistory `json:"history,omitempty"`
+	// AppliedExtensions is the number of items in .spec.extensions already
+	// processed by the controller
+	AppliedExtensions int `json:"appliedExtensions,omitempty"`
+	// ExtendedDuration is the sum of the durations of the extensions
+	// granted by the controller. Rejected extensions are not accounted for.
+	// +optional
+	ExtendedDuration *metav1.Duration `json:"extendedDuration,omitempty"`
+	// ObservedGeneration is the .metadata.generation last processed by
+	// the controller
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Conditions represent the latest available observations of the
+	// AccessRequest state
+	// +listType=map
+	// +listMapKey=type
+	// +patchStrategy=merge
+	// +patchMergeKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
 }
 
 // AccessRequestHistory contain the history of all status transitions associated
@@ -121,6 +330,9 @@ type AccessRequestHistory struct {
 	RequestState Status `json:"status"`
 	// Details may contain detailed information about the transition
 	Details *string `json:"details,omitempty"`
+	// Actor is the identity of the user responsible for the transition
+	// (e.g. the approver)
+	Actor *string `json:"actor,omitempty"`
 }
 
 func (h AccessRequestHistory) String() string {
@@ -128,7 +340,11 @@ func (h AccessRequestHistory) String() string {
 	if h.Details != nil {
 		details = *h.Details
 	}
-	return fmt.Sprintf("{TransitionTime: %s, RequestState: %s, Details: %s }", h.TransitionTime.String(), h.RequestState, details)
+	actor := ""
+	if h.Actor != nil {
+		actor = *h.Actor
+	}
+	return fmt.Sprintf("{TransitionTime: %s, RequestState: %s, Details: %s, Actor: %s }", h.TransitionTime.String(), h.RequestState, details, actor)
 }
 
 // AccessRequest is the Schema for the accessrequests API
@@ -148,6 +364,12 @@ type AccessRequest struct {
 // objects provided by this package. If any additional dependency is needed
 // than this function should be moved to another package.
 func (ar *AccessRequest) UpdateStatusHistory(newStatus Status, details string) {
+	ar.UpdateStatusHistoryWithActor(newStatus, details, "")
+}
+
+// UpdateStatusHistoryWithActor behaves like UpdateStatusHistory and also
+// records the given actor in the history entry if provided.
+func (ar *AccessRequest) UpdateStatusHistoryWithActor(newStatus Status, details, actor string) {
 	status := ar.Status.DeepCopy()
 	status.RequestState = newStatus
 
@@ -161,13 +383,51 @@ func (ar *AccessRequest) UpdateStatusHistory(newStatus Status, details string) {
 	if details != "" {
 		detailsPtr = &details
 	}
+	var actorPtr *string
+	if actor != "" {
+		actorPtr = &actor
+	}
 	history := AccessRequestHistory{
 		TransitionTime: metav1.Now(),
 		RequestState:   newStatus,
 		Details:        detailsPtr,
+		Actor:          actorPtr,
 	}
 	status.History = append(status.History, history)
 	ar.Status = *status
+
+	grantedStatus := metav1.ConditionFalse
+	if newStatus == GrantedStatus {
+		grantedStatus = metav1.ConditionTrue
+	}
+	msg := fmt.Sprintf("AccessRequest %s", newStatus)
+	if details != "" {
+		msg = fmt.Sprintf("%s: %s", msg, details)
+	}
+	ar.SetCondition(ConditionGranted, grantedStatus, newStatus.ConditionReason(), msg)
+}
+
+// SetCondition will add or update the condition with the given type in
+// this AccessRequest status. The condition observedGeneration is set with
+// the current AccessRequest generation. It returns true if the condition
+// changed.
+func (ar *AccessRequest) SetCondition(conditionType string, status metav1.ConditionStatus, reason, message string) bool {
+	return meta.SetStatusCondition(&ar.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		ObservedGeneration: ar.GetGeneration(),
+		Reason:             reason,
+		Message:            message,
+	})
+}
+
+// ConditionReason returns the CamelCase representation of this status
+// to be used as condition reason (e.g. "granted" returns "Granted").
+func (s Status) ConditionReason() string {
+	if s == "" {
+		return "Unknown"
+	}
+	return strings.ToUpper(string(s[:1])) + string(s[1:])
 }
 
 // IsExpiring will return true if this AccessRequest is expired by
@@ -180,6 +440,67 @@ func (ar *AccessRequest) IsExpiring() bool {
 	return false
 }
 
+// GrantedAt returns the time this AccessRequest was first granted. It
+// returns nil if the access was never granted.
+func (ar *AccessRequest) GrantedAt() *metav1.Time {
+	for _, h := range ar.Status.History {
+		if h.RequestState == GrantedStatus {
+			t := h.TransitionTime
+			return &t
+		}
+	}
+	return nil
+}
+
+// GrantedDuration returns the duration requested for this AccessRequest
+// added to the duration of the extensions already granted by the
+// controller. Rejected extensions are not accounted for. This is the
+// duration verified against the RoleTemplate maximum extended duration
+// when extensions are applied.
+func (ar *AccessRequest) GrantedDuration() time.Duration {
+	total := ar.Spec.Duration.Duration
+	if ar.Status.ExtendedDuration != nil {
+		total += ar.Status.ExtendedDuration.Duration
+	}
+	return total
+}
+
+// PendingExtensionsDuration returns the sum of the durations of the
+// extensions not processed by the controller yet.
+func (ar *AccessRequest) PendingExtensionsDuration() time.Duration {
+	var total time.Duration
+	for i := ar.Status.AppliedExtensions; i < len(ar.Spec.Extensions); i++ {
+		total += ar.Spec.Extensions[i].Duration.Duration
+	}
+	return total
+}
+
+// IsScheduled will return true if this AccessRequest defines a start time
+// in the future by verifying the .spec.startsAt field. Otherwise it returns
+// false.
+func (ar *AccessRequest) IsScheduled() bool {
+	return ar.Spec.StartsAt != nil && ar.Spec.StartsAt.Time.After(time.Now())
+}
+
+// IsProjectScoped will return true if this AccessRequest targets all the
+// Applications in an AppProject by verifying the .spec.project field.
+// Otherwise it returns false.
+func (ar *AccessRequest) IsProjectScoped() bool {
+	return ar.Spec.Project != nil
+}
+
+// IsRevoking will return true if the revocation of this AccessRequest was
+// requested by verifying the .spec.revocation field. Otherwise it returns false.
+func (ar *AccessRequest) IsRevoking() bool {
+	return ar.Spec.Revocation != nil
+}
+
+// IsPendingReview will return true if this AccessRequest is a break-glass
+// request that wasn't reviewed yet. Otherwise it returns false.
+func (ar *AccessRequest) IsPendingReview() bool {
+	return ar.Spec.BreakGlass && ar.Spec.Review == nil
+}
+
 // AccessRequestList contains a list of AccessRequest
 // +kubebuilder:object:root=true
 type AccessRequestList struct {
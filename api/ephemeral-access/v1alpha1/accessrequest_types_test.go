@@ -0,0 +1,122 @@
+package v1alpha1_test
+
+import (
+	"testing"
+	"time"
+
+	api "github.com/argoproj-labs/ephemeral-access/api/ephemeral-access/v1alpha1"
+	"github.com/argoproj-labs/ephemeral-access/test/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAccessRequest_UpdateStatusHistory(t *testing.T) {
+	t.Run("will set Granted condition as true when granted", func(t *testing.T) {
+		// Given
+		ar := utils.NewAccessRequestRequested()
+		ar.SetGeneration(3)
+
+		// When
+		ar.UpdateStatusHistory(api.GrantedStatus, "")
+
+		// Then
+		cond := meta.FindStatusCondition(ar.Status.Conditions, api.ConditionGranted)
+		require.NotNil(t, cond)
+		assert.Equal(t, metav1.ConditionTrue, cond.Status)
+		assert.Equal(t, "Granted", cond.Reason)
+		assert.Equal(t, "AccessRequest granted", cond.Message)
+		assert.Equal(t, int64(3), cond.ObservedGeneration)
+	})
+	t.Run("will set Granted condition as false when expired", func(t *testing.T) {
+		// Given
+		ar := utils.NewAccessRequestGranted()
+		ar.UpdateStatusHistory(api.GrantedStatus, "")
+
+		// When
+		ar.UpdateStatusHistory(api.ExpiredStatus, "some details")
+
+		// Then
+		cond := meta.FindStatusCondition(ar.Status.Conditions, api.ConditionGranted)
+		require.NotNil(t, cond)
+		assert.Equal(t, metav1.ConditionFalse, cond.Status)
+		assert.Equal(t, "Expired", cond.Reason)
+		assert.Equal(t, "AccessRequest expired: some details", cond.Message)
+		assert.Len(t, ar.Status.Conditions, 1)
+	})
+}
+
+func TestAccessRequest_SetCondition(t *testing.T) {
+	t.Run("will only report changes when the condition is modified", func(t *testing.T) {
+		// Given
+		ar := utils.NewAccessRequestRequested()
+
+		// When
+		added := ar.SetCondition(api.ConditionReady, metav1.ConditionTrue, "Reconciled", "ok")
+		unchanged := ar.SetCondition(api.ConditionReady, metav1.ConditionTrue, "Reconciled", "ok")
+		updated := ar.SetCondition(api.ConditionReady, metav1.ConditionFalse, "Error", "not ok")
+
+		// Then
+		assert.True(t, added)
+		assert.False(t, unchanged)
+		assert.True(t, updated)
+		assert.Len(t, ar.Status.Conditions, 1)
+		assert.Equal(t, metav1.ConditionFalse, ar.Status.Conditions[0].Status)
+	})
+}
+
+func TestAccessRequest_IsScheduled(t *testing.T) {
+	t.Run("will return true only if startsAt is in the future", func(t *testing.T) {
+		ar := utils.NewAccessRequestCreated()
+		assert.False(t, ar.IsScheduled())
+
+		ar.Spec.StartsAt = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+		assert.False(t, ar.IsScheduled())
+
+		ar.Spec.StartsAt = &metav1.Time{Time: time.Now().Add(time.Minute)}
+		assert.True(t, ar.IsScheduled())
+	})
+}
+
+func TestAccessRequest_GrantedDuration(t *testing.T) {
+	t.Run("will return the requested duration if no extension was granted", func(t *testing.T) {
+		ar := utils.NewAccessRequestCreated()
+		ar.Spec.Duration = metav1.Duration{Duration: time.Hour}
+		ar.Spec.Extensions = []api.Extension{{Duration: metav1.Duration{Duration: 30 * time.Minute}}}
+
+		assert.Equal(t, time.Hour, ar.GrantedDuration())
+	})
+	t.Run("will add the duration of the granted extensions", func(t *testing.T) {
+		ar := utils.NewAccessRequestCreated()
+		ar.Spec.Duration = metav1.Duration{Duration: time.Hour}
+		ar.Status.ExtendedDuration = &metav1.Duration{Duration: 15 * time.Minute}
+
+		assert.Equal(t, 75*time.Minute, ar.GrantedDuration())
+	})
+}
+
+func TestAccessRequest_PendingExtensionsDuration(t *testing.T) {
+	t.Run("will add the duration of the extensions not processed yet", func(t *testing.T) {
+		ar := utils.NewAccessRequestCreated()
+		ar.Spec.Extensions = []api.Extension{
+			{Duration: metav1.Duration{Duration: 30 * time.Minute}},
+			{Duration: metav1.Duration{Duration: 15 * time.Minute}},
+			{Duration: metav1.Duration{Duration: 5 * time.Minute}},
+		}
+		ar.Status.AppliedExtensions = 1
+
+		assert.Equal(t, 20*time.Minute, ar.PendingExtensionsDuration())
+	})
+}
+
+func TestSubject_Principal(t *testing.T) {
+	t.Run("will return the username if group is not provided", func(t *testing.T) {
+		subject := api.Subject{Username: "some-user"}
+		assert.Equal(t, "some-user", subject.Principal())
+	})
+	t.Run("will return the group if provided", func(t *testing.T) {
+		subject := api.Subject{Username: "some-user", Group: "on-call"}
+		assert.Equal(t, "on-call", subject.Principal())
+	})
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// maxChainedWindows limits how many consecutive allowed windows are merged
+// when calculating until when the access is allowed.
+const maxChainedWindows = 10000
+
+// ErrOutsideAccessWindow is returned when the access can not be granted at
+// the evaluated time due to the RoleTemplate access windows.
+var ErrOutsideAccessWindow = errors.New("outside of the allowed access windows")
+
+// AccessWindows defines when the access for a role can be granted
+type AccessWindows struct {
+	// TimeZone is the IANA time zone name used to evaluate the allowed
+	// windows schedules (e.g. "America/Toronto"). Defaults to UTC.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+	// Allowed is the list of recurring windows during which the access can
+	// be granted. If empty, the access can be granted at any time outside
+	// of the blackouts.
+	// +optional
+	Allowed []AllowedWindow `json:"allowed,omitempty"`
+	// Blackouts is the list of date ranges during which the access can not
+	// be granted (e.g. change freezes).
+	// +optional
+	Blackouts []BlackoutWindow `json:"blackouts,omitempty"`
+}
+
+// AllowedWindow defines a recurring window during which the access can be
+// granted
+type AllowedWindow struct {
+	// Schedule is a standard cron expression defining when the window opens
+	// (e.g. "0 9 * * 1-5" for weekdays at 9am)
+	// +kubebuilder:validation:Required
+	Schedule string `json:"schedule"`
+	// Duration defines for how long the window remains open after the
+	// schedule is triggered (e.g. "8h")
+	// +kubebuilder:validation:Required
+	Duration metav1.Duration `json:"duration"`
+}
+
+// BlackoutWindow defines a date range during which the access can not be
+// granted
+type BlackoutWindow struct {
+	// Start is the time the blackout starts
+	// +kubebuilder:validation:Required
+	Start metav1.Time `json:"start"`
+	// End is the time the blackout ends
+	// +kubebuilder:validation:Required
+	End metav1.Time `json:"end"`
+	// Reason is an optional description of the blackout
+	// +kubebuilder:validation:MaxLength=1024
+	Reason string `json:"reason,omitempty"`
+}
+
+// allowedSchedule is a parsed AllowedWindow
+type allowedSchedule struct {
+	schedule cron.Schedule
+	duration time.Duration
+}
+
+// Validate will verify if the time zone, the schedules and the blackouts
+// are valid.
+func (w *AccessWindows) Validate() error {
+	_, err := w.location()
+	if err != nil {
+		return err
+	}
+	_, err = w.schedules()
+	if err != nil {
+		return err
+	}
+	for i, b := range w.Blackouts {
+		if !b.End.Time.After(b.Start.Time) {
+			return fmt.Errorf("blackout %d end must be after its start", i)
+		}
+	}
+	return nil
+}
+
+// AllowedUntil verifies if the access can be granted at the given time t
+// and returns until when it remains allowed, never exceeding the given
+// limit. Consecutive or overlapping allowed windows are considered as a
+// single window. Returns an error wrapping ErrOutsideAccessWindow if the
+// access is not allowed at t.
+func (w *AccessWindows) AllowedUntil(t, limit time.Time) (time.Time, error) {
+	loc, err := w.location()
+	if err != nil {
+		return time.Time{}, err
+	}
+	schedules, err := w.schedules()
+	if err != nil {
+		return time.Time{}, err
+	}
+	t = t.In(loc)
+
+	until := limit
+	for _, b := range w.Blackouts {
+		if !t.Before(b.Start.Time) && t.Before(b.End.Time) {
+			msg := fmt.Sprintf("blackout until %s", b.End.Time.Format(time.RFC3339))
+			if b.Reason != "" {
+				msg = fmt.Sprintf("%s: %s", msg, b.Reason)
+			}
+			return time.Time{}, fmt.Errorf("%w: %s", ErrOutsideAccessWindow, msg)
+		}
+		if b.Start.Time.After(t) && b.Start.Time.Before(until) {
+			until = b.Start.Time
+		}
+	}
+	if len(schedules) == 0 {
+		return until, nil
+	}
+
+	end, ok := windowEnd(schedules, t)
+	if !ok {
+		return time.Time{}, fmt.Errorf("%w: no allowed window is open at %s", ErrOutsideAccessWindow, t.Format(time.RFC3339))
+	}
+	for i := 0; i < maxChainedWindows && end.Before(until); i++ {
+		next, ok := windowEnd(schedules, end)
+		if !ok || !next.After(end) {
+			break
+		}
+		end = next
+	}
+	if end.Before(until) {
+		until = end
+	}
+	return until, nil
+}
+
+// windowEnd returns the latest end time of the allowed windows open at the
+// given time t. Returns false if no window is open at t.
+func windowEnd(schedules []allowedSchedule, t time.Time) (time.Time, bool) {
+	var end time.Time
+	for _, s := range schedules {
+		// the last schedule trigger before t defines the window end
+		var last time.Time
+		for n := s.schedule.Next(t.Add(-s.duration)); !n.IsZero() && !n.After(t); n = s.schedule.Next(n) {
+			last = n
+		}
+		if last.IsZero() {
+			continue
+		}
+		if e := last.Add(s.duration); e.After(end) {
+			end = e
+		}
+	}
+	return end, !end.IsZero()
+}
+
+func (w *AccessWindows) location() (*time.Location, error) {
+	if w.TimeZone == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(w.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone %q: %w", w.TimeZone, err)
+	}
+	return loc, nil
+}
+
+func (w *AccessWindows) schedules() ([]allowedSchedule, error) {
+	schedules := []allowedSchedule{}
+	for i, a := range w.Allowed {
+		schedule, err := cron.ParseStandard(a.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowed window %d schedule %q: %w", i, a.Schedule, err)
+		}
+		if a.Duration.Duration <= 0 {
+			return nil, fmt.Errorf("allowed window %d duration must be positive", i)
+		}
+		schedules = append(schedules, allowedSchedule{schedule: schedule, duration: a.Duration.Duration})
+	}
+	return schedules, nil
+}
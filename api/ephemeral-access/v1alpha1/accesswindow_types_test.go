@@ -0,0 +1,169 @@
+package v1alpha1_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/argoproj-labs/ephemeral-access/api/ephemeral-access/v1alpha1"
+)
+
+func TestAccessWindows_Validate(t *testing.T) {
+	start := time.Date(2024, 12, 20, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		windows     api.AccessWindows
+		expectedErr string
+	}{
+		{
+			name: "valid windows",
+			windows: api.AccessWindows{
+				TimeZone:  "America/Toronto",
+				Allowed:   []api.AllowedWindow{{Schedule: "0 9 * * 1-5", Duration: metav1.Duration{Duration: 8 * time.Hour}}},
+				Blackouts: []api.BlackoutWindow{{Start: metav1.NewTime(start), End: metav1.NewTime(start.Add(24 * time.Hour))}},
+			},
+		},
+		{
+			name:        "invalid time zone",
+			windows:     api.AccessWindows{TimeZone: "Mars/Olympus"},
+			expectedErr: "invalid time zone",
+		},
+		{
+			name: "invalid schedule",
+			windows: api.AccessWindows{
+				Allowed: []api.AllowedWindow{{Schedule: "every day", Duration: metav1.Duration{Duration: time.Hour}}},
+			},
+			expectedErr: "invalid allowed window 0 schedule",
+		},
+		{
+			name: "invalid duration",
+			windows: api.AccessWindows{
+				Allowed: []api.AllowedWindow{{Schedule: "0 9 * * *"}},
+			},
+			expectedErr: "allowed window 0 duration must be positive",
+		},
+		{
+			name: "blackout end before start",
+			windows: api.AccessWindows{
+				Blackouts: []api.BlackoutWindow{{Start: metav1.NewTime(start), End: metav1.NewTime(start)}},
+			},
+			expectedErr: "blackout 0 end must be after its start",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.windows.Validate()
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestAccessWindows_AllowedUntil(t *testing.T) {
+	// Monday 2024-12-16 in America/Toronto (UTC-5)
+	loc, err := time.LoadLocation("America/Toronto")
+	require.NoError(t, err)
+	monday := func(hour, min int) time.Time {
+		return time.Date(2024, 12, 16, hour, min, 0, 0, loc)
+	}
+	businessHours := []api.AllowedWindow{{Schedule: "0 9 * * 1-5", Duration: metav1.Duration{Duration: 8 * time.Hour}}}
+	blackout := api.BlackoutWindow{
+		Start:  metav1.NewTime(monday(15, 0)),
+		End:    metav1.NewTime(monday(16, 0)),
+		Reason: "release freeze",
+	}
+
+	tests := []struct {
+		name          string
+		windows       api.AccessWindows
+		at            time.Time
+		limit         time.Time
+		expectedUntil time.Time
+		expectedErr   string
+	}{
+		{
+			name:          "no restrictions returns the limit",
+			windows:       api.AccessWindows{},
+			at:            monday(3, 0),
+			limit:         monday(7, 0),
+			expectedUntil: monday(7, 0),
+		},
+		{
+			name:          "inside allowed window returns the limit",
+			windows:       api.AccessWindows{TimeZone: "America/Toronto", Allowed: businessHours},
+			at:            monday(10, 0),
+			limit:         monday(12, 0),
+			expectedUntil: monday(12, 0),
+		},
+		{
+			name:          "inside allowed window truncates at the window end",
+			windows:       api.AccessWindows{TimeZone: "America/Toronto", Allowed: businessHours},
+			at:            monday(16, 0),
+			limit:         monday(20, 0),
+			expectedUntil: monday(17, 0),
+		},
+		{
+			name:        "outside allowed window is denied",
+			windows:     api.AccessWindows{TimeZone: "America/Toronto", Allowed: businessHours},
+			at:          monday(8, 0),
+			limit:       monday(12, 0),
+			expectedErr: "no allowed window is open",
+		},
+		{
+			// 8am in Toronto is 1pm UTC which is inside the UTC window
+			name:          "defaults to UTC time zone",
+			windows:       api.AccessWindows{Allowed: businessHours},
+			at:            monday(8, 0),
+			limit:         monday(13, 0),
+			expectedUntil: monday(12, 0),
+		},
+		{
+			name: "consecutive windows are merged",
+			windows: api.AccessWindows{Allowed: []api.AllowedWindow{
+				{Schedule: "0 * * * *", Duration: metav1.Duration{Duration: time.Hour}},
+			}},
+			at:            monday(10, 30),
+			limit:         monday(14, 0),
+			expectedUntil: monday(14, 0),
+		},
+		{
+			name:        "inside blackout is denied",
+			windows:     api.AccessWindows{Blackouts: []api.BlackoutWindow{blackout}},
+			at:          monday(15, 30),
+			limit:       monday(17, 0),
+			expectedErr: "release freeze",
+		},
+		{
+			name:          "truncates at the next blackout start",
+			windows:       api.AccessWindows{TimeZone: "America/Toronto", Allowed: businessHours, Blackouts: []api.BlackoutWindow{blackout}},
+			at:            monday(14, 0),
+			limit:         monday(16, 30),
+			expectedUntil: monday(15, 0),
+		},
+		{
+			name:          "access allowed after blackout ends",
+			windows:       api.AccessWindows{Blackouts: []api.BlackoutWindow{blackout}},
+			at:            monday(16, 0),
+			limit:         monday(17, 0),
+			expectedUntil: monday(17, 0),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			until, err := tt.windows.AllowedUntil(tt.at, tt.limit)
+			if tt.expectedErr != "" {
+				assert.ErrorIs(t, err, api.ErrOutsideAccessWindow)
+				assert.ErrorContains(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tt.expectedUntil.Equal(until), "expected %s, got %s", tt.expectedUntil, until)
+		})
+	}
+}
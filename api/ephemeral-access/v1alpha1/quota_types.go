@@ -0,0 +1,197 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ErrQuotaExceeded is returned when an AccessRequest is not allowed by the
+// RoleTemplate quotas.
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// Quotas defines the limits applied to AccessRequests for a role
+type Quotas struct {
+	// User limits the AccessRequests of each requesting user for this role.
+	// AccessRequests are accounted for the Subject.Username even when a
+	// group is granted the access so users can't bypass the quota by
+	// requesting it for their groups.
+	// +optional
+	User *Quota `json:"user,omitempty"`
+	// Application limits the AccessRequests targeting each Application (or
+	// AppProject for project-scoped AccessRequests) for this role
+	// +optional
+	Application *Quota `json:"application,omitempty"`
+	// Role limits all the AccessRequests for this role
+	// +optional
+	Role *Quota `json:"role,omitempty"`
+}
+
+// Quota defines the limits applied to a group of AccessRequests
+type Quota struct {
+	// MaxConcurrent is the maximum number of AccessRequests that can be
+	// requested, scheduled or granted at the same time
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxConcurrent *int32 `json:"maxConcurrent,omitempty"`
+	// MaxRequests is the maximum number of AccessRequests that can be
+	// created within the rolling Period. Period must be provided.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxRequests *int32 `json:"maxRequests,omitempty"`
+	// Period is the rolling time period evaluated by MaxRequests (e.g. "24h")
+	// +optional
+	Period *metav1.Duration `json:"period,omitempty"`
+	// Cooldown is the minimum amount of time after an access expires before
+	// a new AccessRequest is allowed (e.g. "1h")
+	// +optional
+	Cooldown *metav1.Duration `json:"cooldown,omitempty"`
+}
+
+// Validate will verify if the quotas are consistent.
+func (q *Quotas) Validate() error {
+	scopes := []string{"user", "application", "role"}
+	for i, quota := range []*Quota{q.User, q.Application, q.Role} {
+		if quota == nil {
+			continue
+		}
+		scope := scopes[i]
+		if quota.MaxRequests != nil && quota.Period == nil {
+			return fmt.Errorf("%s quota period must be provided with maxRequests", scope)
+		}
+		if quota.Period != nil && quota.Period.Duration <= 0 {
+			return fmt.Errorf("%s quota period must be positive", scope)
+		}
+		if quota.Cooldown != nil && quota.Cooldown.Duration <= 0 {
+			return fmt.Errorf("%s quota cooldown must be positive", scope)
+		}
+	}
+	return nil
+}
+
+// Check verifies if the given ar is allowed by the quotas at the given time
+// considering the existing AccessRequests. AccessRequests for other roles,
+// created after the given time or with the same name as ar are ignored.
+// Returns an error wrapping ErrQuotaExceeded if a quota is exceeded.
+func (q *Quotas) Check(ar *AccessRequest, existing []AccessRequest, at time.Time) error {
+	var user, app, role []*AccessRequest
+	for i := range existing {
+		other := &existing[i]
+		if other.GetName() != "" &&
+			other.GetName() == ar.GetName() &&
+			other.GetNamespace() == ar.GetNamespace() {
+			continue
+		}
+		if other.Spec.Role.TemplateRef != ar.Spec.Role.TemplateRef ||
+			other.GetCreationTimestamp().After(at) {
+			continue
+		}
+		role = append(role, other)
+		if other.Spec.Subject.Username == ar.Spec.Subject.Username {
+			user = append(user, other)
+		}
+		if sameTarget(ar, other) {
+			app = append(app, other)
+		}
+	}
+	err := q.User.check("user", user, at)
+	if err != nil {
+		return err
+	}
+	err = q.Application.check("application", app, at)
+	if err != nil {
+		return err
+	}
+	return q.Role.check("role", role, at)
+}
+
+// check verifies if the quota is exceeded by the given AccessRequests.
+func (q *Quota) check(scope string, ars []*AccessRequest, at time.Time) error {
+	if q == nil {
+		return nil
+	}
+	concurrent := 0
+	requests := 0
+	for _, ar := range ars {
+		// requests in progress may be granted before the quota is evaluated
+		// again so they are accounted for as if they were already granted
+		switch ar.Status.RequestState {
+		case RequestedStatus, ScheduledStatus, GrantedStatus:
+			concurrent++
+		}
+		if q.Period != nil && ar.GetCreationTimestamp().After(at.Add(-q.Period.Duration)) {
+			requests++
+		}
+		if q.Cooldown != nil &&
+			ar.Status.RequestState == ExpiredStatus &&
+			ar.Status.ExpiresAt != nil {
+			end := ar.Status.ExpiresAt.Add(q.Cooldown.Duration)
+			if end.After(at) {
+				return fmt.Errorf("%w: %s cooldown in effect until %s", ErrQuotaExceeded, scope, end.Format(time.RFC3339))
+			}
+		}
+	}
+	if q.MaxConcurrent != nil && concurrent >= int(*q.MaxConcurrent) {
+		return fmt.Errorf("%w: %s quota allows at most %d concurrent requests", ErrQuotaExceeded, scope, *q.MaxConcurrent)
+	}
+	if q.MaxRequests != nil && q.Period != nil && requests >= int(*q.MaxRequests) {
+		return fmt.Errorf("%w: %s quota allows at most %d requests every %s", ErrQuotaExceeded, scope, *q.MaxRequests, q.Period.Duration)
+	}
+	return nil
+}
+
+// RetainUntil returns the time until the given ar is accounted for by the
+// quotas: the end of the rolling periods following its creation and of the
+// cooldowns following its expiration. Returns the zero time if the quotas
+// never account for the ar.
+func (q *Quotas) RetainUntil(ar *AccessRequest) time.Time {
+	var until time.Time
+	for _, quota := range []*Quota{q.User, q.Application, q.Role} {
+		if quota == nil {
+			continue
+		}
+		if quota.Period != nil {
+			end := ar.GetCreationTimestamp().Add(quota.Period.Duration)
+			if end.After(until) {
+				until = end
+			}
+		}
+		if quota.Cooldown != nil &&
+			ar.Status.RequestState == ExpiredStatus &&
+			ar.Status.ExpiresAt != nil {
+			end := ar.Status.ExpiresAt.Add(quota.Cooldown.Duration)
+			if end.After(until) {
+				until = end
+			}
+		}
+	}
+	return until
+}
+
+// sameTarget returns true if both AccessRequests target the same
+// Application or the same AppProject.
+func sameTarget(a, b *AccessRequest) bool {
+	if a.IsProjectScoped() || b.IsProjectScoped() {
+		return a.IsProjectScoped() && b.IsProjectScoped() &&
+			a.Spec.Project.Name == b.Spec.Project.Name
+	}
+	return a.Spec.Application == b.Spec.Application
+}
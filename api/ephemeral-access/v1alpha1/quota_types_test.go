@@ -0,0 +1,255 @@
+package v1alpha1_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	api "github.com/argoproj-labs/ephemeral-access/api/ephemeral-access/v1alpha1"
+)
+
+func TestQuotas_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		quotas      api.Quotas
+		expectedErr string
+	}{
+		{
+			name: "valid quotas",
+			quotas: api.Quotas{
+				User: &api.Quota{
+					MaxConcurrent: ptr.To(int32(1)),
+					MaxRequests:   ptr.To(int32(3)),
+					Period:        &metav1.Duration{Duration: 24 * time.Hour},
+					Cooldown:      &metav1.Duration{Duration: time.Hour},
+				},
+				Role: &api.Quota{MaxConcurrent: ptr.To(int32(5))},
+			},
+		},
+		{
+			name:        "max requests without period",
+			quotas:      api.Quotas{Application: &api.Quota{MaxRequests: ptr.To(int32(3))}},
+			expectedErr: "application quota period must be provided with maxRequests",
+		},
+		{
+			name:        "negative period",
+			quotas:      api.Quotas{User: &api.Quota{Period: &metav1.Duration{Duration: -time.Hour}}},
+			expectedErr: "user quota period must be positive",
+		},
+		{
+			name:        "zero cooldown",
+			quotas:      api.Quotas{Role: &api.Quota{Cooldown: &metav1.Duration{}}},
+			expectedErr: "role quota cooldown must be positive",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.quotas.Validate()
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestQuotas_Check(t *testing.T) {
+	now := time.Date(2024, 12, 20, 12, 0, 0, 0, time.UTC)
+	newAR := func(name, user, app string, status api.Status, createdAt time.Time) api.AccessRequest {
+		ar := api.AccessRequest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "ephemeral",
+				CreationTimestamp: metav1.NewTime(createdAt),
+			},
+			Spec: api.AccessRequestSpec{
+				Role: api.TargetRole{
+					TemplateRef: api.TargetRoleTemplate{Name: "devops", Namespace: "ephemeral"},
+				},
+				Application: api.TargetApplication{Name: app, Namespace: "argocd"},
+				Subject:     api.Subject{Username: user},
+			},
+		}
+		ar.Status.RequestState = status
+		return ar
+	}
+	expired := func(ar api.AccessRequest, expiresAt time.Time) api.AccessRequest {
+		ar.Status.ExpiresAt = &metav1.Time{Time: expiresAt}
+		return ar
+	}
+	otherRole := newAR("other-role", "alice", "app1", api.GrantedStatus, now.Add(-time.Minute))
+	otherRole.Spec.Role.TemplateRef.Name = "admin"
+
+	tests := []struct {
+		name        string
+		quotas      api.Quotas
+		existing    []api.AccessRequest
+		expectedErr string
+	}{
+		{
+			name:   "allowed without existing requests",
+			quotas: api.Quotas{User: &api.Quota{MaxConcurrent: ptr.To(int32(1))}},
+		},
+		{
+			name:   "concurrent user quota exceeded",
+			quotas: api.Quotas{User: &api.Quota{MaxConcurrent: ptr.To(int32(1))}},
+			existing: []api.AccessRequest{
+				newAR("granted", "alice", "app2", api.GrantedStatus, now.Add(-time.Hour)),
+			},
+			expectedErr: "user quota allows at most 1 concurrent requests",
+		},
+		{
+			name:   "concurrent user quota accounts for requests in progress",
+			quotas: api.Quotas{User: &api.Quota{MaxConcurrent: ptr.To(int32(2))}},
+			existing: []api.AccessRequest{
+				newAR("requested", "alice", "app2", api.RequestedStatus, now.Add(-time.Hour)),
+				newAR("scheduled", "alice", "app3", api.ScheduledStatus, now.Add(-time.Hour)),
+			},
+			expectedErr: "user quota allows at most 2 concurrent requests",
+		},
+		{
+			name:   "concurrent user quota ignores concluded requests",
+			quotas: api.Quotas{User: &api.Quota{MaxConcurrent: ptr.To(int32(1))}},
+			existing: []api.AccessRequest{
+				newAR("denied", "alice", "app2", api.DeniedStatus, now.Add(-time.Hour)),
+				newAR("expired", "alice", "app2", api.ExpiredStatus, now.Add(-time.Hour)),
+				newAR("revoked", "alice", "app2", api.RevokedStatus, now.Add(-time.Hour)),
+			},
+		},
+		{
+			name:   "concurrent user quota accounts for group requests of the user",
+			quotas: api.Quotas{User: &api.Quota{MaxConcurrent: ptr.To(int32(1))}},
+			existing: []api.AccessRequest{
+				func() api.AccessRequest {
+					ar := newAR("group", "alice", "app2", api.GrantedStatus, now.Add(-time.Hour))
+					ar.Spec.Subject.Group = "on-call"
+					return ar
+				}(),
+			},
+			expectedErr: "user quota allows at most 1 concurrent requests",
+		},
+		{
+			name:   "concurrent user quota ignores other users and roles",
+			quotas: api.Quotas{User: &api.Quota{MaxConcurrent: ptr.To(int32(1))}},
+			existing: []api.AccessRequest{
+				newAR("granted", "bob", "app1", api.GrantedStatus, now.Add(-time.Hour)),
+				otherRole,
+			},
+		},
+		{
+			name:   "concurrent application quota exceeded",
+			quotas: api.Quotas{Application: &api.Quota{MaxConcurrent: ptr.To(int32(2))}},
+			existing: []api.AccessRequest{
+				newAR("granted-1", "bob", "app1", api.GrantedStatus, now.Add(-time.Hour)),
+				newAR("granted-2", "carol", "app1", api.GrantedStatus, now.Add(-time.Hour)),
+				newAR("granted-3", "dave", "app2", api.GrantedStatus, now.Add(-time.Hour)),
+			},
+			expectedErr: "application quota allows at most 2 concurrent requests",
+		},
+		{
+			name:   "periodic role quota exceeded",
+			quotas: api.Quotas{Role: &api.Quota{MaxRequests: ptr.To(int32(2)), Period: &metav1.Duration{Duration: 24 * time.Hour}}},
+			existing: []api.AccessRequest{
+				newAR("denied", "bob", "app2", api.DeniedStatus, now.Add(-time.Hour)),
+				newAR("expired", "carol", "app3", api.ExpiredStatus, now.Add(-2*time.Hour)),
+			},
+			expectedErr: "role quota allows at most 2 requests every 24h0m0s",
+		},
+		{
+			name:   "periodic quota ignores requests outside the period",
+			quotas: api.Quotas{User: &api.Quota{MaxRequests: ptr.To(int32(1)), Period: &metav1.Duration{Duration: time.Hour}}},
+			existing: []api.AccessRequest{
+				newAR("old", "alice", "app1", api.ExpiredStatus, now.Add(-2*time.Hour)),
+				newAR("newer", "alice", "app1", api.RequestedStatus, now.Add(time.Minute)),
+			},
+		},
+		{
+			name:   "cooldown in effect",
+			quotas: api.Quotas{User: &api.Quota{Cooldown: &metav1.Duration{Duration: time.Hour}}},
+			existing: []api.AccessRequest{
+				expired(newAR("expired", "alice", "app1", api.ExpiredStatus, now.Add(-2*time.Hour)), now.Add(-30*time.Minute)),
+			},
+			expectedErr: "user cooldown in effect until 2024-12-20T12:30:00Z",
+		},
+		{
+			name:   "cooldown elapsed",
+			quotas: api.Quotas{User: &api.Quota{Cooldown: &metav1.Duration{Duration: time.Hour}}},
+			existing: []api.AccessRequest{
+				expired(newAR("expired", "alice", "app1", api.ExpiredStatus, now.Add(-3*time.Hour)), now.Add(-2*time.Hour)),
+			},
+		},
+		{
+			name:   "ignores the evaluated request",
+			quotas: api.Quotas{User: &api.Quota{MaxConcurrent: ptr.To(int32(1))}},
+			existing: []api.AccessRequest{
+				newAR("current", "alice", "app1", api.GrantedStatus, now.Add(-time.Hour)),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			ar := newAR("current", "alice", "app1", "", now)
+
+			// When
+			err := tt.quotas.Check(&ar, tt.existing, now)
+
+			// Then
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, api.ErrQuotaExceeded)
+			assert.ErrorContains(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestQuotas_RetainUntil(t *testing.T) {
+	createdAt := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	newAR := func(state api.Status) *api.AccessRequest {
+		ar := &api.AccessRequest{}
+		ar.SetCreationTimestamp(metav1.NewTime(createdAt))
+		ar.Status.RequestState = state
+		ar.Status.ExpiresAt = &metav1.Time{Time: createdAt.Add(time.Hour)}
+		return ar
+	}
+	tests := []struct {
+		name     string
+		quotas   api.Quotas
+		ar       *api.AccessRequest
+		expected time.Time
+	}{
+		{
+			name:   "no time based quotas",
+			quotas: api.Quotas{Role: &api.Quota{MaxConcurrent: ptr.To(int32(1))}},
+			ar:     newAR(api.ExpiredStatus),
+		},
+		{
+			name:     "longest period",
+			quotas:   api.Quotas{User: &api.Quota{Period: &metav1.Duration{Duration: time.Hour}}, Role: &api.Quota{Period: &metav1.Duration{Duration: 24 * time.Hour}}},
+			ar:       newAR(api.DeniedStatus),
+			expected: createdAt.Add(24 * time.Hour),
+		},
+		{
+			name:     "cooldown after expiration",
+			quotas:   api.Quotas{Application: &api.Quota{Period: &metav1.Duration{Duration: time.Hour}, Cooldown: &metav1.Duration{Duration: 2 * time.Hour}}},
+			ar:       newAR(api.ExpiredStatus),
+			expected: createdAt.Add(3 * time.Hour),
+		},
+		{
+			name:   "cooldown ignored if not expired",
+			quotas: api.Quotas{User: &api.Quota{Cooldown: &metav1.Duration{Duration: 2 * time.Hour}}},
+			ar:     newAR(api.RevokedStatus),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.quotas.RetainUntil(tt.ar))
+		})
+	}
+}
@@ -18,8 +18,11 @@ package v1alpha1
 
 import (
 	"fmt"
+	"regexp"
+	"slices"
 	"strings"
 	"text/template"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -48,6 +51,79 @@ type RoleTemplateSpec struct {
 	Name        string   `json:"name"`
 	Description string   `json:"description,omitempty"`
 	Policies    []string `json:"policies"`
+	// Approval defines if AccessRequests for this role require manual
+	// approval before being granted
+	// +optional
+	Approval *ApprovalSpec `json:"approval,omitempty"`
+	// Revokers is a list of usernames or groups allowed to revoke
+	// AccessRequests for this role, regardless of the approval settings.
+	// The requester and the approvers are always allowed to revoke.
+	// +optional
+	Revokers []string `json:"revokers,omitempty"`
+	// DefaultDuration defines the access duration for this role when the
+	// requester doesn't provide one. If not defined, the backend default
+	// duration is used.
+	// +optional
+	DefaultDuration *metav1.Duration `json:"defaultDuration,omitempty"`
+	// MaxDuration defines the maximum amount of time the access can be
+	// requested for this role.
+	// +optional
+	MaxDuration *metav1.Duration `json:"maxDuration,omitempty"`
+	// MaxExtendedDuration defines the maximum total amount of time the
+	// access can be granted for this role including extensions.
+	// AccessRequests can only be extended if this field is defined. It must
+	// not be lower than MaxDuration.
+	// +optional
+	MaxExtendedDuration *metav1.Duration `json:"maxExtendedDuration,omitempty"`
+	// AccessWindows restricts when the access for this role can be granted.
+	// Granted access never extends past the end of the current window.
+	// +optional
+	AccessWindows *AccessWindows `json:"accessWindows,omitempty"`
+	// Justification defines if AccessRequests for this role must explain why
+	// the access is needed
+	// +optional
+	Justification *JustificationSpec `json:"justification,omitempty"`
+	// Quotas limits how many AccessRequests can be granted concurrently or
+	// created within a time period for this role
+	// +optional
+	Quotas *Quotas `json:"quotas,omitempty"`
+	// BreakGlass allows emergency AccessRequests for this role bypassing the
+	// approval and the plugin checks. Break-glass AccessRequests are only
+	// allowed if this field is defined.
+	// +optional
+	BreakGlass *BreakGlassSpec `json:"breakGlass,omitempty"`
+}
+
+// ApprovalSpec defines who is allowed to approve AccessRequests
+type ApprovalSpec struct {
+	// Approvers is a list of usernames or groups allowed to approve or deny
+	// AccessRequests for this role
+	// +kubebuilder:validation:MinItems=1
+	Approvers []string `json:"approvers"`
+}
+
+// JustificationSpec defines the justification requirements for
+// AccessRequests
+type JustificationSpec struct {
+	// Required defines if AccessRequests for this role must provide both a
+	// justification and a ticket reference
+	// +optional
+	Required bool `json:"required,omitempty"`
+	// TicketPattern is an optional regular expression the ticket reference
+	// must match (e.g. "^INC-[0-9]+$")
+	// +optional
+	TicketPattern string `json:"ticketPattern,omitempty"`
+}
+
+// BreakGlassSpec defines how break-glass AccessRequests are handled
+type BreakGlassSpec struct {
+	// MaxDuration caps the duration of break-glass AccessRequests
+	// +kubebuilder:validation:Required
+	MaxDuration metav1.Duration `json:"maxDuration"`
+	// Reviewers is a list of usernames or groups allowed to review
+	// break-glass AccessRequests for this role
+	// +kubebuilder:validation:MinItems=1
+	Reviewers []string `json:"reviewers"`
 }
 
 // RoleTemplateStatus defines the observed state of RoleTemplate
@@ -86,6 +162,39 @@ func (rt *RoleTemplate) Render(projName, appName, appNs string) (*RoleTemplate,
 	return rendered, nil
 }
 
+// RenderProject will return a new RoleTemplate instance with the templates
+// replaced by the given projName for project-scoped AccessRequests. Only the
+// 'role' and 'project' variables are available and rendering fails if the
+// templates reference any other variable.
+func (rt *RoleTemplate) RenderProject(projName string) (*RoleTemplate, error) {
+	rendered := rt.DeepCopy()
+	vars := map[string]string{
+		"role":    fmt.Sprintf("proj:%s:%s", projName, rt.ProjectRoleName()),
+		"project": projName,
+	}
+	descTmpl, err := template.New("description").Option("missingkey=error").Parse(rt.Spec.Description)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing RoleTemplate description: %w", err)
+	}
+	desc, err := execTemplate(descTmpl, vars)
+	if err != nil {
+		return nil, fmt.Errorf("error rendering RoleTemplate description for project: %w", err)
+	}
+	rendered.Spec.Description = desc
+
+	policiesTmpl, err := template.New("policies").Option("missingkey=error").Parse(strings.Join(rt.Spec.Policies, "\n"))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing RoleTemplate policies: %w", err)
+	}
+	p, err := execTemplate(policiesTmpl, vars)
+	if err != nil {
+		return nil, fmt.Errorf("error rendering RoleTemplate policies for project: %w", err)
+	}
+	rendered.Spec.Policies = strings.Split(p, "\n")
+
+	return rendered, nil
+}
+
 func (rt *RoleTemplate) execTemplate(tmpl *template.Template, projName, appName, appNs string) (string, error) {
 	roleName := rt.AppProjectRoleName(appName, appNs)
 	vars := map[string]string{
@@ -94,6 +203,10 @@ func (rt *RoleTemplate) execTemplate(tmpl *template.Template, projName, appName,
 		"application": appName,
 		"namespace":   appNs,
 	}
+	return execTemplate(tmpl, vars)
+}
+
+func execTemplate(tmpl *template.Template, vars map[string]string) (string, error) {
 	var s strings.Builder
 	err := tmpl.Execute(&s, vars)
 	if err != nil {
@@ -102,10 +215,224 @@ func (rt *RoleTemplate) execTemplate(tmpl *template.Template, projName, appName,
 	return s.String(), nil
 }
 
-// roleName will return the role name to be used in the AppProject
+// Values used to test-render RoleTemplates during validation
+const (
+	validationProject   = "validation-project"
+	validationApp       = "validation-app"
+	validationNamespace = "validation-namespace"
+)
+
+// policyResources defines the resources allowed in Argo CD AppProject
+// role policies
+var policyResources = []string{"applications", "applicationsets", "repositories", "clusters", "logs", "exec"}
+
+// Validate will parse and test-render the description and policies
+// templates of this RoleTemplate and validate each rendered policy as an
+// Argo CD AppProject role casbin policy. It returns an error describing the
+// first problem found.
+func (rt *RoleTemplate) Validate() error {
+	rendered, err := rt.Render(validationProject, validationApp, validationNamespace)
+	if err != nil {
+		return err
+	}
+	roleName := rt.AppProjectRoleName(validationApp, validationNamespace)
+	for idx, policy := range rendered.Spec.Policies {
+		err := validatePolicy(policy, validationProject, roleName)
+		if err != nil {
+			return fmt.Errorf("invalid policy at index %d: %w", idx, err)
+		}
+	}
+	if rt.Spec.AccessWindows != nil {
+		err := rt.Spec.AccessWindows.Validate()
+		if err != nil {
+			return fmt.Errorf("invalid access windows: %w", err)
+		}
+	}
+	if rt.Spec.DefaultDuration != nil {
+		if rt.Spec.DefaultDuration.Duration <= 0 {
+			return fmt.Errorf("default duration must be positive")
+		}
+		err := rt.ValidateDuration(rt.Spec.DefaultDuration.Duration)
+		if err != nil {
+			return fmt.Errorf("invalid default duration: %w", err)
+		}
+	}
+	if rt.Spec.MaxExtendedDuration != nil && rt.Spec.MaxDuration != nil &&
+		rt.Spec.MaxExtendedDuration.Duration < rt.Spec.MaxDuration.Duration {
+		return fmt.Errorf("max extended duration must not be lower than the max duration")
+	}
+	if rt.Spec.Quotas != nil {
+		err := rt.Spec.Quotas.Validate()
+		if err != nil {
+			return fmt.Errorf("invalid quotas: %w", err)
+		}
+	}
+	if rt.Spec.BreakGlass != nil && rt.Spec.BreakGlass.MaxDuration.Duration <= 0 {
+		return fmt.Errorf("break-glass max duration must be positive")
+	}
+	if rt.Spec.Justification != nil && rt.Spec.Justification.TicketPattern != "" {
+		_, err := regexp.Compile(rt.Spec.Justification.TicketPattern)
+		if err != nil {
+			return fmt.Errorf("invalid ticket pattern: %w", err)
+		}
+	}
+	return nil
+}
+
+// validatePolicy verifies if the given policy is a valid Argo CD AppProject
+// role policy in the form: 'p, proj:<project>:<role>, <resource>, <action>, <project>/<object>, <allow|deny>'
+func validatePolicy(policy, projName, roleName string) error {
+	components := strings.Split(policy, ",")
+	if len(components) != 6 || strings.TrimSpace(components[0]) != "p" {
+		return fmt.Errorf("policy rule '%s' must be of the form: 'p, sub, res, act, obj, eft'", policy)
+	}
+	subject := strings.TrimSpace(components[1])
+	expectedSubject := fmt.Sprintf("proj:%s:%s", projName, roleName)
+	if subject != expectedSubject {
+		return fmt.Errorf("policy subject must be '{{.role}}', not '%s'", subject)
+	}
+	resource := strings.TrimSpace(components[2])
+	if !slices.Contains(policyResources, resource) {
+		return fmt.Errorf("policy resource must be one of %s, not '%s'", strings.Join(policyResources, ", "), resource)
+	}
+	action := strings.TrimSpace(components[3])
+	if action == "" {
+		return fmt.Errorf("policy action must not be empty")
+	}
+	object := strings.TrimSpace(components[4])
+	objectRegexp := regexp.MustCompile(fmt.Sprintf(`^%s/[*\w.-]+(/[*\w.-]+)?$`, regexp.QuoteMeta(projName)))
+	if !objectRegexp.MatchString(object) {
+		return fmt.Errorf("policy object must be of the form '{{.project}}/*', '{{.project}}[/<NAMESPACE>]/<APPNAME>', not '%s'", object)
+	}
+	effect := strings.TrimSpace(components[5])
+	if effect != "allow" && effect != "deny" {
+		return fmt.Errorf("policy effect must be 'allow' or 'deny', not '%s'", effect)
+	}
+	return nil
+}
+
+// RequiresApproval returns true if AccessRequests for this role must be
+// manually approved before being granted.
+func (rt *RoleTemplate) RequiresApproval() bool {
+	return rt.Spec.Approval != nil
+}
+
+// ValidateDuration returns an error if the given duration exceeds the
+// maximum duration allowed for this role. Any duration is valid if the
+// role doesn't define a maximum duration.
+func (rt *RoleTemplate) ValidateDuration(duration time.Duration) error {
+	if rt.Spec.MaxDuration != nil && duration > rt.Spec.MaxDuration.Duration {
+		return fmt.Errorf("duration %s exceeds the maximum duration of %s", duration, rt.Spec.MaxDuration.Duration)
+	}
+	return nil
+}
+
+// AllowsExtensions returns true if AccessRequests for this role can be
+// extended.
+func (rt *RoleTemplate) AllowsExtensions() bool {
+	return rt.Spec.MaxExtendedDuration != nil
+}
+
+// ValidateJustification returns an error if the given justification and
+// ticketRef don't satisfy the justification requirements of this role. The
+// ticketRef must match the ticket pattern whenever it is provided.
+func (rt *RoleTemplate) ValidateJustification(justification, ticketRef string) error {
+	if rt.Spec.Justification == nil {
+		return nil
+	}
+	if rt.Spec.Justification.Required {
+		if strings.TrimSpace(justification) == "" {
+			return fmt.Errorf("justification is required for role %s", rt.Name)
+		}
+		if strings.TrimSpace(ticketRef) == "" {
+			return fmt.Errorf("ticket reference is required for role %s", rt.Name)
+		}
+	}
+	if ticketRef != "" && rt.Spec.Justification.TicketPattern != "" {
+		re, err := regexp.Compile(rt.Spec.Justification.TicketPattern)
+		if err != nil {
+			return fmt.Errorf("invalid ticket pattern: %w", err)
+		}
+		if !re.MatchString(ticketRef) {
+			return fmt.Errorf("ticket reference %q does not match the pattern %q", ticketRef, rt.Spec.Justification.TicketPattern)
+		}
+	}
+	return nil
+}
+
+// ValidateBreakGlass returns an error if this role doesn't allow break-glass
+// access or if the given duration exceeds the break-glass maximum duration.
+func (rt *RoleTemplate) ValidateBreakGlass(duration time.Duration) error {
+	if rt.Spec.BreakGlass == nil {
+		return fmt.Errorf("role %s does not allow break-glass access", rt.Name)
+	}
+	if duration > rt.Spec.BreakGlass.MaxDuration.Duration {
+		return fmt.Errorf("duration %s exceeds the break-glass maximum duration of %s", duration, rt.Spec.BreakGlass.MaxDuration.Duration)
+	}
+	return nil
+}
+
+// IsReviewer returns true if the given username or at least one of the given
+// groups is listed as break-glass reviewer for this role.
+func (rt *RoleTemplate) IsReviewer(username string, groups []string) bool {
+	if rt.Spec.BreakGlass == nil {
+		return false
+	}
+	for _, reviewer := range rt.Spec.BreakGlass.Reviewers {
+		if reviewer == username || slices.Contains(groups, reviewer) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsApprover returns true if the given username or at least one of the given
+// groups is listed as approver for this role.
+func (rt *RoleTemplate) IsApprover(username string, groups []string) bool {
+	if rt.Spec.Approval == nil {
+		return false
+	}
+	for _, approver := range rt.Spec.Approval.Approvers {
+		if approver == username || slices.Contains(groups, approver) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsRevoker returns true if the given username or at least one of the given
+// groups is listed as revoker or approver for this role.
+func (rt *RoleTemplate) IsRevoker(username string, groups []string) bool {
+	for _, revoker := range rt.Spec.Revokers {
+		if revoker == username || slices.Contains(groups, revoker) {
+			return true
+		}
+	}
+	return rt.IsApprover(username, groups)
+}
+
+// AppProjectRolePrefix is the prefix of all AppProject roles managed by
+// the ephemeral access controller
+const AppProjectRolePrefix = "ephemeral-"
+
+// projectRolePrefix is prepended to the RoleTemplate name in the AppProject
+// roles created for project-scoped AccessRequests. The underscore makes sure
+// the role name never clashes with the roles created for Applications as
+// they can not contain it: namespaces and Application names don't allow it
+// and it is replaced in the RoleTemplate name.
+const projectRolePrefix = "project_"
+
+// roleName will return the role name to be used in the AppProject. Any
+// underscore in the RoleTemplate name is replaced by a dash.
 func (rt *RoleTemplate) AppProjectRoleName(appName, namespace string) string {
-	roleName := rt.Spec.Name
-	return fmt.Sprintf("ephemeral-%s-%s-%s", roleName, namespace, appName)
+	roleName := strings.ReplaceAll(rt.Spec.Name, "_", "-")
+	return fmt.Sprintf("%s%s-%s-%s", AppProjectRolePrefix, roleName, namespace, appName)
+}
+
+// ProjectRoleName will return the role name to be used in the AppProject
+// for project-scoped AccessRequests
+func (rt *RoleTemplate) ProjectRoleName() string {
+	return fmt.Sprintf("%s%s%s", AppProjectRolePrefix, projectRolePrefix, rt.Spec.Name)
 }
 
 func init() {
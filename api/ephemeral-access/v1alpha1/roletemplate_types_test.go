@@ -0,0 +1,263 @@
+package v1alpha1_test
+
+import (
+	"testing"
+	"time"
+
+	api "github.com/argoproj-labs/ephemeral-access/api/ephemeral-access/v1alpha1"
+	"github.com/argoproj-labs/ephemeral-access/test/utils"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRoleTemplate_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		description string
+		policies    []string
+		expectedErr string
+	}{
+		{
+			name: "valid policies",
+			policies: []string{
+				"p, {{.role}}, applications, sync, {{.project}}/{{.application}}, allow",
+				"p, {{.role}}, applications, delete/*/Pod/*, {{.project}}/{{.application}}, deny",
+				"p, {{.role}}, logs, get, {{.project}}/{{.namespace}}/{{.application}}, allow",
+				"p, {{.role}}, exec, create, {{.project}}/*, allow",
+			},
+		},
+		{
+			name:        "invalid description template",
+			description: "{{.invalid",
+			policies:    []string{"p, {{.role}}, applications, sync, {{.project}}/{{.application}}, allow"},
+			expectedErr: "error parsing RoleTemplate description",
+		},
+		{
+			name:        "invalid policies template",
+			policies:    []string{"p, {{.role}, applications, sync, {{.project}}/{{.application}}, allow"},
+			expectedErr: "error parsing RoleTemplate policies",
+		},
+		{
+			name:        "wrong number of fields",
+			policies:    []string{"p, {{.role}}, applications, sync, {{.project}}/{{.application}}"},
+			expectedErr: "invalid policy at index 0: policy rule",
+		},
+		{
+			name:        "wrong policy type",
+			policies:    []string{"g, {{.role}}, applications, sync, {{.project}}/{{.application}}, allow"},
+			expectedErr: "must be of the form",
+		},
+		{
+			name:        "wrong subject",
+			policies:    []string{"p, proj:other:role, applications, sync, {{.project}}/{{.application}}, allow"},
+			expectedErr: "policy subject must be '{{.role}}', not 'proj:other:role'",
+		},
+		{
+			name:        "invalid resource",
+			policies:    []string{"p, {{.role}}, projects, get, {{.project}}/{{.application}}, allow"},
+			expectedErr: "policy resource must be one of",
+		},
+		{
+			name:        "empty action",
+			policies:    []string{"p, {{.role}}, applications, , {{.project}}/{{.application}}, allow"},
+			expectedErr: "policy action must not be empty",
+		},
+		{
+			name: "object in other project",
+			policies: []string{
+				"p, {{.role}}, applications, sync, {{.project}}/{{.application}}, allow",
+				"p, {{.role}}, applications, sync, other/{{.application}}, allow",
+			},
+			expectedErr: "invalid policy at index 1: policy object must be of the form",
+		},
+		{
+			name:        "invalid effect",
+			policies:    []string{"p, {{.role}}, applications, sync, {{.project}}/{{.application}}, maybe"},
+			expectedErr: "policy effect must be 'allow' or 'deny', not 'maybe'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given
+			rt := utils.NewRoleTemplate("test", "test-ns", "role", tt.policies)
+			rt.Spec.Description = tt.description
+
+			// When
+			err := rt.Validate()
+
+			// Then
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expectedErr)
+		})
+	}
+}
+
+func TestRoleTemplate_ValidateDuration(t *testing.T) {
+	t.Run("will allow any duration if max duration is not defined", func(t *testing.T) {
+		rt := utils.NewRoleTemplate("some-template", "some-ns", "some-role", nil)
+		assert.NoError(t, rt.ValidateDuration(time.Hour*1000))
+	})
+	t.Run("will validate the duration against max duration", func(t *testing.T) {
+		rt := utils.NewRoleTemplate("some-template", "some-ns", "some-role", nil)
+		rt.Spec.MaxDuration = &metav1.Duration{Duration: time.Hour}
+		assert.NoError(t, rt.ValidateDuration(time.Hour))
+		err := rt.ValidateDuration(time.Hour + time.Second)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "exceeds the maximum duration of 1h0m0s")
+	})
+}
+
+func TestRoleTemplate_ValidateDefaultDuration(t *testing.T) {
+	policies := []string{"p, {{.role}}, applications, sync, {{.project}}/{{.application}}, allow"}
+	t.Run("will accept default duration within the max duration", func(t *testing.T) {
+		rt := utils.NewRoleTemplate("some-template", "some-ns", "some-role", policies)
+		rt.Spec.DefaultDuration = &metav1.Duration{Duration: 30 * time.Minute}
+		rt.Spec.MaxDuration = &metav1.Duration{Duration: time.Hour}
+		assert.NoError(t, rt.Validate())
+	})
+	t.Run("will return error if default duration exceeds the max duration", func(t *testing.T) {
+		rt := utils.NewRoleTemplate("some-template", "some-ns", "some-role", policies)
+		rt.Spec.DefaultDuration = &metav1.Duration{Duration: 2 * time.Hour}
+		rt.Spec.MaxDuration = &metav1.Duration{Duration: time.Hour}
+		assert.ErrorContains(t, rt.Validate(), "invalid default duration: duration 2h0m0s exceeds the maximum duration of 1h0m0s")
+	})
+	t.Run("will return error if default duration is not positive", func(t *testing.T) {
+		rt := utils.NewRoleTemplate("some-template", "some-ns", "some-role", policies)
+		rt.Spec.DefaultDuration = &metav1.Duration{}
+		assert.ErrorContains(t, rt.Validate(), "default duration must be positive")
+	})
+}
+
+func TestRoleTemplate_ValidateMaxExtendedDuration(t *testing.T) {
+	policies := []string{"p, {{.role}}, applications, sync, {{.project}}/{{.application}}, allow"}
+	t.Run("will not allow extensions if only max duration is defined", func(t *testing.T) {
+		rt := utils.NewRoleTemplate("some-template", "some-ns", "some-role", policies)
+		rt.Spec.MaxDuration = &metav1.Duration{Duration: time.Hour}
+		assert.NoError(t, rt.Validate())
+		assert.False(t, rt.AllowsExtensions())
+	})
+	t.Run("will allow extensions without max duration", func(t *testing.T) {
+		rt := utils.NewRoleTemplate("some-template", "some-ns", "some-role", policies)
+		rt.Spec.MaxExtendedDuration = &metav1.Duration{Duration: time.Hour}
+		assert.NoError(t, rt.Validate())
+		assert.True(t, rt.AllowsExtensions())
+		assert.NoError(t, rt.ValidateDuration(2*time.Hour))
+	})
+	t.Run("will return error if max extended duration is lower than max duration", func(t *testing.T) {
+		rt := utils.NewRoleTemplate("some-template", "some-ns", "some-role", policies)
+		rt.Spec.MaxDuration = &metav1.Duration{Duration: 2 * time.Hour}
+		rt.Spec.MaxExtendedDuration = &metav1.Duration{Duration: time.Hour}
+		assert.ErrorContains(t, rt.Validate(), "max extended duration must not be lower than the max duration")
+	})
+}
+
+func TestRoleTemplate_ValidateJustification(t *testing.T) {
+	t.Run("will allow missing justification if not defined", func(t *testing.T) {
+		rt := utils.NewRoleTemplate("some-template", "some-ns", "some-role", nil)
+		assert.NoError(t, rt.ValidateJustification("", ""))
+	})
+	t.Run("will require justification and ticket reference", func(t *testing.T) {
+		rt := utils.NewRoleTemplate("some-template", "some-ns", "some-role", nil)
+		rt.Spec.Justification = &api.JustificationSpec{Required: true}
+		assert.NoError(t, rt.ValidateJustification("incident response", "INC-123"))
+		assert.ErrorContains(t, rt.ValidateJustification(" ", "INC-123"), "justification is required")
+		assert.ErrorContains(t, rt.ValidateJustification("incident response", ""), "ticket reference is required")
+	})
+	t.Run("will validate the ticket reference against the pattern", func(t *testing.T) {
+		rt := utils.NewRoleTemplate("some-template", "some-ns", "some-role", nil)
+		rt.Spec.Justification = &api.JustificationSpec{TicketPattern: "^INC-[0-9]+$"}
+		assert.NoError(t, rt.ValidateJustification("", ""))
+		assert.NoError(t, rt.ValidateJustification("", "INC-123"))
+		assert.ErrorContains(t, rt.ValidateJustification("", "CHG-123"), `does not match the pattern "^INC-[0-9]+$"`)
+	})
+	t.Run("will return error on invalid ticket pattern", func(t *testing.T) {
+		rt := utils.NewRoleTemplate("some-template", "some-ns", "some-role", []string{
+			"p, {{.role}}, applications, sync, {{.project}}/{{.application}}, allow",
+		})
+		rt.Spec.Justification = &api.JustificationSpec{TicketPattern: "INC-[0-9"}
+		assert.ErrorContains(t, rt.Validate(), "invalid ticket pattern")
+	})
+}
+
+func TestRoleTemplate_ValidateBreakGlass(t *testing.T) {
+	t.Run("will return error if break-glass is not allowed", func(t *testing.T) {
+		rt := utils.NewRoleTemplate("some-template", "some-ns", "some-role", nil)
+		assert.ErrorContains(t, rt.ValidateBreakGlass(time.Minute), "role some-template does not allow break-glass access")
+	})
+	t.Run("will validate the break-glass maximum duration", func(t *testing.T) {
+		rt := utils.NewRoleTemplate("some-template", "some-ns", "some-role", nil)
+		rt.Spec.BreakGlass = &api.BreakGlassSpec{MaxDuration: metav1.Duration{Duration: time.Hour}}
+		assert.NoError(t, rt.ValidateBreakGlass(time.Hour))
+		assert.ErrorContains(t, rt.ValidateBreakGlass(2*time.Hour), "exceeds the break-glass maximum duration of 1h0m0s")
+	})
+	t.Run("will return error on invalid break-glass maximum duration", func(t *testing.T) {
+		rt := utils.NewRoleTemplate("some-template", "some-ns", "some-role", []string{
+			"p, {{.role}}, applications, sync, {{.project}}/{{.application}}, allow",
+		})
+		rt.Spec.BreakGlass = &api.BreakGlassSpec{}
+		assert.ErrorContains(t, rt.Validate(), "break-glass max duration must be positive")
+	})
+}
+
+func TestRoleTemplate_IsReviewer(t *testing.T) {
+	rt := utils.NewRoleTemplate("some-template", "some-ns", "some-role", nil)
+	assert.False(t, rt.IsReviewer("auditor", []string{"auditors"}))
+	rt.Spec.BreakGlass = &api.BreakGlassSpec{Reviewers: []string{"auditor", "auditors"}}
+	assert.True(t, rt.IsReviewer("auditor", nil))
+	assert.True(t, rt.IsReviewer("someone", []string{"devs", "auditors"}))
+	assert.False(t, rt.IsReviewer("someone", []string{"devs"}))
+}
+
+func TestRoleTemplate_IsRevoker(t *testing.T) {
+	rt := utils.NewRoleTemplate("some-template", "some-ns", "some-role", nil)
+	assert.False(t, rt.IsRevoker("admin", []string{"admins"}))
+	rt.Spec.Revokers = []string{"admin", "admins"}
+	assert.True(t, rt.IsRevoker("admin", nil))
+	assert.True(t, rt.IsRevoker("someone", []string{"devs", "admins"}))
+	assert.False(t, rt.IsRevoker("someone", []string{"devs"}))
+	rt.Spec.Approval = &api.ApprovalSpec{Approvers: []string{"approvers"}}
+	assert.True(t, rt.IsRevoker("someone", []string{"approvers"}))
+}
+
+func TestRoleTemplate_RenderProject(t *testing.T) {
+	t.Run("will render project-wide policies", func(t *testing.T) {
+		rt := utils.NewRoleTemplate("some-template", "some-ns", "some-role", []string{
+			"p, {{.role}}, applications, sync, {{.project}}/*, allow",
+		})
+		rt.Spec.Description = "write access to {{.project}}"
+
+		rendered, err := rt.RenderProject("some-project")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "write access to some-project", rendered.Spec.Description)
+		assert.Equal(t, []string{"p, proj:some-project:ephemeral-project_some-role, applications, sync, some-project/*, allow"}, rendered.Spec.Policies)
+	})
+	t.Run("will return error if templates reference the application", func(t *testing.T) {
+		rt := utils.NewRoleTemplate("some-template", "some-ns", "some-role", []string{
+			"p, {{.role}}, applications, sync, {{.project}}/{{.application}}, allow",
+		})
+
+		_, err := rt.RenderProject("some-project")
+
+		assert.ErrorContains(t, err, "error rendering RoleTemplate policies for project")
+	})
+}
+
+func TestRoleTemplate_AppProjectRoleName(t *testing.T) {
+	t.Run("will build the role name from the template name and the application", func(t *testing.T) {
+		rt := utils.NewRoleTemplate("some-template", "some-ns", "some-role", []string{})
+
+		assert.Equal(t, "ephemeral-some-role-app-ns-app-name", rt.AppProjectRoleName("app-name", "app-ns"))
+	})
+	t.Run("will never clash with project-scoped role names", func(t *testing.T) {
+		rt := utils.NewRoleTemplate("some-template", "some-ns", "project_some-role", []string{})
+		projectRT := utils.NewRoleTemplate("some-template", "some-ns", "some-role-app-ns-app-name", []string{})
+
+		assert.Equal(t, "ephemeral-project-some-role-app-ns-app-name", rt.AppProjectRoleName("app-name", "app-ns"))
+		assert.NotEqual(t, projectRT.ProjectRoleName(), rt.AppProjectRoleName("app-name", "app-ns"))
+	})
+}
@@ -18,8 +18,11 @@ package backend
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net/http"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/argoproj-labs/ephemeral-access/internal/backend"
@@ -50,8 +53,34 @@ type BackendConfig struct {
 	// Namespace must point to the namespace where this backend service is running
 	Namespace string `env:"EPHEMERAL_BACKEND_NAMESPACE, required"`
 	// DefaultAccessDuration defines the default duration to be used when creating
-	// AccessRequests
+	// AccessRequests if neither the user nor the RoleTemplate define one
 	DefaultAccessDuration time.Duration `env:"EPHEMERAL_BACKEND_DEFAULT_ACCESS_DURATION, default=4h"`
+	// SigningKey is an optional shared secret used to sign the AccessRequests
+	// created by the backend. The controller must be configured with the
+	// same key in order to verify the signatures.
+	SigningKey string `env:"EPHEMERAL_BACKEND_SIGNING_KEY"`
+	// TLSCertFile is the path of the certificate used to serve the API with
+	// TLS. Required if TLSKeyFile is provided.
+	TLSCertFile string `env:"EPHEMERAL_BACKEND_TLS_CERT_FILE"`
+	// TLSKeyFile is the path of the private key used to serve the API with
+	// TLS. Required if TLSCertFile is provided.
+	TLSKeyFile string `env:"EPHEMERAL_BACKEND_TLS_KEY_FILE"`
+	// AuthSecret is an optional shared secret that must be sent by Argo CD
+	// API server in the AuthSecretHeader of every request.
+	AuthSecret string `env:"EPHEMERAL_BACKEND_AUTH_SECRET"`
+	// AuthSecretHeader defines the header expected to carry the AuthSecret
+	AuthSecretHeader string `env:"EPHEMERAL_BACKEND_AUTH_SECRET_HEADER, default=Ephemeral-Access-Secret"`
+	// AuthClientCAFile is the path of the CA certificates used to verify
+	// the client certificate sent by Argo CD API server. Requires the API
+	// to be served with TLS.
+	AuthClientCAFile string `env:"EPHEMERAL_BACKEND_AUTH_CLIENT_CA_FILE"`
+	// AuthAllowedNetworks is an optional comma separated list of CIDRs or
+	// IP addresses allowed to send requests to the backend.
+	AuthAllowedNetworks string `env:"EPHEMERAL_BACKEND_AUTH_ALLOWED_NETWORKS"`
+	// AuthDisabled allows the backend to start without any authentication
+	// method configured. In this case the Argo CD headers are trusted from
+	// any client that can reach the backend.
+	AuthDisabled bool `env:"EPHEMERAL_BACKEND_AUTH_DISABLED, default=false"`
 }
 
 // LogConfig defines the log configurations
@@ -66,6 +95,59 @@ type LogConfig struct {
 	Format string `env:"FORMAT, default=text"`
 }
 
+// redactedOptions returns a copy of the given opts without secrets so
+// it can be safely logged.
+func redactedOptions(opts *Options) Options {
+	redacted := *opts
+	if redacted.Backend.SigningKey != "" {
+		redacted.Backend.SigningKey = "<redacted>"
+	}
+	if redacted.Backend.AuthSecret != "" {
+		redacted.Backend.AuthSecret = "<redacted>"
+	}
+	return redacted
+}
+
+// newAuthenticators returns the authenticators enabled in the given cfg.
+func newAuthenticators(cfg BackendConfig) ([]backend.Authenticator, error) {
+	authenticators := []backend.Authenticator{}
+	if cfg.AuthAllowedNetworks != "" {
+		networks := []string{}
+		for _, network := range strings.Split(cfg.AuthAllowedNetworks, ",") {
+			if network = strings.TrimSpace(network); network != "" {
+				networks = append(networks, network)
+			}
+		}
+		authenticator, err := backend.NewNetworkAuthenticator(networks)
+		if err != nil {
+			return nil, fmt.Errorf("error creating network authenticator: %w", err)
+		}
+		authenticators = append(authenticators, authenticator)
+	}
+	if cfg.AuthClientCAFile != "" {
+		if cfg.TLSCertFile == "" || cfg.TLSKeyFile == "" {
+			return nil, fmt.Errorf("client certificate authentication requires the TLS certificate and key files")
+		}
+		caPEM, err := os.ReadFile(cfg.AuthClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading client CA file: %w", err)
+		}
+		authenticator, err := backend.NewClientCertAuthenticator(caPEM)
+		if err != nil {
+			return nil, fmt.Errorf("error creating client certificate authenticator: %w", err)
+		}
+		authenticators = append(authenticators, authenticator)
+	}
+	if cfg.AuthSecret != "" {
+		authenticator, err := backend.NewSecretAuthenticator(cfg.AuthSecretHeader, cfg.AuthSecret)
+		if err != nil {
+			return nil, fmt.Errorf("error creating secret authenticator: %w", err)
+		}
+		authenticators = append(authenticators, authenticator)
+	}
+	return authenticators, nil
+}
+
 func newRestConfig(kubeconfig string, logger log.Logger) (*rest.Config, error) {
 	var config *rest.Config
 	var err error
@@ -125,11 +207,26 @@ func run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("error creating a new k8s persister: %w", err)
 	}
 
-	service := backend.NewDefaultService(persister, logger, opts.Backend.Namespace, opts.Backend.DefaultAccessDuration)
+	if (opts.Backend.TLSCertFile == "") != (opts.Backend.TLSKeyFile == "") {
+		return fmt.Errorf("TLS certificate and key files must be provided together")
+	}
+	authenticators, err := newAuthenticators(opts.Backend)
+	if err != nil {
+		return fmt.Errorf("error configuring authentication: %w", err)
+	}
+	if len(authenticators) == 0 {
+		if !opts.Backend.AuthDisabled {
+			return fmt.Errorf("no authentication configured: configure at least one authentication method or set EPHEMERAL_BACKEND_AUTH_DISABLED=true")
+		}
+		logger.Error(nil, "Authentication disabled: Argo CD headers are trusted from any client")
+	}
+
+	service := backend.NewDefaultService(persister, logger, opts.Backend.Namespace, opts.Backend.DefaultAccessDuration, []byte(opts.Backend.SigningKey))
 	handler := backend.NewAPIHandler(service, logger)
 
 	cli := humacli.New(func(hooks humacli.Hooks, options *BackendConfig) {
 		router := chi.NewMux()
+		router.Use(backend.NewAuthMiddleware(logger, authenticators...))
 		api := humachi.New(router, huma.DefaultConfig(backend.APITitle, backend.APIVersion))
 		backend.RegisterRoutes(api, handler)
 
@@ -137,6 +234,12 @@ func run(cmd *cobra.Command, args []string) error {
 			Addr:    fmt.Sprintf(":%d", opts.Backend.Port),
 			Handler: router,
 		}
+		if opts.Backend.AuthClientCAFile != "" {
+			// client certificates are verified by the auth middleware so
+			// unauthenticated requests receive a 401 response instead of
+			// a TLS handshake error
+			server.TLSConfig = &tls.Config{ClientAuth: tls.RequestClientCert}
+		}
 
 		ctx, cancel := context.WithCancel(context.Background())
 		hooks.OnStart(func() {
@@ -152,8 +255,12 @@ func run(cmd *cobra.Command, args []string) error {
 			serverErr := make(chan error)
 			defer close(serverErr)
 			go func() {
-				logger.Info("Starting Ephemeral Access API Server...", "configs", opts)
-				server.ListenAndServe()
+				logger.Info("Starting Ephemeral Access API Server...", "configs", redactedOptions(opts))
+				if opts.Backend.TLSCertFile != "" {
+					server.ListenAndServeTLS(opts.Backend.TLSCertFile, opts.Backend.TLSKeyFile)
+				} else {
+					server.ListenAndServe()
+				}
 			}()
 			select {
 			case <-ctx.Done():
@@ -17,6 +17,7 @@ limitations under the License.
 package controller
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 
@@ -36,7 +37,10 @@ import (
 	api "github.com/argoproj-labs/ephemeral-access/api/ephemeral-access/v1alpha1"
 	"github.com/argoproj-labs/ephemeral-access/internal/controller"
 	"github.com/argoproj-labs/ephemeral-access/internal/controller/config"
+	ephemeralwebhook "github.com/argoproj-labs/ephemeral-access/internal/webhook"
 	"github.com/argoproj-labs/ephemeral-access/pkg/log"
+	"github.com/argoproj-labs/ephemeral-access/pkg/plugin"
+	goPlugin "github.com/hashicorp/go-plugin"
 	"github.com/spf13/cobra"
 	// +kubebuilder:scaffold:imports
 )
@@ -80,6 +84,12 @@ func run(cmd *cobra.Command, args []string) error {
 	ctrl.SetLogger(logger)
 
 	setupLog.Info(fmt.Sprintf("Using controller configs: %s", config))
+	if !config.ControllerRequireSignature() {
+		if !config.ControllerAllowUnsigned() {
+			return fmt.Errorf("signatures are not required: enable EPHEMERAL_CONTROLLER_REQUIRE_SIGNATURE or set EPHEMERAL_CONTROLLER_ALLOW_UNSIGNED=true to evaluate AccessBindings with unverified group claims")
+		}
+		setupLog.Error(nil, "Signatures are not required: AccessBindings are evaluated with unverified group claims")
+	}
 
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
@@ -129,16 +139,77 @@ func run(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("unable to start manager: %w", err)
 	}
 
-	service := controller.NewService(mgr.GetClient(), config)
+	var accessRequester plugin.AccessRequester
+	if config.PluginPath() != "" {
+		pluginLogger, err := log.NewPluginLogger(log.WithLevel(level), log.WithFormat(format))
+		if err != nil {
+			return fmt.Errorf("error creating plugin logger: %w", err)
+		}
+		setupLog.Info(fmt.Sprintf("Loading plugin: %s", config.PluginPath()))
+		pluginClient := goPlugin.NewClient(plugin.NewClientConfig(config.PluginPath(), pluginLogger))
+		defer pluginClient.Kill()
+
+		accessRequester, err = plugin.GetAccessRequester(pluginClient)
+		if err != nil {
+			return fmt.Errorf("error loading plugin %s: %w", config.PluginPath(), err)
+		}
+		err = accessRequester.Init()
+		if err != nil {
+			return fmt.Errorf("error initializing plugin %s: %w", config.PluginPath(), err)
+		}
+	}
+
+	if config.ControllerServerSideApply() {
+		err = controller.VerifyServerSideApply(context.Background(), mgr.GetAPIReader())
+		if err != nil {
+			return fmt.Errorf("unable to enable server-side apply: %w", err)
+		}
+	}
+
+	recorder := mgr.GetEventRecorderFor("ephemeral-access-controller")
+	service := controller.NewService(mgr.GetClient(), config, accessRequester, recorder)
+	archiver, err := controller.NewArchiver(config.ControllerArchiveDir(), config.ControllerArchiveURL())
+	if err != nil {
+		return fmt.Errorf("error creating archiver: %w", err)
+	}
 
 	if err = (&controller.AccessRequestReconciler{
-		Client:  mgr.GetClient(),
-		Scheme:  mgr.GetScheme(),
-		Service: service,
-		Config:  config,
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Service:  service,
+		Config:   config,
+		Recorder: recorder,
+		Archiver: archiver,
 	}).SetupWithManager(mgr); err != nil {
 		return fmt.Errorf("unable to create controller AccessRequest controller: %w", err)
 	}
+	if err = (&controller.RoleTemplateReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: recorder,
+	}).SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("unable to create controller RoleTemplate controller: %w", err)
+	}
+	if err = (&controller.ProjectDriftReconciler{
+		Client:          mgr.GetClient(),
+		Recorder:        recorder,
+		ServerSideApply: config.ControllerServerSideApply(),
+	}).SetupWithManager(mgr); err != nil {
+		return fmt.Errorf("unable to create controller AppProject drift controller: %w", err)
+	}
+	if config.ControllerRoleSweeperInterval() > 0 {
+		sweeper := controller.NewRoleSweeper(mgr.GetClient(), recorder, config.ControllerRoleSweeperInterval(), config.ControllerRoleSweeperDryRun(), config.ControllerServerSideApply())
+		if err := mgr.Add(sweeper); err != nil {
+			return fmt.Errorf("unable to add role sweeper: %w", err)
+		}
+	}
+	if config.WebhookEnabled() {
+		setupLog.Info("Registering validating webhooks")
+		err = ephemeralwebhook.SetupWithManager(mgr, config.WebhookMaxAccessDuration())
+		if err != nil {
+			return fmt.Errorf("unable to create webhooks: %w", err)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
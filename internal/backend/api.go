@@ -2,14 +2,19 @@ package backend
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
 	api "github.com/argoproj-labs/ephemeral-access/api/ephemeral-access/v1alpha1"
 	"github.com/argoproj-labs/ephemeral-access/pkg/log"
 	"github.com/danielgtaylor/huma/v2"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 const (
@@ -17,6 +22,10 @@ const (
 	APITitle = "Ephemeral Access API"
 	// APIVersion refers to the API version used in the open-api spec.
 	APIVersion = "0.0.1"
+
+	// projectScope is the CreateAccessRequestBody scope requesting access to
+	// all applications in the project.
+	projectScope = "project"
 )
 
 // ArgoCDHeaders defines the required headers that are sent by Argo CD
@@ -64,7 +73,14 @@ type CreateAccessRequestInput struct {
 
 // CreateAccessRequestBody defines the create access response body.
 type CreateAccessRequestBody struct {
-	RoleName string `json:"roleName" example:"custom-role-template" doc:"The role template name to request."`
+	RoleName      string `json:"roleName" example:"custom-role-template" doc:"The role template name to request."`
+	StartsAt      string `json:"startsAt,omitempty" example:"2024-02-14T18:25:50Z" doc:"The timestamp the access should start (RFC3339 format). If not provided, the access starts as soon as possible." format:"date-time"`
+	Duration      string `json:"duration,omitempty" example:"1h" doc:"The requested access duration (Go duration format). If not provided, the role default duration is used. Must not exceed the role maximum duration."`
+	Group         string `json:"group,omitempty" example:"on-call" doc:"The group to grant the access to as a whole instead of the requesting user. Must be allowed by the AccessBinding groupTargets."`
+	Scope         string `json:"scope,omitempty" example:"project" doc:"The scope of the access. If project, the access is granted for all applications in the Argo CD project of the current application." enum:"application,project" default:"application"`
+	Justification string `json:"justification,omitempty" maxLength:"1024" example:"Investigating incident INC-123" doc:"Explains why the access is needed. May be required by the role."`
+	TicketRef     string `json:"ticketRef,omitempty" maxLength:"256" example:"INC-123" doc:"The ticket associated with the access (e.g. incident or change request). May be required by the role."`
+	BreakGlass    bool   `json:"breakGlass,omitempty" doc:"Requests emergency access bypassing approval and plugin checks. Must be allowed by the role and by a break-glass AccessBinding. The access must be reviewed afterwards."`
 }
 
 // CreateAccessRequestResponse defines the create access response.
@@ -75,15 +91,121 @@ type CreateAccessRequestResponse struct {
 // AccessRequestResponseBody defines the access request fields returned as part of
 // the response body.
 type AccessRequestResponseBody struct {
-	Name        string `json:"name" example:"some-accessrequest" doc:"The access request name."`
-	Namespace   string `json:"namespace" example:"some-namespace" doc:"The access request namespace."`
-	Username    string `json:"username" example:"some-user@acme.org" doc:"The user associated with the access request."`
-	Permission  string `json:"permission" example:"Operator Access" doc:"The permission description of the role associated to this access request."`
-	Role        string `json:"role" example:"custom-role-template" doc:"The role template associated to this access request."`
-	RequestedAt string `json:"requestedAt,omitempty" example:"2024-02-14T18:25:50Z" doc:"The timestamp the access was requested (RFC3339 format)." format:"date-time"`
-	Status      string `json:"status,omitempty" example:"GRANTED" doc:"The current access request status." enum:"REQUESTED,GRANTED,EXPIRED,DENIED,INVALID"`
-	ExpiresAt   string `json:"expiresAt,omitempty" example:"2024-02-14T18:25:50Z" doc:"The timestamp the access will expire (RFC3339 format)." format:"date-time"`
-	Message     string `json:"message,omitempty" example:"Click the link to see more details: ..." doc:"A human readeable description with details about the access request."`
+	Name          string `json:"name" example:"some-accessrequest" doc:"The access request name."`
+	Namespace     string `json:"namespace" example:"some-namespace" doc:"The access request namespace."`
+	Username      string `json:"username" example:"some-user@acme.org" doc:"The user associated with the access request."`
+	Group         string `json:"group,omitempty" example:"on-call" doc:"The group granted with the access as a whole, if any."`
+	Justification string `json:"justification,omitempty" example:"Investigating incident INC-123" doc:"Explains why the access is needed."`
+	TicketRef     string `json:"ticketRef,omitempty" example:"INC-123" doc:"The ticket associated with the access."`
+	Project       string `json:"project,omitempty" example:"some-project" doc:"The Argo CD project granted with the access, if the access request is project-scoped."`
+	Permission    string `json:"permission" example:"Operator Access" doc:"The permission description of the role associated to this access request."`
+	Role          string `json:"role" example:"custom-role-template" doc:"The role template associated to this access request."`
+	RequestedAt   string `json:"requestedAt,omitempty" example:"2024-02-14T18:25:50Z" doc:"The timestamp the access was requested (RFC3339 format)." format:"date-time"`
+	StartsAt      string `json:"startsAt,omitempty" example:"2024-02-14T18:25:50Z" doc:"The timestamp the access is scheduled to start (RFC3339 format)." format:"date-time"`
+	Status        string `json:"status,omitempty" example:"GRANTED" doc:"The current access request status." enum:"REQUESTED,SCHEDULED,GRANTED,EXPIRED,DENIED,INVALID,REVOKED"`
+	ExpiresAt     string `json:"expiresAt,omitempty" example:"2024-02-14T18:25:50Z" doc:"The timestamp the access will expire (RFC3339 format)." format:"date-time"`
+	Message       string `json:"message,omitempty" example:"Click the link to see more details: ..." doc:"A human readeable description with details about the access request."`
+	BreakGlass    bool   `json:"breakGlass,omitempty" doc:"If true, the access was requested in break-glass mode."`
+	PendingReview bool   `json:"pendingReview,omitempty" doc:"If true, the break-glass access was not reviewed yet."`
+}
+
+// ApprovalInput defines the approve and deny access request input parameters.
+type ApprovalInput struct {
+	ArgoCDHeaders
+	Name string        `path:"name" example:"some-accessrequest" doc:"The access request name."`
+	Body *ApprovalBody `required:"false"`
+}
+
+// ApprovalBody defines the approve and deny access request body.
+type ApprovalBody struct {
+	Reason string `json:"reason,omitempty" maxLength:"1024" example:"Approved as part of incident INC-123" doc:"An optional explanation about the decision."`
+}
+
+// ApprovalResponse defines the approve and deny access request response.
+type ApprovalResponse struct {
+	Body AccessRequestResponseBody
+}
+
+// RevokeAccessRequestInput defines the revoke access request input parameters.
+type RevokeAccessRequestInput struct {
+	ArgoCDHeaders
+	Name string             `path:"name" example:"some-accessrequest" doc:"The access request name."`
+	Body *RevokeRequestBody `required:"false"`
+}
+
+// RevokeRequestBody defines the revoke access request body.
+type RevokeRequestBody struct {
+	Reason string `json:"reason,omitempty" maxLength:"1024" example:"Incident INC-123 resolved" doc:"An optional explanation about the revocation."`
+}
+
+// RevokeAccessRequestResponse defines the revoke access request response.
+type RevokeAccessRequestResponse struct {
+	Body AccessRequestResponseBody
+}
+
+// ExtendAccessRequestInput defines the extend access request input parameters.
+type ExtendAccessRequestInput struct {
+	ArgoCDHeaders
+	Name string `path:"name" example:"some-accessrequest" doc:"The access request name."`
+	Body ExtendAccessRequestBody
+}
+
+// ExtendAccessRequestBody defines the extend access request body.
+type ExtendAccessRequestBody struct {
+	Duration string `json:"duration" example:"30m" doc:"The additional duration requested (Go duration format)."`
+}
+
+// ExtendAccessRequestResponse defines the extend access request response.
+type ExtendAccessRequestResponse struct {
+	Body AccessRequestResponseBody
+}
+
+// ReviewAccessRequestInput defines the review access request input parameters.
+type ReviewAccessRequestInput struct {
+	ArgoCDHeaders
+	Name string             `path:"name" example:"some-accessrequest" doc:"The access request name."`
+	Body *ReviewRequestBody `required:"false"`
+}
+
+// ReviewRequestBody defines the review access request body.
+type ReviewRequestBody struct {
+	Comment string `json:"comment,omitempty" maxLength:"1024" example:"Access used to mitigate incident INC-123" doc:"An optional note about the review."`
+}
+
+// ReviewAccessRequestResponse defines the review access request response.
+type ReviewAccessRequestResponse struct {
+	Body AccessRequestResponseBody
+}
+
+// ListRolesInput defines the list roles input parameters.
+type ListRolesInput struct {
+	ArgoCDHeaders
+}
+
+// ListRolesResponse defines the list roles response.
+type ListRolesResponse struct {
+	Body ListRolesResponseBody
+}
+
+// ListRolesResponseBody defines the list roles response body.
+type ListRolesResponseBody struct {
+	Items []RoleResponseBody `json:"items"`
+}
+
+// RoleResponseBody defines the role fields returned as part of the response
+// body.
+type RoleResponseBody struct {
+	Role                  string   `json:"role" example:"custom-role-template" doc:"The role template name to be provided when requesting access."`
+	FriendlyName          string   `json:"friendlyName,omitempty" example:"Operator Access" doc:"The friendly name of the role."`
+	Ordinal               int      `json:"ordinal" example:"1" doc:"The ordering number of the role compared to the others."`
+	Description           string   `json:"description,omitempty" example:"Allows syncing the application" doc:"The description of the role."`
+	DefaultDuration       string   `json:"defaultDuration" example:"4h0m0s" doc:"The access duration used if not provided when requesting access (Go duration format)."`
+	MaxDuration           string   `json:"maxDuration,omitempty" example:"8h0m0s" doc:"The maximum access duration that can be requested (Go duration format). If not provided, there is no maximum."`
+	MaxExtendedDuration   string   `json:"maxExtendedDuration,omitempty" example:"12h0m0s" doc:"The maximum total access duration including extensions (Go duration format). If not provided, the access can not be extended."`
+	RequiresApproval      bool     `json:"requiresApproval,omitempty" doc:"If true, the access must be manually approved before granted."`
+	JustificationRequired bool     `json:"justificationRequired,omitempty" doc:"If true, both a justification and a ticket reference must be provided when requesting access."`
+	GroupTargets          []string `json:"groupTargets,omitempty" example:"on-call" doc:"The groups that can be granted with the access as a whole."`
+	BreakGlass            bool     `json:"breakGlass,omitempty" doc:"If true, the role can only be requested in break-glass mode."`
 }
 
 // APIHandler is responsible for defining all handlers available as part of the
@@ -112,6 +234,7 @@ func (h *APIHandler) listAccessRequestHandler(ctx context.Context, input *ListAc
 		ApplicationName:      appName,
 		ApplicationNamespace: appNamespace,
 		Username:             input.ArgoCDUsername,
+		ProjectName:          input.ArgoCDProjectName,
 	}
 
 	accessRequests, err := h.service.ListAccessRequests(ctx, key, true)
@@ -127,6 +250,25 @@ func (h *APIHandler) createAccessRequestHandler(ctx context.Context, input *Crea
 	if err != nil {
 		return nil, huma.Error400BadRequest("invalid application", err)
 	}
+	details := AccessRequestDetails{
+		Justification: input.Body.Justification,
+		TicketRef:     input.Body.TicketRef,
+		BreakGlass:    input.Body.BreakGlass,
+	}
+	if input.Body.StartsAt != "" {
+		t, err := time.Parse(time.RFC3339, input.Body.StartsAt)
+		if err != nil {
+			return nil, huma.Error400BadRequest(fmt.Sprintf("invalid startsAt: %q", input.Body.StartsAt))
+		}
+		details.StartsAt = &t
+	}
+	if input.Body.Duration != "" {
+		duration, err := time.ParseDuration(input.Body.Duration)
+		if err != nil || duration <= 0 {
+			return nil, huma.Error400BadRequest(fmt.Sprintf("invalid duration: %q", input.Body.Duration))
+		}
+		details.Duration = duration
+	}
 
 	// Check if AR already exist
 	key := &AccessRequestKey{
@@ -134,6 +276,13 @@ func (h *APIHandler) createAccessRequestHandler(ctx context.Context, input *Crea
 		ApplicationName:      appName,
 		ApplicationNamespace: appNamespace,
 		Username:             input.ArgoCDUsername,
+		Group:                input.Body.Group,
+		ProjectName:          input.ArgoCDProjectName,
+		Groups:               input.Groups(),
+	}
+	if input.Body.Scope == projectScope {
+		key.ApplicationName = ""
+		key.ApplicationNamespace = ""
 	}
 	ar, err := h.service.GetAccessRequestByRole(ctx, key, input.Body.RoleName)
 	if err != nil {
@@ -143,13 +292,17 @@ func (h *APIHandler) createAccessRequestHandler(ctx context.Context, input *Crea
 		return nil, huma.Error409Conflict("AccessRequest already exists")
 	}
 
-	// Validate information in headers necessary to evaluate permissions
-	app, err := h.service.GetApplication(ctx, appName, appNamespace)
-	if err != nil {
-		return nil, h.loggedError(huma.Error500InternalServerError("error getting application", err))
-	}
-	if app == nil {
-		return nil, huma.Error400BadRequest("invalid application", err)
+	// Validate information in headers necessary to evaluate permissions.
+	// Project-scoped access is evaluated against the project only.
+	var app *unstructured.Unstructured
+	if !key.IsProjectScoped() {
+		app, err = h.service.GetApplication(ctx, appName, appNamespace)
+		if err != nil {
+			return nil, h.loggedError(huma.Error500InternalServerError("error getting application", err))
+		}
+		if app == nil {
+			return nil, huma.Error400BadRequest("invalid application", err)
+		}
 	}
 
 	project, err := h.service.GetAppProject(ctx, input.ArgoCDProjectName, input.ArgoCDNamespace)
@@ -161,7 +314,7 @@ func (h *APIHandler) createAccessRequestHandler(ctx context.Context, input *Crea
 	}
 
 	// Evaluate permissions
-	grantingBinding, err := h.service.GetGrantingAccessBinding(ctx, input.Body.RoleName, input.ArgoCDNamespace, input.Groups(), app, project)
+	grantingBinding, err := h.service.GetGrantingAccessBinding(ctx, input.Body.RoleName, input.ArgoCDNamespace, input.Groups(), input.Body.Group, input.Body.BreakGlass, app, project)
 	if err != nil {
 		return nil, h.loggedError(huma.Error500InternalServerError("error getting access binding", err))
 	}
@@ -170,8 +323,14 @@ func (h *APIHandler) createAccessRequestHandler(ctx context.Context, input *Crea
 	}
 
 	// Create Access Request
-	ar, err = h.service.CreateAccessRequest(ctx, key, grantingBinding)
+	ar, err = h.service.CreateAccessRequest(ctx, key, grantingBinding, details)
 	if err != nil {
+		if errors.Is(err, ErrInvalidAccessRequest) {
+			return nil, huma.Error400BadRequest(err.Error())
+		}
+		if errors.Is(err, api.ErrQuotaExceeded) {
+			return nil, huma.Error429TooManyRequests(err.Error())
+		}
 		return nil, h.loggedError(huma.Error500InternalServerError(fmt.Sprintf("error creating access request for role %s", grantingBinding.Spec.RoleTemplateRef.Name), err))
 	}
 
@@ -179,11 +338,285 @@ func (h *APIHandler) createAccessRequestHandler(ctx context.Context, input *Crea
 
 }
 
+func (h *APIHandler) approveAccessRequestHandler(ctx context.Context, input *ApprovalInput) (*ApprovalResponse, error) {
+	return h.decideAccessRequest(ctx, input, api.ApprovedDecision)
+}
+
+func (h *APIHandler) denyAccessRequestHandler(ctx context.Context, input *ApprovalInput) (*ApprovalResponse, error) {
+	return h.decideAccessRequest(ctx, input, api.DeniedDecision)
+}
+
+// decideAccessRequest will validate if the user identified in the given input
+// is allowed to approve or deny the referenced AccessRequest and record the
+// given decision.
+func (h *APIHandler) decideAccessRequest(ctx context.Context, input *ApprovalInput, decision api.ApprovalDecision) (*ApprovalResponse, error) {
+	appNamespace, appName, err := input.Application()
+	if err != nil {
+		return nil, huma.Error400BadRequest("invalid application", err)
+	}
+
+	ar, err := h.service.GetAccessRequest(ctx, input.Name, input.ArgoCDNamespace)
+	if err != nil {
+		return nil, h.loggedError(huma.Error500InternalServerError(fmt.Sprintf("error retrieving access request %s", input.Name), err))
+	}
+	// access requests from other applications are not visible in this context
+	if ar == nil || !isVisible(ar, appNamespace, appName, input.ArgoCDProjectName) {
+		return nil, huma.Error404NotFound(fmt.Sprintf("access request %s not found", input.Name))
+	}
+
+	if ar.Spec.Approval != nil {
+		return nil, huma.Error409Conflict(fmt.Sprintf("access request already %s by %s", ar.Spec.Approval.Decision, ar.Spec.Approval.Approver))
+	}
+	if ar.Status.RequestState != "" && ar.Status.RequestState != api.RequestedStatus {
+		return nil, huma.Error409Conflict(fmt.Sprintf("access request in %s state can not be %s", ar.Status.RequestState, decision))
+	}
+	if ar.Spec.Subject.Username == input.ArgoCDUsername {
+		return nil, huma.Error403Forbidden("self-approval is not allowed")
+	}
+	if ar.Spec.Subject.Group != "" && slices.Contains(input.Groups(), ar.Spec.Subject.Group) {
+		return nil, huma.Error403Forbidden("members of the elevated group are not allowed to approve")
+	}
+
+	rt, err := h.service.GetRoleTemplate(ctx, ar.Spec.Role.TemplateRef.Name, ar.Spec.Role.TemplateRef.Namespace)
+	if err != nil {
+		return nil, h.loggedError(huma.Error500InternalServerError(fmt.Sprintf("error retrieving role template %s", ar.Spec.Role.TemplateRef.Name), err))
+	}
+	if rt == nil || !rt.RequiresApproval() {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("role %s does not require approval", ar.Spec.Role.TemplateRef.Name))
+	}
+	if !rt.IsApprover(input.ArgoCDUsername, input.Groups()) {
+		return nil, huma.Error403Forbidden(fmt.Sprintf("not allowed to approve requests for role %s", ar.Spec.Role.TemplateRef.Name))
+	}
+
+	approval := &api.Approval{
+		Decision:       decision,
+		Approver:       input.ArgoCDUsername,
+		ApproverGroups: input.Groups(),
+		DecidedAt:      metav1.Now(),
+	}
+	if input.Body != nil {
+		approval.Reason = input.Body.Reason
+	}
+	ar, err = h.service.UpdateAccessRequestApproval(ctx, ar, approval)
+	if err != nil {
+		if apierrors.IsConflict(err) {
+			return nil, huma.Error409Conflict("access request was modified concurrently", err)
+		}
+		return nil, h.loggedError(huma.Error500InternalServerError(fmt.Sprintf("error updating access request %s", input.Name), err))
+	}
+	return &ApprovalResponse{Body: toAccessRequestResponseBody(ar)}, nil
+}
+
+// revokeAccessRequestHandler will signal the controller to revoke the
+// referenced AccessRequest. Only the requester, an approver or a revoker of
+// the requested role are allowed to revoke an AccessRequest.
+func (h *APIHandler) revokeAccessRequestHandler(ctx context.Context, input *RevokeAccessRequestInput) (*RevokeAccessRequestResponse, error) {
+	appNamespace, appName, err := input.Application()
+	if err != nil {
+		return nil, huma.Error400BadRequest("invalid application", err)
+	}
+
+	ar, err := h.service.GetAccessRequest(ctx, input.Name, input.ArgoCDNamespace)
+	if err != nil {
+		return nil, h.loggedError(huma.Error500InternalServerError(fmt.Sprintf("error retrieving access request %s", input.Name), err))
+	}
+	// access requests from other applications are not visible in this context
+	if ar == nil || !isVisible(ar, appNamespace, appName, input.ArgoCDProjectName) {
+		return nil, huma.Error404NotFound(fmt.Sprintf("access request %s not found", input.Name))
+	}
+
+	if ar.Spec.Revocation != nil {
+		return nil, huma.Error409Conflict(fmt.Sprintf("access request already revoked by %s", ar.Spec.Revocation.Revoker))
+	}
+	switch ar.Status.RequestState {
+	case api.DeniedStatus, api.ExpiredStatus, api.InvalidStatus, api.RevokedStatus:
+		return nil, huma.Error409Conflict(fmt.Sprintf("access request in %s state can not be revoked", ar.Status.RequestState))
+	}
+
+	// the requester is always allowed to revoke their own access
+	if ar.Spec.Subject.Username != input.ArgoCDUsername {
+		rt, err := h.service.GetRoleTemplate(ctx, ar.Spec.Role.TemplateRef.Name, ar.Spec.Role.TemplateRef.Namespace)
+		if err != nil {
+			return nil, h.loggedError(huma.Error500InternalServerError(fmt.Sprintf("error retrieving role template %s", ar.Spec.Role.TemplateRef.Name), err))
+		}
+		if rt == nil || !rt.IsRevoker(input.ArgoCDUsername, input.Groups()) {
+			return nil, huma.Error403Forbidden(fmt.Sprintf("not allowed to revoke access request %s", input.Name))
+		}
+	}
+
+	revocation := &api.Revocation{
+		Revoker:   input.ArgoCDUsername,
+		RevokedAt: metav1.Now(),
+	}
+	if input.Body != nil {
+		revocation.Reason = input.Body.Reason
+	}
+	ar, err = h.service.RevokeAccessRequest(ctx, ar, revocation)
+	if err != nil {
+		if apierrors.IsConflict(err) {
+			return nil, huma.Error409Conflict("access request was modified concurrently", err)
+		}
+		return nil, h.loggedError(huma.Error500InternalServerError(fmt.Sprintf("error revoking access request %s", input.Name), err))
+	}
+	return &RevokeAccessRequestResponse{Body: toAccessRequestResponseBody(ar)}, nil
+}
+
+// extendAccessRequestHandler will request additional duration for the
+// referenced AccessRequest. Only the requester is allowed to extend a
+// granted AccessRequest and the total duration can not exceed the maximum
+// duration defined in the associated RoleTemplate.
+func (h *APIHandler) extendAccessRequestHandler(ctx context.Context, input *ExtendAccessRequestInput) (*ExtendAccessRequestResponse, error) {
+	appNamespace, appName, err := input.Application()
+	if err != nil {
+		return nil, huma.Error400BadRequest("invalid application", err)
+	}
+	duration, err := time.ParseDuration(input.Body.Duration)
+	if err != nil || duration <= 0 {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("invalid duration: %q", input.Body.Duration))
+	}
+
+	ar, err := h.service.GetAccessRequest(ctx, input.Name, input.ArgoCDNamespace)
+	if err != nil {
+		return nil, h.loggedError(huma.Error500InternalServerError(fmt.Sprintf("error retrieving access request %s", input.Name), err))
+	}
+	// access requests from other applications are not visible in this context
+	if ar == nil || !isVisible(ar, appNamespace, appName, input.ArgoCDProjectName) {
+		return nil, huma.Error404NotFound(fmt.Sprintf("access request %s not found", input.Name))
+	}
+	if ar.Spec.Subject.Username != input.ArgoCDUsername {
+		return nil, huma.Error403Forbidden(fmt.Sprintf("not allowed to extend access request %s", input.Name))
+	}
+	if ar.Status.RequestState != api.GrantedStatus || ar.IsExpiring() || ar.IsRevoking() {
+		return nil, huma.Error409Conflict("only granted access requests can be extended")
+	}
+	if ar.Spec.BreakGlass {
+		return nil, huma.Error400BadRequest("break-glass access requests can not be extended")
+	}
+
+	rt, err := h.service.GetRoleTemplate(ctx, ar.Spec.Role.TemplateRef.Name, ar.Spec.Role.TemplateRef.Namespace)
+	if err != nil {
+		return nil, h.loggedError(huma.Error500InternalServerError(fmt.Sprintf("error retrieving role template %s", ar.Spec.Role.TemplateRef.Name), err))
+	}
+	if rt == nil || !rt.AllowsExtensions() {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("role %s does not allow extensions", ar.Spec.Role.TemplateRef.Name))
+	}
+	// extensions not processed by the controller yet may still be granted
+	total := ar.GrantedDuration() + ar.PendingExtensionsDuration() + duration
+	if total > rt.Spec.MaxExtendedDuration.Duration {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("total duration %s exceeds the maximum extended duration of %s", total, rt.Spec.MaxExtendedDuration.Duration))
+	}
+
+	extension := &api.Extension{
+		Duration:    metav1.Duration{Duration: duration},
+		Requester:   input.ArgoCDUsername,
+		RequestedAt: metav1.Now(),
+	}
+	ar, err = h.service.ExtendAccessRequest(ctx, ar, extension)
+	if err != nil {
+		if apierrors.IsConflict(err) {
+			return nil, huma.Error409Conflict("access request was modified concurrently", err)
+		}
+		return nil, h.loggedError(huma.Error500InternalServerError(fmt.Sprintf("error extending access request %s", input.Name), err))
+	}
+	return &ExtendAccessRequestResponse{Body: toAccessRequestResponseBody(ar)}, nil
+}
+
+// reviewAccessRequestHandler will record the post-hoc review of the
+// referenced break-glass AccessRequest. Only reviewers of the requested role
+// are allowed to review and the subject can not review their own access.
+func (h *APIHandler) reviewAccessRequestHandler(ctx context.Context, input *ReviewAccessRequestInput) (*ReviewAccessRequestResponse, error) {
+	appNamespace, appName, err := input.Application()
+	if err != nil {
+		return nil, huma.Error400BadRequest("invalid application", err)
+	}
+
+	ar, err := h.service.GetAccessRequest(ctx, input.Name, input.ArgoCDNamespace)
+	if err != nil {
+		return nil, h.loggedError(huma.Error500InternalServerError(fmt.Sprintf("error retrieving access request %s", input.Name), err))
+	}
+	// access requests from other applications are not visible in this context
+	if ar == nil || !isVisible(ar, appNamespace, appName, input.ArgoCDProjectName) {
+		return nil, huma.Error404NotFound(fmt.Sprintf("access request %s not found", input.Name))
+	}
+	if !ar.Spec.BreakGlass {
+		return nil, huma.Error400BadRequest(fmt.Sprintf("access request %s is not a break-glass request", input.Name))
+	}
+	if ar.Spec.Review != nil {
+		return nil, huma.Error409Conflict(fmt.Sprintf("access request already reviewed by %s", ar.Spec.Review.Reviewer))
+	}
+	if ar.Spec.Subject.Username == input.ArgoCDUsername {
+		return nil, huma.Error403Forbidden("self-review is not allowed")
+	}
+	if ar.Spec.Subject.Group != "" && slices.Contains(input.Groups(), ar.Spec.Subject.Group) {
+		return nil, huma.Error403Forbidden("members of the elevated group are not allowed to review")
+	}
+
+	rt, err := h.service.GetRoleTemplate(ctx, ar.Spec.Role.TemplateRef.Name, ar.Spec.Role.TemplateRef.Namespace)
+	if err != nil {
+		return nil, h.loggedError(huma.Error500InternalServerError(fmt.Sprintf("error retrieving role template %s", ar.Spec.Role.TemplateRef.Name), err))
+	}
+	if rt == nil || !rt.IsReviewer(input.ArgoCDUsername, input.Groups()) {
+		return nil, huma.Error403Forbidden(fmt.Sprintf("not allowed to review requests for role %s", ar.Spec.Role.TemplateRef.Name))
+	}
+
+	review := &api.Review{
+		Reviewer:   input.ArgoCDUsername,
+		ReviewedAt: metav1.Now(),
+	}
+	if input.Body != nil {
+		review.Comment = input.Body.Comment
+	}
+	ar, err = h.service.ReviewAccessRequest(ctx, ar, review)
+	if err != nil {
+		if apierrors.IsConflict(err) {
+			return nil, huma.Error409Conflict("access request was modified concurrently", err)
+		}
+		return nil, h.loggedError(huma.Error500InternalServerError(fmt.Sprintf("error reviewing access request %s", input.Name), err))
+	}
+	return &ReviewAccessRequestResponse{Body: toAccessRequestResponseBody(ar)}, nil
+}
+
+func (h *APIHandler) listRolesHandler(ctx context.Context, input *ListRolesInput) (*ListRolesResponse, error) {
+	appNamespace, appName, err := input.Application()
+	if err != nil {
+		return nil, huma.Error400BadRequest("invalid application", err)
+	}
+	app, err := h.service.GetApplication(ctx, appName, appNamespace)
+	if err != nil {
+		return nil, h.loggedError(huma.Error500InternalServerError("error getting application", err))
+	}
+	if app == nil {
+		return nil, huma.Error400BadRequest("invalid application")
+	}
+	project, err := h.service.GetAppProject(ctx, input.ArgoCDProjectName, input.ArgoCDNamespace)
+	if err != nil {
+		return nil, h.loggedError(huma.Error500InternalServerError("error getting project", err))
+	}
+	if project == nil {
+		return nil, huma.Error400BadRequest("invalid project")
+	}
+
+	roles, err := h.service.ListGrantableRoles(ctx, input.ArgoCDNamespace, input.Groups(), app, project)
+	if err != nil {
+		return nil, h.loggedError(huma.Error500InternalServerError(fmt.Sprintf("error listing roles for user %s", input.ArgoCDUsername), err))
+	}
+	return &ListRolesResponse{Body: toListRolesResponseBody(roles)}, nil
+}
+
 func (h *APIHandler) loggedError(err huma.StatusError) huma.StatusError {
 	h.logger.Error(err, "backend error")
 	return err
 }
 
+// isVisible returns true if the given ar targets the application or the
+// project of the current Argo CD context.
+func isVisible(ar *api.AccessRequest, appNamespace, appName, projectName string) bool {
+	if ar.IsProjectScoped() {
+		return ar.Spec.Project.Name == projectName
+	}
+	return ar.Spec.Application.Name == appName && ar.Spec.Application.Namespace == appNamespace
+}
+
 // toAccessRequestResponseBody will convert the given ar into an AccessRequestResponseBody.
 func toAccessRequestResponseBody(ar *api.AccessRequest) AccessRequestResponseBody {
 	expiresAt := ""
@@ -204,21 +637,38 @@ func toAccessRequestResponseBody(ar *api.AccessRequest) AccessRequestResponseBod
 		message = *ar.Status.History[len(ar.Status.History)-1].Details
 	}
 
+	startsAt := ""
+	if ar.Spec.StartsAt != nil {
+		startsAt = ar.Spec.StartsAt.Format(time.RFC3339)
+	}
+
 	permission := ar.Spec.Role.TemplateRef.Name
 	if ar.Spec.Role.FriendlyName != nil {
 		permission = *ar.Spec.Role.FriendlyName
 	}
 
+	project := ""
+	if ar.IsProjectScoped() {
+		project = ar.Spec.Project.Name
+	}
+
 	return AccessRequestResponseBody{
-		Name:        ar.GetName(),
-		Namespace:   ar.GetNamespace(),
-		Username:    ar.Spec.Subject.Username,
-		Permission:  permission,
-		RequestedAt: requestedAt,
-		Role:        ar.Spec.Role.TemplateRef.Name,
-		Status:      strings.ToUpper(string(ar.Status.RequestState)),
-		ExpiresAt:   expiresAt,
-		Message:     message,
+		Name:          ar.GetName(),
+		Namespace:     ar.GetNamespace(),
+		Username:      ar.Spec.Subject.Username,
+		Group:         ar.Spec.Subject.Group,
+		Project:       project,
+		Justification: ar.Spec.Justification,
+		TicketRef:     ar.Spec.TicketRef,
+		Permission:    permission,
+		RequestedAt:   requestedAt,
+		StartsAt:      startsAt,
+		Role:          ar.Spec.Role.TemplateRef.Name,
+		Status:        strings.ToUpper(string(ar.Status.RequestState)),
+		ExpiresAt:     expiresAt,
+		Message:       message,
+		BreakGlass:    ar.Spec.BreakGlass,
+		PendingReview: ar.IsPendingReview(),
 	}
 }
 
@@ -230,6 +680,36 @@ func toListAccessRequestResponseBody(accessRequests []*api.AccessRequest) ListAc
 	return ListAccessRequestResponseBody{Items: items}
 }
 
+// toListRolesResponseBody will convert the given roles into a
+// ListRolesResponseBody.
+func toListRolesResponseBody(roles []GrantableRole) ListRolesResponseBody {
+	items := []RoleResponseBody{}
+	for _, role := range roles {
+		rt := role.RoleTemplate
+		item := RoleResponseBody{
+			Role:                  role.Binding.Spec.RoleTemplateRef.Name,
+			Ordinal:               role.Binding.Spec.Ordinal,
+			Description:           rt.Spec.Description,
+			DefaultDuration:       role.DefaultDuration.String(),
+			RequiresApproval:      rt.RequiresApproval() && !role.Binding.Spec.BreakGlass,
+			JustificationRequired: rt.Spec.Justification != nil && rt.Spec.Justification.Required,
+			GroupTargets:          role.Binding.Spec.GroupTargets,
+			BreakGlass:            role.Binding.Spec.BreakGlass,
+		}
+		if role.Binding.Spec.FriendlyName != nil {
+			item.FriendlyName = *role.Binding.Spec.FriendlyName
+		}
+		if role.MaxDuration > 0 {
+			item.MaxDuration = role.MaxDuration.String()
+		}
+		if rt.AllowsExtensions() && !role.Binding.Spec.BreakGlass {
+			item.MaxExtendedDuration = rt.Spec.MaxExtendedDuration.Duration.String()
+		}
+		items = append(items, item)
+	}
+	return ListRolesResponseBody{Items: items}
+}
+
 // listAccessRequestOperation defines the list access requests operation.
 func listAccessRequestOperation() huma.Operation {
 	return huma.Operation{
@@ -241,6 +721,17 @@ func listAccessRequestOperation() huma.Operation {
 	}
 }
 
+// listRolesOperation defines the list roles operation.
+func listRolesOperation() huma.Operation {
+	return huma.Operation{
+		OperationID: "list-roles",
+		Method:      http.MethodGet,
+		Path:        "/roles",
+		Summary:     "List Roles",
+		Description: "Will retrieve the roles the user is allowed to request for the given context",
+	}
+}
+
 // createAccessRequestOperation defines the create access request operation.
 func createAccessRequestOperation() huma.Operation {
 	return huma.Operation{
@@ -252,9 +743,70 @@ func createAccessRequestOperation() huma.Operation {
 	}
 }
 
+// approveAccessRequestOperation defines the approve access request operation.
+func approveAccessRequestOperation() huma.Operation {
+	return huma.Operation{
+		OperationID: "approve-accessrequest",
+		Method:      http.MethodPost,
+		Path:        "/accessrequests/{name}/approve",
+		Summary:     "Approve AccessRequest",
+		Description: "Will approve the access request if the user is an approver of the requested role",
+	}
+}
+
+// denyAccessRequestOperation defines the deny access request operation.
+func denyAccessRequestOperation() huma.Operation {
+	return huma.Operation{
+		OperationID: "deny-accessrequest",
+		Method:      http.MethodPost,
+		Path:        "/accessrequests/{name}/deny",
+		Summary:     "Deny AccessRequest",
+		Description: "Will deny the access request if the user is an approver of the requested role",
+	}
+}
+
+// revokeAccessRequestOperation defines the revoke access request operation.
+func revokeAccessRequestOperation() huma.Operation {
+	return huma.Operation{
+		OperationID: "revoke-accessrequest",
+		Method:      http.MethodPost,
+		Path:        "/accessrequests/{name}/revoke",
+		Summary:     "Revoke AccessRequest",
+		Description: "Will revoke the access request before its natural expiration",
+	}
+}
+
+// extendAccessRequestOperation defines the extend access request operation.
+func extendAccessRequestOperation() huma.Operation {
+	return huma.Operation{
+		OperationID: "extend-accessrequest",
+		Method:      http.MethodPost,
+		Path:        "/accessrequests/{name}/extend",
+		Summary:     "Extend AccessRequest",
+		Description: "Will request additional duration for a granted access request",
+	}
+}
+
+// reviewAccessRequestOperation defines the review access request operation.
+func reviewAccessRequestOperation() huma.Operation {
+	return huma.Operation{
+		OperationID: "review-accessrequest",
+		Method:      http.MethodPost,
+		Path:        "/accessrequests/{name}/review",
+		Summary:     "Review AccessRequest",
+		Description: "Will record the post-hoc review of a break-glass access request if the user is a reviewer of the requested role",
+	}
+}
+
 // RegisterRoutes will register all routes provided by the access request REST API
 // in the given api.
 func RegisterRoutes(api huma.API, h *APIHandler) {
 	huma.Register(api, listAccessRequestOperation(), h.listAccessRequestHandler)
 	huma.Register(api, createAccessRequestOperation(), h.createAccessRequestHandler)
+	huma.Register(api, approveAccessRequestOperation(), h.approveAccessRequestHandler)
+	huma.Register(api, denyAccessRequestOperation(), h.denyAccessRequestHandler)
+	huma.Register(api, revokeAccessRequestOperation(), h.revokeAccessRequestHandler)
+	huma.Register(api, extendAccessRequestOperation(), h.extendAccessRequestHandler)
+	huma.Register(api, reviewAccessRequestOperation(), h.reviewAccessRequestHandler)
+	huma.Register(api, listRolesOperation(), h.listRolesHandler)
 }
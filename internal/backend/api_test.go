@@ -1,6 +1,7 @@
 package backend_test
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"reflect"
@@ -16,6 +17,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
@@ -81,6 +83,8 @@ func TestApiCreateAccessRequest(t *testing.T) {
 			ApplicationName:      ar.Spec.Application.Name,
 			ApplicationNamespace: ar.Spec.Application.Namespace,
 			Username:             ar.Spec.Subject.Username,
+			ProjectName:          projectName,
+			Groups:               []string{group},
 		}
 		headers := headers(key.Namespace, key.Username, group, key.ApplicationNamespace, key.ApplicationName, projectName)
 		project := &unstructured.Unstructured{}
@@ -88,8 +92,8 @@ func TestApiCreateAccessRequest(t *testing.T) {
 		f.service.EXPECT().GetAccessRequestByRole(mock.Anything, key, roleName).Return(nil, nil)
 		f.service.EXPECT().GetApplication(mock.Anything, key.ApplicationName, key.ApplicationNamespace).Return(app, nil)
 		f.service.EXPECT().GetAppProject(mock.Anything, projectName, key.Namespace).Return(project, nil)
-		f.service.EXPECT().GetGrantingAccessBinding(mock.Anything, roleName, key.Namespace, []string{group}, app, project).Return(arBinding, nil)
-		f.service.EXPECT().CreateAccessRequest(mock.Anything, key, arBinding).Return(ar, nil)
+		f.service.EXPECT().GetGrantingAccessBinding(mock.Anything, roleName, key.Namespace, []string{group}, "", false, app, project).Return(arBinding, nil)
+		f.service.EXPECT().CreateAccessRequest(mock.Anything, key, arBinding, backend.AccessRequestDetails{}).Return(ar, nil)
 
 		// When
 		payload := backend.CreateAccessRequestBody{
@@ -107,6 +111,201 @@ func TestApiCreateAccessRequest(t *testing.T) {
 		assert.Equal(t, ar.GetName(), respBody.Name)
 	})
 
+	t.Run("will create access request with justification successfully", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		projectName := "some-project"
+		roleName := "my-custom-role"
+		group := "group1"
+		ar := utils.NewAccessRequestCreated(utils.WithName("created"))
+		ar.Spec.Justification = "incident response"
+		ar.Spec.TicketRef = "INC-123"
+		arBinding := newDefaultAccessBinding()
+		key := &backend.AccessRequestKey{
+			Namespace:            ar.GetNamespace(),
+			ApplicationName:      ar.Spec.Application.Name,
+			ApplicationNamespace: ar.Spec.Application.Namespace,
+			Username:             ar.Spec.Subject.Username,
+			ProjectName:          projectName,
+			Groups:               []string{group},
+		}
+		headers := headers(key.Namespace, key.Username, group, key.ApplicationNamespace, key.ApplicationName, projectName)
+		project := &unstructured.Unstructured{}
+		app := &unstructured.Unstructured{}
+		f.service.EXPECT().GetAccessRequestByRole(mock.Anything, key, roleName).Return(nil, nil)
+		f.service.EXPECT().GetApplication(mock.Anything, key.ApplicationName, key.ApplicationNamespace).Return(app, nil)
+		f.service.EXPECT().GetAppProject(mock.Anything, projectName, key.Namespace).Return(project, nil)
+		f.service.EXPECT().GetGrantingAccessBinding(mock.Anything, roleName, key.Namespace, []string{group}, "", false, app, project).Return(arBinding, nil)
+		f.service.EXPECT().CreateAccessRequest(mock.Anything, key, arBinding, backend.AccessRequestDetails{Justification: "incident response", TicketRef: "INC-123"}).Return(ar, nil)
+
+		// When
+		payload := backend.CreateAccessRequestBody{
+			RoleName:      roleName,
+			Justification: "incident response",
+			TicketRef:     "INC-123",
+		}
+		resp := f.api.Post("/accessrequests", append(headers, payload)...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 200, resp.Result().StatusCode)
+		var respBody backend.AccessRequestResponseBody
+		err := json.Unmarshal(resp.Body.Bytes(), &respBody)
+		assert.NoError(t, err)
+		assert.Equal(t, "incident response", respBody.Justification)
+		assert.Equal(t, "INC-123", respBody.TicketRef)
+	})
+	t.Run("will create access request with the requested duration", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		projectName := "some-project"
+		roleName := "my-custom-role"
+		group := "group1"
+		ar := utils.NewAccessRequestCreated(utils.WithName("created"))
+		arBinding := newDefaultAccessBinding()
+		key := &backend.AccessRequestKey{
+			Namespace:            ar.GetNamespace(),
+			ApplicationName:      ar.Spec.Application.Name,
+			ApplicationNamespace: ar.Spec.Application.Namespace,
+			Username:             ar.Spec.Subject.Username,
+			ProjectName:          projectName,
+			Groups:               []string{group},
+		}
+		headers := headers(key.Namespace, key.Username, group, key.ApplicationNamespace, key.ApplicationName, projectName)
+		project := &unstructured.Unstructured{}
+		app := &unstructured.Unstructured{}
+		f.service.EXPECT().GetAccessRequestByRole(mock.Anything, key, roleName).Return(nil, nil)
+		f.service.EXPECT().GetApplication(mock.Anything, key.ApplicationName, key.ApplicationNamespace).Return(app, nil)
+		f.service.EXPECT().GetAppProject(mock.Anything, projectName, key.Namespace).Return(project, nil)
+		f.service.EXPECT().GetGrantingAccessBinding(mock.Anything, roleName, key.Namespace, []string{group}, "", false, app, project).Return(arBinding, nil)
+		f.service.EXPECT().CreateAccessRequest(mock.Anything, key, arBinding, backend.AccessRequestDetails{Duration: 2 * time.Hour}).Return(ar, nil)
+
+		// When
+		payload := backend.CreateAccessRequestBody{
+			RoleName: roleName,
+			Duration: "2h",
+		}
+		resp := f.api.Post("/accessrequests", append(headers, payload)...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 200, resp.Result().StatusCode)
+	})
+	t.Run("will return 400 on invalid duration", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		headers := headers("some-namespace", "some-user", "group1", "app-ns", "some-app", "some-project")
+
+		// When
+		payload := backend.CreateAccessRequestBody{
+			RoleName: "my-custom-role",
+			Duration: "-1h",
+		}
+		resp := f.api.Post("/accessrequests", append(headers, payload)...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 400, resp.Result().StatusCode)
+		assert.Contains(t, resp.Body.String(), "invalid duration")
+	})
+	t.Run("will create project-scoped access request successfully", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		projectName := "some-project"
+		roleName := "my-custom-role"
+		group := "group1"
+		ar := utils.NewAccessRequestCreated(utils.WithName("created"))
+		ar.Spec.Application = api.TargetApplication{}
+		ar.Spec.Project = &api.TargetAppProject{Name: projectName}
+		arBinding := newDefaultAccessBinding()
+		key := &backend.AccessRequestKey{
+			Namespace:   ar.GetNamespace(),
+			Username:    ar.Spec.Subject.Username,
+			ProjectName: projectName,
+			Groups:      []string{group},
+		}
+		headers := headers(key.Namespace, key.Username, group, "app-ns", "some-app", projectName)
+		project := &unstructured.Unstructured{}
+		f.service.EXPECT().GetAccessRequestByRole(mock.Anything, key, roleName).Return(nil, nil)
+		f.service.EXPECT().GetAppProject(mock.Anything, projectName, key.Namespace).Return(project, nil)
+		f.service.EXPECT().GetGrantingAccessBinding(mock.Anything, roleName, key.Namespace, []string{group}, "", false, (*unstructured.Unstructured)(nil), project).Return(arBinding, nil)
+		f.service.EXPECT().CreateAccessRequest(mock.Anything, key, arBinding, backend.AccessRequestDetails{}).Return(ar, nil)
+
+		// When
+		payload := backend.CreateAccessRequestBody{
+			RoleName: roleName,
+			Scope:    "project",
+		}
+		resp := f.api.Post("/accessrequests", append(headers, payload)...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 200, resp.Result().StatusCode)
+		var respBody backend.AccessRequestResponseBody
+		err := json.Unmarshal(resp.Body.Bytes(), &respBody)
+		assert.NoError(t, err)
+		assert.Equal(t, projectName, respBody.Project)
+		f.service.AssertNotCalled(t, "GetApplication", mock.Anything, mock.Anything, mock.Anything)
+	})
+	t.Run("will return 422 on invalid scope", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		headers := headers("some-namespace", "some-user", "group1", "app-ns", "some-app", "some-project")
+
+		// When
+		payload := backend.CreateAccessRequestBody{
+			RoleName: "my-custom-role",
+			Scope:    "cluster",
+		}
+		resp := f.api.Post("/accessrequests", append(headers, payload)...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 422, resp.Result().StatusCode)
+	})
+	t.Run("will create group access request successfully", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		projectName := "some-project"
+		roleName := "my-custom-role"
+		group := "group1"
+		ar := utils.NewAccessRequestCreated(utils.WithName("created"))
+		ar.Spec.Subject.Group = "on-call"
+		arBinding := newDefaultAccessBinding()
+		arBinding.Spec.GroupTargets = []string{"on-call"}
+		key := &backend.AccessRequestKey{
+			Namespace:            ar.GetNamespace(),
+			ApplicationName:      ar.Spec.Application.Name,
+			ApplicationNamespace: ar.Spec.Application.Namespace,
+			Username:             ar.Spec.Subject.Username,
+			Group:                "on-call",
+			ProjectName:          projectName,
+			Groups:               []string{group},
+		}
+		headers := headers(key.Namespace, key.Username, group, key.ApplicationNamespace, key.ApplicationName, projectName)
+		project := &unstructured.Unstructured{}
+		app := &unstructured.Unstructured{}
+		f.service.EXPECT().GetAccessRequestByRole(mock.Anything, key, roleName).Return(nil, nil)
+		f.service.EXPECT().GetApplication(mock.Anything, key.ApplicationName, key.ApplicationNamespace).Return(app, nil)
+		f.service.EXPECT().GetAppProject(mock.Anything, projectName, key.Namespace).Return(project, nil)
+		f.service.EXPECT().GetGrantingAccessBinding(mock.Anything, roleName, key.Namespace, []string{group}, "on-call", false, app, project).Return(arBinding, nil)
+		f.service.EXPECT().CreateAccessRequest(mock.Anything, key, arBinding, backend.AccessRequestDetails{}).Return(ar, nil)
+
+		// When
+		payload := backend.CreateAccessRequestBody{
+			RoleName: roleName,
+			Group:    "on-call",
+		}
+		resp := f.api.Post("/accessrequests", append(headers, payload)...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 200, resp.Result().StatusCode)
+		var respBody backend.AccessRequestResponseBody
+		err := json.Unmarshal(resp.Body.Bytes(), &respBody)
+		assert.NoError(t, err)
+		assert.Equal(t, "on-call", respBody.Group)
+	})
 	t.Run("will return 422 on invalid headers", func(t *testing.T) {
 		// Given
 		f := apiSetup(t)
@@ -116,6 +315,8 @@ func TestApiCreateAccessRequest(t *testing.T) {
 			ApplicationName:      "some-app:with-invalid-colon",
 			ApplicationNamespace: "app-ns",
 			Username:             "some-user",
+			ProjectName:          "some-project",
+			Groups:               []string{"group1"},
 		}
 		headers := headers(key.Namespace, key.Username, "group1", key.ApplicationNamespace, key.ApplicationName, "some-project")
 		headers = headers[1:]
@@ -139,6 +340,8 @@ func TestApiCreateAccessRequest(t *testing.T) {
 			ApplicationName:      "some-app:with-invalid-colon",
 			ApplicationNamespace: "app-ns",
 			Username:             "some-user",
+			ProjectName:          "some-project",
+			Groups:               []string{"group1"},
 		}
 		headers := headers(key.Namespace, key.Username, "group1", key.ApplicationNamespace, key.ApplicationName, "some-project")
 
@@ -164,6 +367,8 @@ func TestApiCreateAccessRequest(t *testing.T) {
 			ApplicationName:      ar.Spec.Application.Name,
 			ApplicationNamespace: ar.Spec.Application.Namespace,
 			Username:             ar.Spec.Subject.Username,
+			ProjectName:          projectName,
+			Groups:               []string{group},
 		}
 		headers := headers(key.Namespace, key.Username, group, key.ApplicationNamespace, key.ApplicationName, projectName)
 		f.service.EXPECT().GetAccessRequestByRole(mock.Anything, key, roleName).Return(nil, nil)
@@ -191,6 +396,8 @@ func TestApiCreateAccessRequest(t *testing.T) {
 			ApplicationName:      ar.Spec.Application.Name,
 			ApplicationNamespace: ar.Spec.Application.Namespace,
 			Username:             ar.Spec.Subject.Username,
+			ProjectName:          projectName,
+			Groups:               []string{group},
 		}
 		headers := headers(key.Namespace, key.Username, group, key.ApplicationNamespace, key.ApplicationName, projectName)
 		app := &unstructured.Unstructured{}
@@ -220,6 +427,8 @@ func TestApiCreateAccessRequest(t *testing.T) {
 			ApplicationName:      ar.Spec.Application.Name,
 			ApplicationNamespace: ar.Spec.Application.Namespace,
 			Username:             ar.Spec.Subject.Username,
+			ProjectName:          projectName,
+			Groups:               []string{group},
 		}
 		headers := headers(key.Namespace, key.Username, group, key.ApplicationNamespace, key.ApplicationName, projectName)
 		f.service.EXPECT().GetAccessRequestByRole(mock.Anything, key, roleName).Return(ar, nil)
@@ -246,6 +455,8 @@ func TestApiCreateAccessRequest(t *testing.T) {
 			ApplicationName:      ar.Spec.Application.Name,
 			ApplicationNamespace: ar.Spec.Application.Namespace,
 			Username:             ar.Spec.Subject.Username,
+			ProjectName:          projectName,
+			Groups:               []string{group},
 		}
 		headers := headers(key.Namespace, key.Username, group, key.ApplicationNamespace, key.ApplicationName, projectName)
 		project := &unstructured.Unstructured{}
@@ -253,7 +464,7 @@ func TestApiCreateAccessRequest(t *testing.T) {
 		f.service.EXPECT().GetAccessRequestByRole(mock.Anything, key, roleName).Return(nil, nil)
 		f.service.EXPECT().GetApplication(mock.Anything, key.ApplicationName, key.ApplicationNamespace).Return(app, nil)
 		f.service.EXPECT().GetAppProject(mock.Anything, projectName, key.Namespace).Return(project, nil)
-		f.service.EXPECT().GetGrantingAccessBinding(mock.Anything, roleName, key.Namespace, []string{group}, app, project).Return(nil, nil)
+		f.service.EXPECT().GetGrantingAccessBinding(mock.Anything, roleName, key.Namespace, []string{group}, "", false, app, project).Return(nil, nil)
 
 		// When
 		payload := backend.CreateAccessRequestBody{
@@ -277,6 +488,8 @@ func TestApiCreateAccessRequest(t *testing.T) {
 			ApplicationName:      ar.Spec.Application.Name,
 			ApplicationNamespace: ar.Spec.Application.Namespace,
 			Username:             ar.Spec.Subject.Username,
+			ProjectName:          projectName,
+			Groups:               []string{group},
 		}
 		headers := headers(key.Namespace, key.Username, group, key.ApplicationNamespace, key.ApplicationName, projectName)
 		f.service.EXPECT().GetAccessRequestByRole(mock.Anything, key, roleName).Return(nil, nil)
@@ -305,6 +518,8 @@ func TestApiCreateAccessRequest(t *testing.T) {
 			ApplicationName:      ar.Spec.Application.Name,
 			ApplicationNamespace: ar.Spec.Application.Namespace,
 			Username:             ar.Spec.Subject.Username,
+			ProjectName:          projectName,
+			Groups:               []string{group},
 		}
 		headers := headers(key.Namespace, key.Username, group, key.ApplicationNamespace, key.ApplicationName, projectName)
 		app := &unstructured.Unstructured{}
@@ -335,6 +550,8 @@ func TestApiCreateAccessRequest(t *testing.T) {
 			ApplicationName:      ar.Spec.Application.Name,
 			ApplicationNamespace: ar.Spec.Application.Namespace,
 			Username:             ar.Spec.Subject.Username,
+			ProjectName:          projectName,
+			Groups:               []string{group},
 		}
 		headers := headers(key.Namespace, key.Username, group, key.ApplicationNamespace, key.ApplicationName, projectName)
 		project := &unstructured.Unstructured{}
@@ -342,7 +559,7 @@ func TestApiCreateAccessRequest(t *testing.T) {
 		f.service.EXPECT().GetAccessRequestByRole(mock.Anything, key, roleName).Return(nil, nil)
 		f.service.EXPECT().GetApplication(mock.Anything, key.ApplicationName, key.ApplicationNamespace).Return(app, nil)
 		f.service.EXPECT().GetAppProject(mock.Anything, projectName, key.Namespace).Return(project, nil)
-		f.service.EXPECT().GetGrantingAccessBinding(mock.Anything, roleName, key.Namespace, []string{group}, app, project).Return(nil, fmt.Errorf("some-error"))
+		f.service.EXPECT().GetGrantingAccessBinding(mock.Anything, roleName, key.Namespace, []string{group}, "", false, app, project).Return(nil, fmt.Errorf("some-error"))
 		f.logger.EXPECT().Error(mock.Anything, mock.Anything)
 
 		// When
@@ -367,6 +584,8 @@ func TestApiCreateAccessRequest(t *testing.T) {
 			ApplicationName:      ar.Spec.Application.Name,
 			ApplicationNamespace: ar.Spec.Application.Namespace,
 			Username:             ar.Spec.Subject.Username,
+			ProjectName:          projectName,
+			Groups:               []string{group},
 		}
 		headers := headers(key.Namespace, key.Username, group, key.ApplicationNamespace, key.ApplicationName, projectName)
 		f.service.EXPECT().GetAccessRequestByRole(mock.Anything, key, roleName).Return(nil, fmt.Errorf("some-error"))
@@ -395,6 +614,8 @@ func TestApiCreateAccessRequest(t *testing.T) {
 			ApplicationName:      ar.Spec.Application.Name,
 			ApplicationNamespace: ar.Spec.Application.Namespace,
 			Username:             ar.Spec.Subject.Username,
+			ProjectName:          projectName,
+			Groups:               []string{group},
 		}
 		headers := headers(key.Namespace, key.Username, group, key.ApplicationNamespace, key.ApplicationName, projectName)
 		project := &unstructured.Unstructured{}
@@ -402,8 +623,8 @@ func TestApiCreateAccessRequest(t *testing.T) {
 		f.service.EXPECT().GetAccessRequestByRole(mock.Anything, key, roleName).Return(nil, nil)
 		f.service.EXPECT().GetApplication(mock.Anything, key.ApplicationName, key.ApplicationNamespace).Return(app, nil)
 		f.service.EXPECT().GetAppProject(mock.Anything, projectName, key.Namespace).Return(project, nil)
-		f.service.EXPECT().GetGrantingAccessBinding(mock.Anything, roleName, key.Namespace, []string{group}, app, project).Return(arBinding, nil)
-		f.service.EXPECT().CreateAccessRequest(mock.Anything, key, arBinding).Return(nil, fmt.Errorf("some-error"))
+		f.service.EXPECT().GetGrantingAccessBinding(mock.Anything, roleName, key.Namespace, []string{group}, "", false, app, project).Return(arBinding, nil)
+		f.service.EXPECT().CreateAccessRequest(mock.Anything, key, arBinding, backend.AccessRequestDetails{}).Return(nil, fmt.Errorf("some-error"))
 		f.logger.EXPECT().Error(mock.Anything, mock.Anything)
 
 		// When
@@ -416,103 +637,287 @@ func TestApiCreateAccessRequest(t *testing.T) {
 		assert.NotNil(t, resp)
 		assert.Equal(t, 500, resp.Result().StatusCode)
 	})
-}
-
-func TestApiListAccessRequest(t *testing.T) {
-	t.Run("will return access requests successfully", func(t *testing.T) {
+	t.Run("will create scheduled access request", func(t *testing.T) {
 		// Given
 		f := apiSetup(t)
-		ar1 := utils.NewAccessRequestRequested(utils.WithName("first"))
-		ar2 := utils.NewAccessRequestRequested(utils.WithName("second"))
+		projectName := "some-project"
+		roleName := "my-custom-role"
+		group := "group1"
+		startsAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+		ar := utils.NewAccessRequestCreated(utils.WithName("created"))
+		ar.Spec.StartsAt = &metav1.Time{Time: startsAt}
+		arBinding := newDefaultAccessBinding()
 		key := &backend.AccessRequestKey{
-			Namespace:            ar1.GetNamespace(),
-			ApplicationName:      ar1.Spec.Application.Name,
-			ApplicationNamespace: ar1.Spec.Application.Namespace,
-			Username:             ar1.Spec.Subject.Username,
+			Namespace:            ar.GetNamespace(),
+			ApplicationName:      ar.Spec.Application.Name,
+			ApplicationNamespace: ar.Spec.Application.Namespace,
+			Username:             ar.Spec.Subject.Username,
+			ProjectName:          projectName,
+			Groups:               []string{group},
 		}
-		headers := headers(key.Namespace, key.Username, "group1", key.ApplicationNamespace, key.ApplicationName, "some-project")
-		f.service.EXPECT().ListAccessRequests(mock.Anything, key, true).Return([]*api.AccessRequest{ar1, ar2}, nil)
+		headers := headers(key.Namespace, key.Username, group, key.ApplicationNamespace, key.ApplicationName, projectName)
+		project := &unstructured.Unstructured{}
+		app := &unstructured.Unstructured{}
+		f.service.EXPECT().GetAccessRequestByRole(mock.Anything, key, roleName).Return(nil, nil)
+		f.service.EXPECT().GetApplication(mock.Anything, key.ApplicationName, key.ApplicationNamespace).Return(app, nil)
+		f.service.EXPECT().GetAppProject(mock.Anything, projectName, key.Namespace).Return(project, nil)
+		f.service.EXPECT().GetGrantingAccessBinding(mock.Anything, roleName, key.Namespace, []string{group}, "", false, app, project).Return(arBinding, nil)
+		f.service.EXPECT().CreateAccessRequest(mock.Anything, key, arBinding, backend.AccessRequestDetails{StartsAt: &startsAt}).Return(ar, nil)
 
 		// When
-		resp := f.api.Get("/accessrequests", headers...)
+		payload := backend.CreateAccessRequestBody{
+			RoleName: roleName,
+			StartsAt: startsAt.Format(time.RFC3339),
+		}
+		resp := f.api.Post("/accessrequests", append(headers, payload)...)
 
 		// Then
 		assert.NotNil(t, resp)
 		assert.Equal(t, 200, resp.Result().StatusCode)
-		var respBody backend.ListAccessRequestResponseBody
+		var respBody backend.AccessRequestResponseBody
 		err := json.Unmarshal(resp.Body.Bytes(), &respBody)
 		assert.NoError(t, err)
-		require.Equal(t, 2, len(respBody.Items))
-		assert.Equal(t, ar1.GetNamespace(), respBody.Items[0].Namespace)
-		assert.Equal(t, ar1.GetName(), respBody.Items[0].Name)
-		assert.Equal(t, ar2.GetNamespace(), respBody.Items[1].Namespace)
-		assert.Equal(t, ar2.GetName(), respBody.Items[1].Name)
+		assert.Equal(t, "2030-01-02T03:04:05Z", respBody.StartsAt)
 	})
-	t.Run("will return 422 on invalid headers", func(t *testing.T) {
+	t.Run("will return 422 on invalid startsAt", func(t *testing.T) {
 		// Given
 		f := apiSetup(t)
-		key := &backend.AccessRequestKey{
-			Namespace:            "some-namespace",
-			ApplicationName:      "some-app:with-invalid-colon",
-			ApplicationNamespace: "app-ns",
-			Username:             "some-user",
-		}
-		headers := headers(key.Namespace, key.Username, "group1", key.ApplicationNamespace, key.ApplicationName, "some-project")
-		headers = headers[1:]
+		headers := headers("some-namespace", "some-user", "group1", "app-ns", "some-app", "some-project")
 
 		// When
-		resp := f.api.Get("/accessrequests", headers...)
+		payload := backend.CreateAccessRequestBody{
+			RoleName: "my-custom-role",
+			StartsAt: "tomorrow",
+		}
+		resp := f.api.Post("/accessrequests", append(headers, payload)...)
 
 		// Then
 		assert.NotNil(t, resp)
 		assert.Equal(t, 422, resp.Result().StatusCode)
 	})
-	t.Run("will return 400 on invalid header format", func(t *testing.T) {
+	t.Run("will return 400 if service returns invalid access request error", func(t *testing.T) {
 		// Given
 		f := apiSetup(t)
+		projectName := "some-project"
+		roleName := "my-custom-role"
+		group := "group1"
+		startsAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+		ar := utils.NewAccessRequestCreated(utils.WithName("created"))
+		arBinding := newDefaultAccessBinding()
 		key := &backend.AccessRequestKey{
-			Namespace:            "some-namespace",
-			ApplicationName:      "some-app:with-invalid-colon",
-			ApplicationNamespace: "app-ns",
-			Username:             "some-user",
+			Namespace:            ar.GetNamespace(),
+			ApplicationName:      ar.Spec.Application.Name,
+			ApplicationNamespace: ar.Spec.Application.Namespace,
+			Username:             ar.Spec.Subject.Username,
+			ProjectName:          projectName,
+			Groups:               []string{group},
 		}
-		headers := headers(key.Namespace, key.Username, "group1", key.ApplicationNamespace, key.ApplicationName, "some-project")
+		headers := headers(key.Namespace, key.Username, group, key.ApplicationNamespace, key.ApplicationName, projectName)
+		project := &unstructured.Unstructured{}
+		app := &unstructured.Unstructured{}
+		f.service.EXPECT().GetAccessRequestByRole(mock.Anything, key, roleName).Return(nil, nil)
+		f.service.EXPECT().GetApplication(mock.Anything, key.ApplicationName, key.ApplicationNamespace).Return(app, nil)
+		f.service.EXPECT().GetAppProject(mock.Anything, projectName, key.Namespace).Return(project, nil)
+		f.service.EXPECT().GetGrantingAccessBinding(mock.Anything, roleName, key.Namespace, []string{group}, "", false, app, project).Return(arBinding, nil)
+		f.service.EXPECT().CreateAccessRequest(mock.Anything, key, arBinding, backend.AccessRequestDetails{StartsAt: &startsAt}).
+			Return(nil, fmt.Errorf("%w: some validation error", backend.ErrInvalidAccessRequest))
 
 		// When
-		resp := f.api.Get("/accessrequests", headers...)
+		payload := backend.CreateAccessRequestBody{
+			RoleName: roleName,
+			StartsAt: startsAt.Format(time.RFC3339),
+		}
+		resp := f.api.Post("/accessrequests", append(headers, payload)...)
 
 		// Then
 		assert.NotNil(t, resp)
 		assert.Equal(t, 400, resp.Result().StatusCode)
+		assert.Contains(t, resp.Body.String(), "some validation error")
 	})
-	t.Run("will return 500 on service error", func(t *testing.T) {
+	t.Run("will return 429 if the quota is exceeded", func(t *testing.T) {
 		// Given
 		f := apiSetup(t)
+		projectName := "some-project"
+		roleName := "my-custom-role"
+		group := "group1"
+		startsAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+		ar := utils.NewAccessRequestCreated(utils.WithName("created"))
+		arBinding := newDefaultAccessBinding()
 		key := &backend.AccessRequestKey{
-			Namespace:            "some-namespace",
-			ApplicationName:      "some-app",
-			ApplicationNamespace: "app-ns",
-			Username:             "some-user",
+			Namespace:            ar.GetNamespace(),
+			ApplicationName:      ar.Spec.Application.Name,
+			ApplicationNamespace: ar.Spec.Application.Namespace,
+			Username:             ar.Spec.Subject.Username,
+			ProjectName:          projectName,
+			Groups:               []string{group},
 		}
-		headers := headers(key.Namespace, key.Username, "group1", key.ApplicationNamespace, key.ApplicationName, "some-project")
-		f.service.EXPECT().ListAccessRequests(mock.Anything, key, mock.Anything).Return(nil, fmt.Errorf("some-error"))
-		f.logger.EXPECT().Error(mock.Anything, mock.Anything)
+		headers := headers(key.Namespace, key.Username, group, key.ApplicationNamespace, key.ApplicationName, projectName)
+		project := &unstructured.Unstructured{}
+		app := &unstructured.Unstructured{}
+		f.service.EXPECT().GetAccessRequestByRole(mock.Anything, key, roleName).Return(nil, nil)
+		f.service.EXPECT().GetApplication(mock.Anything, key.ApplicationName, key.ApplicationNamespace).Return(app, nil)
+		f.service.EXPECT().GetAppProject(mock.Anything, projectName, key.Namespace).Return(project, nil)
+		f.service.EXPECT().GetGrantingAccessBinding(mock.Anything, roleName, key.Namespace, []string{group}, "", false, app, project).Return(arBinding, nil)
+		f.service.EXPECT().CreateAccessRequest(mock.Anything, key, arBinding, backend.AccessRequestDetails{StartsAt: &startsAt}).
+			Return(nil, fmt.Errorf("%w: user quota allows at most 1 concurrent requests", api.ErrQuotaExceeded))
 
 		// When
-		resp := f.api.Get("/accessrequests", headers...)
+		payload := backend.CreateAccessRequestBody{
+			RoleName: roleName,
+			StartsAt: startsAt.Format(time.RFC3339),
+		}
+		resp := f.api.Post("/accessrequests", append(headers, payload)...)
 
 		// Then
 		assert.NotNil(t, resp)
-		assert.Equal(t, 500, resp.Result().StatusCode)
+		assert.Equal(t, 429, resp.Result().StatusCode)
+		assert.Contains(t, resp.Body.String(), "quota exceeded")
 	})
-	t.Run("will return empty list if no access request exist", func(t *testing.T) {
+	t.Run("will create break-glass access request with break-glass binding", func(t *testing.T) {
 		// Given
 		f := apiSetup(t)
-		key := &backend.AccessRequestKey{
-			Namespace:            "some-namespace",
-			ApplicationName:      "some-app",
+		projectName := "some-project"
+		roleName := "my-custom-role"
+		group := "group1"
+		ar := utils.NewAccessRequestCreated(utils.WithName("created"))
+		ar.Spec.BreakGlass = true
+		arBinding := newDefaultAccessBinding()
+		arBinding.Spec.BreakGlass = true
+		key := &backend.AccessRequestKey{
+			Namespace:            ar.GetNamespace(),
+			ApplicationName:      ar.Spec.Application.Name,
+			ApplicationNamespace: ar.Spec.Application.Namespace,
+			Username:             ar.Spec.Subject.Username,
+			ProjectName:          projectName,
+			Groups:               []string{group},
+		}
+		headers := headers(key.Namespace, key.Username, group, key.ApplicationNamespace, key.ApplicationName, projectName)
+		project := &unstructured.Unstructured{}
+		app := &unstructured.Unstructured{}
+		f.service.EXPECT().GetAccessRequestByRole(mock.Anything, key, roleName).Return(nil, nil)
+		f.service.EXPECT().GetApplication(mock.Anything, key.ApplicationName, key.ApplicationNamespace).Return(app, nil)
+		f.service.EXPECT().GetAppProject(mock.Anything, projectName, key.Namespace).Return(project, nil)
+		f.service.EXPECT().GetGrantingAccessBinding(mock.Anything, roleName, key.Namespace, []string{group}, "", true, app, project).Return(arBinding, nil)
+		f.service.EXPECT().CreateAccessRequest(mock.Anything, key, arBinding, backend.AccessRequestDetails{BreakGlass: true}).Return(ar, nil)
+
+		// When
+		payload := backend.CreateAccessRequestBody{
+			RoleName:   roleName,
+			BreakGlass: true,
+		}
+		resp := f.api.Post("/accessrequests", append(headers, payload)...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 200, resp.Result().StatusCode)
+		var respBody backend.AccessRequestResponseBody
+		err := json.Unmarshal(resp.Body.Bytes(), &respBody)
+		assert.NoError(t, err)
+		assert.True(t, respBody.BreakGlass)
+		assert.True(t, respBody.PendingReview)
+	})
+}
+
+func TestApiListAccessRequest(t *testing.T) {
+	t.Run("will return access requests successfully", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		ar1 := utils.NewAccessRequestRequested(utils.WithName("first"))
+		ar2 := utils.NewAccessRequestRequested(utils.WithName("second"))
+		key := &backend.AccessRequestKey{
+			Namespace:            ar1.GetNamespace(),
+			ApplicationName:      ar1.Spec.Application.Name,
+			ApplicationNamespace: ar1.Spec.Application.Namespace,
+			Username:             ar1.Spec.Subject.Username,
+			ProjectName:          "some-project",
+		}
+		headers := headers(key.Namespace, key.Username, "group1", key.ApplicationNamespace, key.ApplicationName, "some-project")
+		f.service.EXPECT().ListAccessRequests(mock.Anything, key, true).Return([]*api.AccessRequest{ar1, ar2}, nil)
+
+		// When
+		resp := f.api.Get("/accessrequests", headers...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 200, resp.Result().StatusCode)
+		var respBody backend.ListAccessRequestResponseBody
+		err := json.Unmarshal(resp.Body.Bytes(), &respBody)
+		assert.NoError(t, err)
+		require.Equal(t, 2, len(respBody.Items))
+		assert.Equal(t, ar1.GetNamespace(), respBody.Items[0].Namespace)
+		assert.Equal(t, ar1.GetName(), respBody.Items[0].Name)
+		assert.Equal(t, ar2.GetNamespace(), respBody.Items[1].Namespace)
+		assert.Equal(t, ar2.GetName(), respBody.Items[1].Name)
+	})
+	t.Run("will return 422 on invalid headers", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		key := &backend.AccessRequestKey{
+			Namespace:            "some-namespace",
+			ApplicationName:      "some-app:with-invalid-colon",
+			ApplicationNamespace: "app-ns",
+			Username:             "some-user",
+			ProjectName:          "some-project",
+		}
+		headers := headers(key.Namespace, key.Username, "group1", key.ApplicationNamespace, key.ApplicationName, "some-project")
+		headers = headers[1:]
+
+		// When
+		resp := f.api.Get("/accessrequests", headers...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 422, resp.Result().StatusCode)
+	})
+	t.Run("will return 400 on invalid header format", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		key := &backend.AccessRequestKey{
+			Namespace:            "some-namespace",
+			ApplicationName:      "some-app:with-invalid-colon",
+			ApplicationNamespace: "app-ns",
+			Username:             "some-user",
+			ProjectName:          "some-project",
+		}
+		headers := headers(key.Namespace, key.Username, "group1", key.ApplicationNamespace, key.ApplicationName, "some-project")
+
+		// When
+		resp := f.api.Get("/accessrequests", headers...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 400, resp.Result().StatusCode)
+	})
+	t.Run("will return 500 on service error", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		key := &backend.AccessRequestKey{
+			Namespace:            "some-namespace",
+			ApplicationName:      "some-app",
+			ApplicationNamespace: "app-ns",
+			Username:             "some-user",
+			ProjectName:          "some-project",
+		}
+		headers := headers(key.Namespace, key.Username, "group1", key.ApplicationNamespace, key.ApplicationName, "some-project")
+		f.service.EXPECT().ListAccessRequests(mock.Anything, key, mock.Anything).Return(nil, fmt.Errorf("some-error"))
+		f.logger.EXPECT().Error(mock.Anything, mock.Anything)
+
+		// When
+		resp := f.api.Get("/accessrequests", headers...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 500, resp.Result().StatusCode)
+	})
+	t.Run("will return empty list if no access request exist", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		key := &backend.AccessRequestKey{
+			Namespace:            "some-namespace",
+			ApplicationName:      "some-app",
 			ApplicationNamespace: "app-ns",
 			Username:             "some-user",
+			ProjectName:          "some-project",
 		}
 		headers := headers(key.Namespace, key.Username, "group1", key.ApplicationNamespace, key.ApplicationName, "some-project")
 		f.service.EXPECT().ListAccessRequests(mock.Anything, key, mock.Anything).Return(nil, nil)
@@ -531,6 +936,789 @@ func TestApiListAccessRequest(t *testing.T) {
 
 }
 
+func TestApiApproveAccessRequest(t *testing.T) {
+	newApprovalRoleTemplate := func(approvers ...string) *api.RoleTemplate {
+		rt := utils.NewRoleTemplate("role-template-name", "ephemeral", "some-role", []string{"some-policy"})
+		rt.Spec.Approval = &api.ApprovalSpec{Approvers: approvers}
+		return rt
+	}
+	t.Run("will approve access request successfully", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		ar := utils.NewAccessRequestRequested(utils.WithName("requested"))
+		rt := newApprovalRoleTemplate("approvers")
+		headers := headers(ar.GetNamespace(), "approver@user.com", "approvers", ar.Spec.Application.Namespace, ar.Spec.Application.Name, "some-project")
+		f.service.EXPECT().GetAccessRequest(mock.Anything, ar.GetName(), ar.GetNamespace()).Return(ar, nil)
+		f.service.EXPECT().GetRoleTemplate(mock.Anything, rt.GetName(), rt.GetNamespace()).Return(rt, nil)
+		f.service.EXPECT().UpdateAccessRequestApproval(mock.Anything, ar, mock.Anything).
+			RunAndReturn(func(_ context.Context, ar *api.AccessRequest, approval *api.Approval) (*api.AccessRequest, error) {
+				assert.Equal(t, api.ApprovedDecision, approval.Decision)
+				assert.Equal(t, "approver@user.com", approval.Approver)
+				assert.Equal(t, []string{"approvers"}, approval.ApproverGroups)
+				assert.Equal(t, "some reason", approval.Reason)
+				updated := ar.DeepCopy()
+				updated.Spec.Approval = approval
+				return updated, nil
+			})
+
+		// When
+		payload := backend.ApprovalBody{Reason: "some reason"}
+		resp := f.api.Post("/accessrequests/requested/approve", append(headers, payload)...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 200, resp.Result().StatusCode)
+		var respBody backend.AccessRequestResponseBody
+		err := json.Unmarshal(resp.Body.Bytes(), &respBody)
+		assert.NoError(t, err)
+		assert.Equal(t, ar.GetName(), respBody.Name)
+	})
+	t.Run("will deny access request successfully without body", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		ar := utils.NewAccessRequestRequested(utils.WithName("requested"))
+		rt := newApprovalRoleTemplate("approver@user.com")
+		headers := headers(ar.GetNamespace(), "approver@user.com", "group1", ar.Spec.Application.Namespace, ar.Spec.Application.Name, "some-project")
+		f.service.EXPECT().GetAccessRequest(mock.Anything, ar.GetName(), ar.GetNamespace()).Return(ar, nil)
+		f.service.EXPECT().GetRoleTemplate(mock.Anything, rt.GetName(), rt.GetNamespace()).Return(rt, nil)
+		f.service.EXPECT().UpdateAccessRequestApproval(mock.Anything, ar, mock.Anything).
+			RunAndReturn(func(_ context.Context, ar *api.AccessRequest, approval *api.Approval) (*api.AccessRequest, error) {
+				assert.Equal(t, api.DeniedDecision, approval.Decision)
+				assert.Empty(t, approval.Reason)
+				return ar, nil
+			})
+
+		// When
+		resp := f.api.Post("/accessrequests/requested/deny", headers...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 200, resp.Result().StatusCode)
+	})
+	t.Run("will return 404 if access request is not found", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		ar := utils.NewAccessRequestRequested(utils.WithName("requested"))
+		headers := headers(ar.GetNamespace(), "approver@user.com", "approvers", ar.Spec.Application.Namespace, ar.Spec.Application.Name, "some-project")
+		f.service.EXPECT().GetAccessRequest(mock.Anything, ar.GetName(), ar.GetNamespace()).Return(nil, nil)
+
+		// When
+		resp := f.api.Post("/accessrequests/requested/approve", headers...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 404, resp.Result().StatusCode)
+	})
+	t.Run("will return 404 if access request belongs to another application", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		ar := utils.NewAccessRequestRequested(utils.WithName("requested"))
+		headers := headers(ar.GetNamespace(), "approver@user.com", "approvers", ar.Spec.Application.Namespace, "another-app", "some-project")
+		f.service.EXPECT().GetAccessRequest(mock.Anything, ar.GetName(), ar.GetNamespace()).Return(ar, nil)
+
+		// When
+		resp := f.api.Post("/accessrequests/requested/approve", headers...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 404, resp.Result().StatusCode)
+	})
+	t.Run("will return 409 if access request is already granted", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		ar := utils.NewAccessRequestGranted(utils.WithName("granted"))
+		headers := headers(ar.GetNamespace(), "approver@user.com", "approvers", ar.Spec.Application.Namespace, ar.Spec.Application.Name, "some-project")
+		f.service.EXPECT().GetAccessRequest(mock.Anything, ar.GetName(), ar.GetNamespace()).Return(ar, nil)
+
+		// When
+		resp := f.api.Post("/accessrequests/granted/approve", headers...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 409, resp.Result().StatusCode)
+	})
+	t.Run("will return 409 if access request is already decided", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		ar := utils.NewAccessRequestRequested(utils.WithName("requested"))
+		ar.Spec.Approval = &api.Approval{Decision: api.DeniedDecision, Approver: "another-approver"}
+		headers := headers(ar.GetNamespace(), "approver@user.com", "approvers", ar.Spec.Application.Namespace, ar.Spec.Application.Name, "some-project")
+		f.service.EXPECT().GetAccessRequest(mock.Anything, ar.GetName(), ar.GetNamespace()).Return(ar, nil)
+
+		// When
+		resp := f.api.Post("/accessrequests/requested/approve", headers...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 409, resp.Result().StatusCode)
+	})
+	t.Run("will return 403 on self-approval", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		ar := utils.NewAccessRequestRequested(utils.WithName("requested"))
+		headers := headers(ar.GetNamespace(), ar.Spec.Subject.Username, "approvers", ar.Spec.Application.Namespace, ar.Spec.Application.Name, "some-project")
+		f.service.EXPECT().GetAccessRequest(mock.Anything, ar.GetName(), ar.GetNamespace()).Return(ar, nil)
+
+		// When
+		resp := f.api.Post("/accessrequests/requested/approve", headers...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 403, resp.Result().StatusCode)
+	})
+	t.Run("will return 403 if approver is member of the elevated group", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		ar := utils.NewAccessRequestRequested(utils.WithName("requested"))
+		ar.Spec.Subject.Group = "on-call"
+		headers := headers(ar.GetNamespace(), "approver@user.com", "approvers,on-call", ar.Spec.Application.Namespace, ar.Spec.Application.Name, "some-project")
+		f.service.EXPECT().GetAccessRequest(mock.Anything, ar.GetName(), ar.GetNamespace()).Return(ar, nil)
+
+		// When
+		resp := f.api.Post("/accessrequests/requested/approve", headers...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 403, resp.Result().StatusCode)
+	})
+	t.Run("will return 400 if role does not require approval", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		ar := utils.NewAccessRequestRequested(utils.WithName("requested"))
+		rt := utils.NewRoleTemplate("role-template-name", "ephemeral", "some-role", []string{"some-policy"})
+		headers := headers(ar.GetNamespace(), "approver@user.com", "approvers", ar.Spec.Application.Namespace, ar.Spec.Application.Name, "some-project")
+		f.service.EXPECT().GetAccessRequest(mock.Anything, ar.GetName(), ar.GetNamespace()).Return(ar, nil)
+		f.service.EXPECT().GetRoleTemplate(mock.Anything, rt.GetName(), rt.GetNamespace()).Return(rt, nil)
+
+		// When
+		resp := f.api.Post("/accessrequests/requested/approve", headers...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 400, resp.Result().StatusCode)
+	})
+	t.Run("will return 403 if user is not an approver", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		ar := utils.NewAccessRequestRequested(utils.WithName("requested"))
+		rt := newApprovalRoleTemplate("approvers")
+		headers := headers(ar.GetNamespace(), "approver@user.com", "group1", ar.Spec.Application.Namespace, ar.Spec.Application.Name, "some-project")
+		f.service.EXPECT().GetAccessRequest(mock.Anything, ar.GetName(), ar.GetNamespace()).Return(ar, nil)
+		f.service.EXPECT().GetRoleTemplate(mock.Anything, rt.GetName(), rt.GetNamespace()).Return(rt, nil)
+
+		// When
+		resp := f.api.Post("/accessrequests/requested/approve", headers...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 403, resp.Result().StatusCode)
+	})
+	t.Run("will return 500 on service error getting access request", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		ar := utils.NewAccessRequestRequested(utils.WithName("requested"))
+		headers := headers(ar.GetNamespace(), "approver@user.com", "approvers", ar.Spec.Application.Namespace, ar.Spec.Application.Name, "some-project")
+		f.service.EXPECT().GetAccessRequest(mock.Anything, ar.GetName(), ar.GetNamespace()).Return(nil, fmt.Errorf("some-error"))
+		f.logger.EXPECT().Error(mock.Anything, mock.Anything)
+
+		// When
+		resp := f.api.Post("/accessrequests/requested/approve", headers...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 500, resp.Result().StatusCode)
+	})
+	t.Run("will return 500 on service error updating access request", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		ar := utils.NewAccessRequestRequested(utils.WithName("requested"))
+		rt := newApprovalRoleTemplate("approvers")
+		headers := headers(ar.GetNamespace(), "approver@user.com", "approvers", ar.Spec.Application.Namespace, ar.Spec.Application.Name, "some-project")
+		f.service.EXPECT().GetAccessRequest(mock.Anything, ar.GetName(), ar.GetNamespace()).Return(ar, nil)
+		f.service.EXPECT().GetRoleTemplate(mock.Anything, rt.GetName(), rt.GetNamespace()).Return(rt, nil)
+		f.service.EXPECT().UpdateAccessRequestApproval(mock.Anything, ar, mock.Anything).Return(nil, fmt.Errorf("some-error"))
+		f.logger.EXPECT().Error(mock.Anything, mock.Anything)
+
+		// When
+		resp := f.api.Post("/accessrequests/requested/approve", headers...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 500, resp.Result().StatusCode)
+	})
+}
+
+func TestApiRevokeAccessRequest(t *testing.T) {
+	t.Run("will revoke access request by the requester", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		ar := utils.NewAccessRequestGranted(utils.WithName("granted"))
+		headers := headers(ar.GetNamespace(), ar.Spec.Subject.Username, "group1", ar.Spec.Application.Namespace, ar.Spec.Application.Name, "some-project")
+		f.service.EXPECT().GetAccessRequest(mock.Anything, ar.GetName(), ar.GetNamespace()).Return(ar, nil)
+		f.service.EXPECT().RevokeAccessRequest(mock.Anything, ar, mock.Anything).
+			RunAndReturn(func(_ context.Context, ar *api.AccessRequest, revocation *api.Revocation) (*api.AccessRequest, error) {
+				assert.Equal(t, ar.Spec.Subject.Username, revocation.Revoker)
+				assert.Equal(t, "done", revocation.Reason)
+				updated := ar.DeepCopy()
+				updated.Spec.Revocation = revocation
+				return updated, nil
+			})
+
+		// When
+		payload := backend.RevokeRequestBody{Reason: "done"}
+		resp := f.api.Post("/accessrequests/granted/revoke", append(headers, payload)...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 200, resp.Result().StatusCode)
+		var respBody backend.AccessRequestResponseBody
+		err := json.Unmarshal(resp.Body.Bytes(), &respBody)
+		assert.NoError(t, err)
+		assert.Equal(t, ar.GetName(), respBody.Name)
+	})
+	t.Run("will revoke access request by an approver", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		ar := utils.NewAccessRequestGranted(utils.WithName("granted"))
+		rt := utils.NewRoleTemplate("role-template-name", "ephemeral", "some-role", []string{"some-policy"})
+		rt.Spec.Approval = &api.ApprovalSpec{Approvers: []string{"admins"}}
+		headers := headers(ar.GetNamespace(), "admin@user.com", "admins", ar.Spec.Application.Namespace, ar.Spec.Application.Name, "some-project")
+		f.service.EXPECT().GetAccessRequest(mock.Anything, ar.GetName(), ar.GetNamespace()).Return(ar, nil)
+		f.service.EXPECT().GetRoleTemplate(mock.Anything, rt.GetName(), rt.GetNamespace()).Return(rt, nil)
+		f.service.EXPECT().RevokeAccessRequest(mock.Anything, ar, mock.Anything).Return(ar, nil)
+
+		// When
+		resp := f.api.Post("/accessrequests/granted/revoke", headers...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 200, resp.Result().StatusCode)
+	})
+	t.Run("will revoke access request by a revoker of a role without approvers", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		ar := utils.NewAccessRequestGranted(utils.WithName("granted"))
+		rt := utils.NewRoleTemplate("role-template-name", "ephemeral", "some-role", []string{"some-policy"})
+		rt.Spec.Revokers = []string{"admins"}
+		headers := headers(ar.GetNamespace(), "admin@user.com", "admins", ar.Spec.Application.Namespace, ar.Spec.Application.Name, "some-project")
+		f.service.EXPECT().GetAccessRequest(mock.Anything, ar.GetName(), ar.GetNamespace()).Return(ar, nil)
+		f.service.EXPECT().GetRoleTemplate(mock.Anything, rt.GetName(), rt.GetNamespace()).Return(rt, nil)
+		f.service.EXPECT().RevokeAccessRequest(mock.Anything, ar, mock.Anything).
+			RunAndReturn(func(_ context.Context, ar *api.AccessRequest, revocation *api.Revocation) (*api.AccessRequest, error) {
+				assert.Equal(t, "admin@user.com", revocation.Revoker)
+				return ar, nil
+			})
+
+		// When
+		resp := f.api.Post("/accessrequests/granted/revoke", headers...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 200, resp.Result().StatusCode)
+	})
+	t.Run("will return 403 if user is not the requester nor an approver", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		ar := utils.NewAccessRequestGranted(utils.WithName("granted"))
+		rt := utils.NewRoleTemplate("role-template-name", "ephemeral", "some-role", []string{"some-policy"})
+		headers := headers(ar.GetNamespace(), "another@user.com", "group1", ar.Spec.Application.Namespace, ar.Spec.Application.Name, "some-project")
+		f.service.EXPECT().GetAccessRequest(mock.Anything, ar.GetName(), ar.GetNamespace()).Return(ar, nil)
+		f.service.EXPECT().GetRoleTemplate(mock.Anything, rt.GetName(), rt.GetNamespace()).Return(rt, nil)
+
+		// When
+		resp := f.api.Post("/accessrequests/granted/revoke", headers...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 403, resp.Result().StatusCode)
+	})
+	t.Run("will return 404 if access request is not found", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		ar := utils.NewAccessRequestGranted(utils.WithName("granted"))
+		headers := headers(ar.GetNamespace(), ar.Spec.Subject.Username, "group1", ar.Spec.Application.Namespace, ar.Spec.Application.Name, "some-project")
+		f.service.EXPECT().GetAccessRequest(mock.Anything, ar.GetName(), ar.GetNamespace()).Return(nil, nil)
+
+		// When
+		resp := f.api.Post("/accessrequests/granted/revoke", headers...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 404, resp.Result().StatusCode)
+	})
+	t.Run("will return 409 if access request is already expired", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		ar := utils.NewAccessRequestExpired(utils.WithName("expired"))
+		headers := headers(ar.GetNamespace(), ar.Spec.Subject.Username, "group1", ar.Spec.Application.Namespace, ar.Spec.Application.Name, "some-project")
+		f.service.EXPECT().GetAccessRequest(mock.Anything, ar.GetName(), ar.GetNamespace()).Return(ar, nil)
+
+		// When
+		resp := f.api.Post("/accessrequests/expired/revoke", headers...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 409, resp.Result().StatusCode)
+	})
+	t.Run("will return 409 if access request revocation is already requested", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		ar := utils.NewAccessRequestGranted(utils.WithName("granted"))
+		ar.Spec.Revocation = &api.Revocation{Revoker: "some-admin"}
+		headers := headers(ar.GetNamespace(), ar.Spec.Subject.Username, "group1", ar.Spec.Application.Namespace, ar.Spec.Application.Name, "some-project")
+		f.service.EXPECT().GetAccessRequest(mock.Anything, ar.GetName(), ar.GetNamespace()).Return(ar, nil)
+
+		// When
+		resp := f.api.Post("/accessrequests/granted/revoke", headers...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 409, resp.Result().StatusCode)
+	})
+	t.Run("will return 500 on service error revoking access request", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		ar := utils.NewAccessRequestGranted(utils.WithName("granted"))
+		headers := headers(ar.GetNamespace(), ar.Spec.Subject.Username, "group1", ar.Spec.Application.Namespace, ar.Spec.Application.Name, "some-project")
+		f.service.EXPECT().GetAccessRequest(mock.Anything, ar.GetName(), ar.GetNamespace()).Return(ar, nil)
+		f.service.EXPECT().RevokeAccessRequest(mock.Anything, ar, mock.Anything).Return(nil, fmt.Errorf("some-error"))
+		f.logger.EXPECT().Error(mock.Anything, mock.Anything)
+
+		// When
+		resp := f.api.Post("/accessrequests/granted/revoke", headers...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 500, resp.Result().StatusCode)
+	})
+}
+
+func TestApiExtendAccessRequest(t *testing.T) {
+	newExtendableRoleTemplate := func(maxDuration time.Duration) *api.RoleTemplate {
+		rt := utils.NewRoleTemplate("role-template-name", "ephemeral", "some-role", []string{"some-policy"})
+		rt.Spec.MaxExtendedDuration = &metav1.Duration{Duration: maxDuration}
+		return rt
+	}
+	t.Run("will extend access request successfully", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		ar := utils.NewAccessRequestGranted(utils.WithName("granted"))
+		ar.Spec.Duration = metav1.Duration{Duration: time.Hour}
+		rt := newExtendableRoleTemplate(time.Hour * 2)
+		headers := headers(ar.GetNamespace(), ar.Spec.Subject.Username, "group1", ar.Spec.Application.Namespace, ar.Spec.Application.Name, "some-project")
+		f.service.EXPECT().GetAccessRequest(mock.Anything, ar.GetName(), ar.GetNamespace()).Return(ar, nil)
+		f.service.EXPECT().GetRoleTemplate(mock.Anything, rt.GetName(), rt.GetNamespace()).Return(rt, nil)
+		f.service.EXPECT().ExtendAccessRequest(mock.Anything, ar, mock.Anything).
+			RunAndReturn(func(_ context.Context, ar *api.AccessRequest, extension *api.Extension) (*api.AccessRequest, error) {
+				assert.Equal(t, time.Minute*30, extension.Duration.Duration)
+				assert.Equal(t, ar.Spec.Subject.Username, extension.Requester)
+				return ar, nil
+			})
+
+		// When
+		payload := backend.ExtendAccessRequestBody{Duration: "30m"}
+		resp := f.api.Post("/accessrequests/granted/extend", append(headers, payload)...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 200, resp.Result().StatusCode)
+	})
+	t.Run("will return 400 on invalid duration", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		ar := utils.NewAccessRequestGranted(utils.WithName("granted"))
+		headers := headers(ar.GetNamespace(), ar.Spec.Subject.Username, "group1", ar.Spec.Application.Namespace, ar.Spec.Application.Name, "some-project")
+
+		// When
+		payload := backend.ExtendAccessRequestBody{Duration: "invalid"}
+		resp := f.api.Post("/accessrequests/granted/extend", append(headers, payload)...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 400, resp.Result().StatusCode)
+	})
+	t.Run("will not account for rejected extensions in the maximum duration", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		ar := utils.NewAccessRequestGranted(utils.WithName("granted"))
+		ar.Spec.Duration = metav1.Duration{Duration: time.Hour}
+		ar.Spec.Extensions = []api.Extension{{Duration: metav1.Duration{Duration: time.Minute * 45}}}
+		ar.Status.AppliedExtensions = 1
+		rt := newExtendableRoleTemplate(time.Hour * 2)
+		headers := headers(ar.GetNamespace(), ar.Spec.Subject.Username, "group1", ar.Spec.Application.Namespace, ar.Spec.Application.Name, "some-project")
+		f.service.EXPECT().GetAccessRequest(mock.Anything, ar.GetName(), ar.GetNamespace()).Return(ar, nil)
+		f.service.EXPECT().GetRoleTemplate(mock.Anything, rt.GetName(), rt.GetNamespace()).Return(rt, nil)
+		f.service.EXPECT().ExtendAccessRequest(mock.Anything, ar, mock.Anything).Return(ar, nil)
+
+		// When
+		payload := backend.ExtendAccessRequestBody{Duration: "30m"}
+		resp := f.api.Post("/accessrequests/granted/extend", append(headers, payload)...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 200, resp.Result().StatusCode)
+	})
+	t.Run("will return 400 if maximum duration is exceeded", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		ar := utils.NewAccessRequestGranted(utils.WithName("granted"))
+		ar.Spec.Duration = metav1.Duration{Duration: time.Hour}
+		ar.Spec.Extensions = []api.Extension{{Duration: metav1.Duration{Duration: time.Minute * 45}}}
+		rt := newExtendableRoleTemplate(time.Hour * 2)
+		headers := headers(ar.GetNamespace(), ar.Spec.Subject.Username, "group1", ar.Spec.Application.Namespace, ar.Spec.Application.Name, "some-project")
+		f.service.EXPECT().GetAccessRequest(mock.Anything, ar.GetName(), ar.GetNamespace()).Return(ar, nil)
+		f.service.EXPECT().GetRoleTemplate(mock.Anything, rt.GetName(), rt.GetNamespace()).Return(rt, nil)
+
+		// When
+		payload := backend.ExtendAccessRequestBody{Duration: "30m"}
+		resp := f.api.Post("/accessrequests/granted/extend", append(headers, payload)...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 400, resp.Result().StatusCode)
+	})
+	t.Run("will return 400 if role does not allow extensions", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		ar := utils.NewAccessRequestGranted(utils.WithName("granted"))
+		rt := utils.NewRoleTemplate("role-template-name", "ephemeral", "some-role", []string{"some-policy"})
+		headers := headers(ar.GetNamespace(), ar.Spec.Subject.Username, "group1", ar.Spec.Application.Namespace, ar.Spec.Application.Name, "some-project")
+		f.service.EXPECT().GetAccessRequest(mock.Anything, ar.GetName(), ar.GetNamespace()).Return(ar, nil)
+		f.service.EXPECT().GetRoleTemplate(mock.Anything, rt.GetName(), rt.GetNamespace()).Return(rt, nil)
+
+		// When
+		payload := backend.ExtendAccessRequestBody{Duration: "30m"}
+		resp := f.api.Post("/accessrequests/granted/extend", append(headers, payload)...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 400, resp.Result().StatusCode)
+	})
+	t.Run("will return 400 if access request is break-glass", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		ar := utils.NewAccessRequestGranted(utils.WithName("granted"))
+		ar.Spec.BreakGlass = true
+		headers := headers(ar.GetNamespace(), ar.Spec.Subject.Username, "group1", ar.Spec.Application.Namespace, ar.Spec.Application.Name, "some-project")
+		f.service.EXPECT().GetAccessRequest(mock.Anything, ar.GetName(), ar.GetNamespace()).Return(ar, nil)
+
+		// When
+		payload := backend.ExtendAccessRequestBody{Duration: "30m"}
+		resp := f.api.Post("/accessrequests/granted/extend", append(headers, payload)...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 400, resp.Result().StatusCode)
+	})
+	t.Run("will return 403 if user is not the requester", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		ar := utils.NewAccessRequestGranted(utils.WithName("granted"))
+		headers := headers(ar.GetNamespace(), "another@user.com", "group1", ar.Spec.Application.Namespace, ar.Spec.Application.Name, "some-project")
+		f.service.EXPECT().GetAccessRequest(mock.Anything, ar.GetName(), ar.GetNamespace()).Return(ar, nil)
+
+		// When
+		payload := backend.ExtendAccessRequestBody{Duration: "30m"}
+		resp := f.api.Post("/accessrequests/granted/extend", append(headers, payload)...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 403, resp.Result().StatusCode)
+	})
+	t.Run("will return 409 if access request is not granted", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		ar := utils.NewAccessRequestRequested(utils.WithName("requested"))
+		headers := headers(ar.GetNamespace(), ar.Spec.Subject.Username, "group1", ar.Spec.Application.Namespace, ar.Spec.Application.Name, "some-project")
+		f.service.EXPECT().GetAccessRequest(mock.Anything, ar.GetName(), ar.GetNamespace()).Return(ar, nil)
+
+		// When
+		payload := backend.ExtendAccessRequestBody{Duration: "30m"}
+		resp := f.api.Post("/accessrequests/requested/extend", append(headers, payload)...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 409, resp.Result().StatusCode)
+	})
+	t.Run("will return 500 on service error extending access request", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		ar := utils.NewAccessRequestGranted(utils.WithName("granted"))
+		rt := newExtendableRoleTemplate(time.Hour * 2)
+		headers := headers(ar.GetNamespace(), ar.Spec.Subject.Username, "group1", ar.Spec.Application.Namespace, ar.Spec.Application.Name, "some-project")
+		f.service.EXPECT().GetAccessRequest(mock.Anything, ar.GetName(), ar.GetNamespace()).Return(ar, nil)
+		f.service.EXPECT().GetRoleTemplate(mock.Anything, rt.GetName(), rt.GetNamespace()).Return(rt, nil)
+		f.service.EXPECT().ExtendAccessRequest(mock.Anything, ar, mock.Anything).Return(nil, fmt.Errorf("some-error"))
+		f.logger.EXPECT().Error(mock.Anything, mock.Anything)
+
+		// When
+		payload := backend.ExtendAccessRequestBody{Duration: "30m"}
+		resp := f.api.Post("/accessrequests/granted/extend", append(headers, payload)...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 500, resp.Result().StatusCode)
+	})
+}
+
+func TestApiReviewAccessRequest(t *testing.T) {
+	newBreakGlassRoleTemplate := func(reviewers ...string) *api.RoleTemplate {
+		rt := utils.NewRoleTemplate("role-template-name", "ephemeral", "some-role", []string{"some-policy"})
+		rt.Spec.BreakGlass = &api.BreakGlassSpec{
+			MaxDuration: metav1.Duration{Duration: time.Hour},
+			Reviewers:   reviewers,
+		}
+		return rt
+	}
+	newBreakGlassAccessRequest := func() *api.AccessRequest {
+		ar := utils.NewAccessRequestGranted(utils.WithName("granted"))
+		ar.Spec.BreakGlass = true
+		return ar
+	}
+	t.Run("will review access request successfully", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		ar := newBreakGlassAccessRequest()
+		rt := newBreakGlassRoleTemplate("auditors")
+		headers := headers(ar.GetNamespace(), "auditor@user.com", "auditors", ar.Spec.Application.Namespace, ar.Spec.Application.Name, "some-project")
+		f.service.EXPECT().GetAccessRequest(mock.Anything, ar.GetName(), ar.GetNamespace()).Return(ar, nil)
+		f.service.EXPECT().GetRoleTemplate(mock.Anything, rt.GetName(), rt.GetNamespace()).Return(rt, nil)
+		f.service.EXPECT().ReviewAccessRequest(mock.Anything, ar, mock.Anything).
+			RunAndReturn(func(_ context.Context, ar *api.AccessRequest, review *api.Review) (*api.AccessRequest, error) {
+				assert.Equal(t, "auditor@user.com", review.Reviewer)
+				assert.Equal(t, "some comment", review.Comment)
+				updated := ar.DeepCopy()
+				updated.Spec.Review = review
+				return updated, nil
+			})
+
+		// When
+		payload := backend.ReviewRequestBody{Comment: "some comment"}
+		resp := f.api.Post("/accessrequests/granted/review", append(headers, payload)...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 200, resp.Result().StatusCode)
+		var respBody backend.AccessRequestResponseBody
+		err := json.Unmarshal(resp.Body.Bytes(), &respBody)
+		assert.NoError(t, err)
+		assert.True(t, respBody.BreakGlass)
+		assert.False(t, respBody.PendingReview)
+	})
+	t.Run("will return 404 if access request is not found", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		ar := newBreakGlassAccessRequest()
+		headers := headers(ar.GetNamespace(), "auditor@user.com", "auditors", ar.Spec.Application.Namespace, ar.Spec.Application.Name, "some-project")
+		f.service.EXPECT().GetAccessRequest(mock.Anything, ar.GetName(), ar.GetNamespace()).Return(nil, nil)
+
+		// When
+		resp := f.api.Post("/accessrequests/granted/review", headers...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 404, resp.Result().StatusCode)
+	})
+	t.Run("will return 400 if access request is not break-glass", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		ar := utils.NewAccessRequestGranted(utils.WithName("granted"))
+		headers := headers(ar.GetNamespace(), "auditor@user.com", "auditors", ar.Spec.Application.Namespace, ar.Spec.Application.Name, "some-project")
+		f.service.EXPECT().GetAccessRequest(mock.Anything, ar.GetName(), ar.GetNamespace()).Return(ar, nil)
+
+		// When
+		resp := f.api.Post("/accessrequests/granted/review", headers...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 400, resp.Result().StatusCode)
+	})
+	t.Run("will return 409 if access request is already reviewed", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		ar := newBreakGlassAccessRequest()
+		ar.Spec.Review = &api.Review{Reviewer: "another-auditor"}
+		headers := headers(ar.GetNamespace(), "auditor@user.com", "auditors", ar.Spec.Application.Namespace, ar.Spec.Application.Name, "some-project")
+		f.service.EXPECT().GetAccessRequest(mock.Anything, ar.GetName(), ar.GetNamespace()).Return(ar, nil)
+
+		// When
+		resp := f.api.Post("/accessrequests/granted/review", headers...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 409, resp.Result().StatusCode)
+	})
+	t.Run("will return 403 on self-review", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		ar := newBreakGlassAccessRequest()
+		headers := headers(ar.GetNamespace(), ar.Spec.Subject.Username, "auditors", ar.Spec.Application.Namespace, ar.Spec.Application.Name, "some-project")
+		f.service.EXPECT().GetAccessRequest(mock.Anything, ar.GetName(), ar.GetNamespace()).Return(ar, nil)
+
+		// When
+		resp := f.api.Post("/accessrequests/granted/review", headers...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 403, resp.Result().StatusCode)
+	})
+	t.Run("will return 403 if user is not a reviewer", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		ar := newBreakGlassAccessRequest()
+		rt := newBreakGlassRoleTemplate("auditors")
+		headers := headers(ar.GetNamespace(), "another@user.com", "group1", ar.Spec.Application.Namespace, ar.Spec.Application.Name, "some-project")
+		f.service.EXPECT().GetAccessRequest(mock.Anything, ar.GetName(), ar.GetNamespace()).Return(ar, nil)
+		f.service.EXPECT().GetRoleTemplate(mock.Anything, rt.GetName(), rt.GetNamespace()).Return(rt, nil)
+
+		// When
+		resp := f.api.Post("/accessrequests/granted/review", headers...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 403, resp.Result().StatusCode)
+	})
+	t.Run("will return 500 on service error reviewing access request", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		ar := newBreakGlassAccessRequest()
+		rt := newBreakGlassRoleTemplate("auditors")
+		headers := headers(ar.GetNamespace(), "auditor@user.com", "auditors", ar.Spec.Application.Namespace, ar.Spec.Application.Name, "some-project")
+		f.service.EXPECT().GetAccessRequest(mock.Anything, ar.GetName(), ar.GetNamespace()).Return(ar, nil)
+		f.service.EXPECT().GetRoleTemplate(mock.Anything, rt.GetName(), rt.GetNamespace()).Return(rt, nil)
+		f.service.EXPECT().ReviewAccessRequest(mock.Anything, ar, mock.Anything).Return(nil, fmt.Errorf("some-error"))
+		f.logger.EXPECT().Error(mock.Anything, mock.Anything)
+
+		// When
+		resp := f.api.Post("/accessrequests/granted/review", headers...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 500, resp.Result().StatusCode)
+	})
+}
+
+func TestApiListRoles(t *testing.T) {
+	namespace := "some-namespace"
+	appNs := "app-ns"
+	appName := "some-app"
+	projectName := "some-project"
+	app := &unstructured.Unstructured{}
+	project := &unstructured.Unstructured{}
+
+	t.Run("will return the grantable roles successfully", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		headers := headers(namespace, "some-user", "group1,group2", appNs, appName, projectName)
+		devops := newAccessBinding(namespace, "devops", "group1")
+		devops.Spec.GroupTargets = []string{"on-call"}
+		devopsRT := utils.NewRoleTemplate("devops", namespace, "devops", []string{"some-policy"})
+		devopsRT.Spec.Description = "Devops access"
+		devopsRT.Spec.Approval = &api.ApprovalSpec{Approvers: []string{"managers"}}
+		devopsRT.Spec.Justification = &api.JustificationSpec{Required: true}
+		devopsRT.Spec.MaxExtendedDuration = &metav1.Duration{Duration: 8 * time.Hour}
+		breakGlass := newAccessBinding(namespace, "devops", "group2")
+		breakGlass.Spec.BreakGlass = true
+		roles := []backend.GrantableRole{
+			{Binding: devops, RoleTemplate: devopsRT, DefaultDuration: time.Hour, MaxDuration: 4 * time.Hour},
+			{Binding: breakGlass, RoleTemplate: devopsRT, DefaultDuration: 30 * time.Minute},
+		}
+		f.service.EXPECT().GetApplication(mock.Anything, appName, appNs).Return(app, nil)
+		f.service.EXPECT().GetAppProject(mock.Anything, projectName, namespace).Return(project, nil)
+		f.service.EXPECT().ListGrantableRoles(mock.Anything, namespace, []string{"group1", "group2"}, app, project).Return(roles, nil)
+
+		// When
+		resp := f.api.Get("/roles", headers...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 200, resp.Result().StatusCode)
+		var respBody backend.ListRolesResponseBody
+		err := json.Unmarshal(resp.Body.Bytes(), &respBody)
+		assert.NoError(t, err)
+		require.Len(t, respBody.Items, 2)
+		assert.Equal(t, "devops", respBody.Items[0].Role)
+		assert.Equal(t, *devops.Spec.FriendlyName, respBody.Items[0].FriendlyName)
+		assert.Equal(t, devops.Spec.Ordinal, respBody.Items[0].Ordinal)
+		assert.Equal(t, "Devops access", respBody.Items[0].Description)
+		assert.Equal(t, "1h0m0s", respBody.Items[0].DefaultDuration)
+		assert.Equal(t, "4h0m0s", respBody.Items[0].MaxDuration)
+		assert.Equal(t, "8h0m0s", respBody.Items[0].MaxExtendedDuration)
+		assert.True(t, respBody.Items[0].RequiresApproval)
+		assert.True(t, respBody.Items[0].JustificationRequired)
+		assert.Equal(t, []string{"on-call"}, respBody.Items[0].GroupTargets)
+		assert.False(t, respBody.Items[0].BreakGlass)
+		assert.True(t, respBody.Items[1].BreakGlass)
+		assert.False(t, respBody.Items[1].RequiresApproval)
+		assert.Equal(t, "30m0s", respBody.Items[1].DefaultDuration)
+		assert.Empty(t, respBody.Items[1].MaxDuration)
+		assert.Empty(t, respBody.Items[1].MaxExtendedDuration)
+	})
+	t.Run("will return empty list if no role is grantable", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		headers := headers(namespace, "some-user", "group1", appNs, appName, projectName)
+		f.service.EXPECT().GetApplication(mock.Anything, appName, appNs).Return(app, nil)
+		f.service.EXPECT().GetAppProject(mock.Anything, projectName, namespace).Return(project, nil)
+		f.service.EXPECT().ListGrantableRoles(mock.Anything, namespace, []string{"group1"}, app, project).Return(nil, nil)
+
+		// When
+		resp := f.api.Get("/roles", headers...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 200, resp.Result().StatusCode)
+		var respBody backend.ListRolesResponseBody
+		err := json.Unmarshal(resp.Body.Bytes(), &respBody)
+		assert.NoError(t, err)
+		assert.NotNil(t, respBody.Items)
+		assert.Empty(t, respBody.Items)
+	})
+	t.Run("will return 400 if application is not found", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		headers := headers(namespace, "some-user", "group1", appNs, appName, projectName)
+		f.service.EXPECT().GetApplication(mock.Anything, appName, appNs).Return(nil, nil)
+
+		// When
+		resp := f.api.Get("/roles", headers...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 400, resp.Result().StatusCode)
+	})
+	t.Run("will return 400 if project is not found", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		headers := headers(namespace, "some-user", "group1", appNs, appName, projectName)
+		f.service.EXPECT().GetApplication(mock.Anything, appName, appNs).Return(app, nil)
+		f.service.EXPECT().GetAppProject(mock.Anything, projectName, namespace).Return(nil, nil)
+
+		// When
+		resp := f.api.Get("/roles", headers...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 400, resp.Result().StatusCode)
+	})
+	t.Run("will return 500 on service error", func(t *testing.T) {
+		// Given
+		f := apiSetup(t)
+		headers := headers(namespace, "some-user", "group1", appNs, appName, projectName)
+		f.service.EXPECT().GetApplication(mock.Anything, appName, appNs).Return(app, nil)
+		f.service.EXPECT().GetAppProject(mock.Anything, projectName, namespace).Return(project, nil)
+		f.service.EXPECT().ListGrantableRoles(mock.Anything, namespace, []string{"group1"}, app, project).Return(nil, fmt.Errorf("some-error"))
+		f.logger.EXPECT().Error(mock.Anything, mock.Anything)
+
+		// When
+		resp := f.api.Get("/roles", headers...)
+
+		// Then
+		assert.NotNil(t, resp)
+		assert.Equal(t, 500, resp.Result().StatusCode)
+	})
+}
+
 func TestArgoCDHeaders_Application(t *testing.T) {
 	tests := []struct {
 		name              string
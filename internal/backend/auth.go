@@ -0,0 +1,176 @@
+package backend
+
+import (
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/argoproj-labs/ephemeral-access/pkg/log"
+	"github.com/danielgtaylor/huma/v2"
+)
+
+// DefaultSecretHeader is the default header expected to carry the shared
+// secret sent by Argo CD API server.
+const DefaultSecretHeader = "Ephemeral-Access-Secret"
+
+// ErrUnauthenticated is returned by Authenticators when the request can
+// not be authenticated.
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+// Authenticator defines the interface used to verify that the requests
+// received by the backend are sent by the Argo CD API server. Only
+// authenticated requests can be trusted to provide the Argo CD headers.
+type Authenticator interface {
+	// Authenticate returns an error wrapping ErrUnauthenticated if the
+	// given request can not be authenticated.
+	Authenticate(r *http.Request) error
+}
+
+// SecretAuthenticator authenticates requests providing a shared secret
+// in a configured header.
+type SecretAuthenticator struct {
+	header string
+	secret []byte
+}
+
+// NewSecretAuthenticator will return a new SecretAuthenticator expecting
+// the given secret in the given header. DefaultSecretHeader is used if
+// the header is empty.
+func NewSecretAuthenticator(header, secret string) (*SecretAuthenticator, error) {
+	if secret == "" {
+		return nil, fmt.Errorf("shared secret must be provided")
+	}
+	if header == "" {
+		header = DefaultSecretHeader
+	}
+	return &SecretAuthenticator{
+		header: header,
+		secret: []byte(secret),
+	}, nil
+}
+
+// Authenticate implements the Authenticator interface.
+func (a *SecretAuthenticator) Authenticate(r *http.Request) error {
+	value := r.Header.Get(a.header)
+	if value == "" {
+		return fmt.Errorf("%w: missing %s header", ErrUnauthenticated, a.header)
+	}
+	if subtle.ConstantTimeCompare([]byte(value), a.secret) != 1 {
+		return fmt.Errorf("%w: invalid %s header", ErrUnauthenticated, a.header)
+	}
+	return nil
+}
+
+// ClientCertAuthenticator authenticates requests providing a TLS client
+// certificate signed by a configured CA.
+type ClientCertAuthenticator struct {
+	roots *x509.CertPool
+}
+
+// NewClientCertAuthenticator will return a new ClientCertAuthenticator
+// verifying client certificates against the CA certificates in the given
+// PEM encoded caPEM.
+func NewClientCertAuthenticator(caPEM []byte) (*ClientCertAuthenticator, error) {
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no valid CA certificate found")
+	}
+	return &ClientCertAuthenticator{roots: roots}, nil
+}
+
+// Authenticate implements the Authenticator interface.
+func (a *ClientCertAuthenticator) Authenticate(r *http.Request) error {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return fmt.Errorf("%w: missing client certificate", ErrUnauthenticated)
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range r.TLS.PeerCertificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := r.TLS.PeerCertificates[0].Verify(x509.VerifyOptions{
+		Roots:         a.roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	if err != nil {
+		return fmt.Errorf("%w: invalid client certificate: %w", ErrUnauthenticated, err)
+	}
+	return nil
+}
+
+// NetworkAuthenticator authenticates requests based on the source address
+// of the connection.
+type NetworkAuthenticator struct {
+	networks []*net.IPNet
+}
+
+// NewNetworkAuthenticator will return a new NetworkAuthenticator allowing
+// requests from the given networks. Each entry can be a CIDR or a single
+// IP address.
+func NewNetworkAuthenticator(networks []string) (*NetworkAuthenticator, error) {
+	if len(networks) == 0 {
+		return nil, fmt.Errorf("at least one allowed network must be provided")
+	}
+	a := &NetworkAuthenticator{}
+	for _, network := range networks {
+		_, ipNet, err := net.ParseCIDR(network)
+		if err != nil {
+			ip := net.ParseIP(network)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid allowed network %q: %w", network, err)
+			}
+			ipNet = &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)}
+		}
+		a.networks = append(a.networks, ipNet)
+	}
+	return a, nil
+}
+
+// Authenticate implements the Authenticator interface. Only the connection
+// source address is verified as forwarding headers can be set by anyone.
+func (a *NetworkAuthenticator) Authenticate(r *http.Request) error {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("%w: invalid source address %q", ErrUnauthenticated, r.RemoteAddr)
+	}
+	for _, network := range a.networks {
+		if network.Contains(ip) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: source address %s is not allowed", ErrUnauthenticated, host)
+}
+
+// NewAuthMiddleware will return an http middleware that only forwards the
+// requests authenticated by all the given authenticators. Unauthenticated
+// requests receive a 401 response.
+func NewAuthMiddleware(logger log.Logger, authenticators ...Authenticator) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, authenticator := range authenticators {
+				err := authenticator.Authenticate(r)
+				if err != nil {
+					logger.Info("Unauthenticated request", "remoteAddr", r.RemoteAddr, "error", err.Error())
+					writeUnauthorized(w, err)
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func writeUnauthorized(w http.ResponseWriter, err error) {
+	body := huma.NewError(http.StatusUnauthorized, err.Error())
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(http.StatusUnauthorized)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,285 @@
+package backend_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/argoproj-labs/ephemeral-access/internal/backend"
+	"github.com/argoproj-labs/ephemeral-access/test/mocks"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{
+		cert: cert,
+		key:  key,
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+func (ca *testCA) newClientCert(t *testing.T) *x509.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "argocd-server"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert
+}
+
+func TestSecretAuthenticator(t *testing.T) {
+	t.Run("will authenticate request with the shared secret", func(t *testing.T) {
+		// Given
+		authenticator, err := backend.NewSecretAuthenticator("", "some-secret")
+		require.NoError(t, err)
+		r := httptest.NewRequest(http.MethodGet, "/accessrequests", nil)
+		r.Header.Set(backend.DefaultSecretHeader, "some-secret")
+
+		// When
+		err = authenticator.Authenticate(r)
+
+		// Then
+		assert.NoError(t, err)
+	})
+	t.Run("will return error if the header is missing", func(t *testing.T) {
+		// Given
+		authenticator, err := backend.NewSecretAuthenticator("Some-Header", "some-secret")
+		require.NoError(t, err)
+		r := httptest.NewRequest(http.MethodGet, "/accessrequests", nil)
+		r.Header.Set(backend.DefaultSecretHeader, "some-secret")
+
+		// When
+		err = authenticator.Authenticate(r)
+
+		// Then
+		assert.ErrorIs(t, err, backend.ErrUnauthenticated)
+		assert.ErrorContains(t, err, "missing Some-Header header")
+	})
+	t.Run("will return error if the secret does not match", func(t *testing.T) {
+		// Given
+		authenticator, err := backend.NewSecretAuthenticator("", "some-secret")
+		require.NoError(t, err)
+		r := httptest.NewRequest(http.MethodGet, "/accessrequests", nil)
+		r.Header.Set(backend.DefaultSecretHeader, "another-secret")
+
+		// When
+		err = authenticator.Authenticate(r)
+
+		// Then
+		assert.ErrorIs(t, err, backend.ErrUnauthenticated)
+		assert.ErrorContains(t, err, "invalid")
+	})
+	t.Run("will return error if the secret is empty", func(t *testing.T) {
+		// When
+		authenticator, err := backend.NewSecretAuthenticator("", "")
+
+		// Then
+		assert.Error(t, err)
+		assert.Nil(t, authenticator)
+	})
+}
+
+func TestClientCertAuthenticator(t *testing.T) {
+	ca := newTestCA(t)
+	authenticator, err := backend.NewClientCertAuthenticator(ca.pem)
+	require.NoError(t, err)
+
+	t.Run("will authenticate request with a certificate signed by the CA", func(t *testing.T) {
+		// Given
+		r := httptest.NewRequest(http.MethodGet, "/accessrequests", nil)
+		r.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{ca.newClientCert(t)}}
+
+		// When
+		err := authenticator.Authenticate(r)
+
+		// Then
+		assert.NoError(t, err)
+	})
+	t.Run("will return error if the certificate is signed by another CA", func(t *testing.T) {
+		// Given
+		r := httptest.NewRequest(http.MethodGet, "/accessrequests", nil)
+		r.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{newTestCA(t).newClientCert(t)}}
+
+		// When
+		err := authenticator.Authenticate(r)
+
+		// Then
+		assert.ErrorIs(t, err, backend.ErrUnauthenticated)
+		assert.ErrorContains(t, err, "invalid client certificate")
+	})
+	t.Run("will return error if no certificate is provided", func(t *testing.T) {
+		// Given
+		r := httptest.NewRequest(http.MethodGet, "/accessrequests", nil)
+
+		// When
+		err := authenticator.Authenticate(r)
+
+		// Then
+		assert.ErrorIs(t, err, backend.ErrUnauthenticated)
+		assert.ErrorContains(t, err, "missing client certificate")
+	})
+	t.Run("will return error if the CA is invalid", func(t *testing.T) {
+		// When
+		authenticator, err := backend.NewClientCertAuthenticator([]byte("invalid"))
+
+		// Then
+		assert.Error(t, err)
+		assert.Nil(t, authenticator)
+	})
+}
+
+func TestNetworkAuthenticator(t *testing.T) {
+	authenticator, err := backend.NewNetworkAuthenticator([]string{"10.0.0.0/8", "192.168.1.10"})
+	require.NoError(t, err)
+
+	t.Run("will authenticate request from an allowed network", func(t *testing.T) {
+		// Given
+		r := httptest.NewRequest(http.MethodGet, "/accessrequests", nil)
+		r.RemoteAddr = "10.1.2.3:4567"
+
+		// When
+		err := authenticator.Authenticate(r)
+
+		// Then
+		assert.NoError(t, err)
+	})
+	t.Run("will authenticate request from an allowed address", func(t *testing.T) {
+		// Given
+		r := httptest.NewRequest(http.MethodGet, "/accessrequests", nil)
+		r.RemoteAddr = "192.168.1.10:4567"
+
+		// When
+		err := authenticator.Authenticate(r)
+
+		// Then
+		assert.NoError(t, err)
+	})
+	t.Run("will return error if the source address is not allowed", func(t *testing.T) {
+		// Given
+		r := httptest.NewRequest(http.MethodGet, "/accessrequests", nil)
+		r.RemoteAddr = "192.168.1.11:4567"
+		r.Header.Set("X-Forwarded-For", "10.1.2.3")
+
+		// When
+		err := authenticator.Authenticate(r)
+
+		// Then
+		assert.ErrorIs(t, err, backend.ErrUnauthenticated)
+		assert.ErrorContains(t, err, "source address 192.168.1.11 is not allowed")
+	})
+	t.Run("will return error if the network is invalid", func(t *testing.T) {
+		// When
+		authenticator, err := backend.NewNetworkAuthenticator([]string{"10.0.0.0/33"})
+
+		// Then
+		assert.Error(t, err)
+		assert.Nil(t, authenticator)
+	})
+}
+
+func TestAuthMiddleware(t *testing.T) {
+	newHandler := func(t *testing.T, authenticators ...backend.Authenticator) http.Handler {
+		logger := mocks.NewMockLogger(t)
+		logger.EXPECT().Info(mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+		return backend.NewAuthMiddleware(logger, authenticators...)(next)
+	}
+	secret, err := backend.NewSecretAuthenticator("", "some-secret")
+	require.NoError(t, err)
+	network, err := backend.NewNetworkAuthenticator([]string{"10.0.0.0/8"})
+	require.NoError(t, err)
+
+	t.Run("will forward request authenticated by all authenticators", func(t *testing.T) {
+		// Given
+		handler := newHandler(t, secret, network)
+		r := httptest.NewRequest(http.MethodGet, "/accessrequests", nil)
+		r.RemoteAddr = "10.1.2.3:4567"
+		r.Header.Set(backend.DefaultSecretHeader, "some-secret")
+		w := httptest.NewRecorder()
+
+		// When
+		handler.ServeHTTP(w, r)
+
+		// Then
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+	t.Run("will return 401 if any authenticator fails", func(t *testing.T) {
+		// Given
+		handler := newHandler(t, secret, network)
+		r := httptest.NewRequest(http.MethodGet, "/accessrequests", nil)
+		r.RemoteAddr = "172.16.0.1:4567"
+		r.Header.Set(backend.DefaultSecretHeader, "some-secret")
+		w := httptest.NewRecorder()
+
+		// When
+		handler.ServeHTTP(w, r)
+
+		// Then
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+		body := &huma.ErrorModel{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), body))
+		assert.Equal(t, http.StatusUnauthorized, body.Status)
+		assert.Contains(t, body.Detail, "source address 172.16.0.1 is not allowed")
+	})
+	t.Run("will forward all requests without authenticators", func(t *testing.T) {
+		// Given
+		handler := newHandler(t)
+		r := httptest.NewRequest(http.MethodGet, "/accessrequests", nil)
+		w := httptest.NewRecorder()
+
+		// When
+		handler.ServeHTTP(w, r)
+
+		// Then
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
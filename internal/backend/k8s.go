@@ -23,6 +23,8 @@ const (
 	accessRequestUsernameField     = "spec.subject.username"
 	accessRequestAppNameField      = "spec.application.name"
 	accessRequestAppNamespaceField = "spec.application.namespace"
+	accessRequestProjectNameField  = "spec.project.name"
+	accessRequestRoleField         = "spec.role.templateRef.name"
 
 	accessBindingRoleField = "spec.roleTemplateRef.name"
 )
@@ -33,10 +35,23 @@ type Persister interface {
 
 	// CreateAccessRequest creates a new Access Request object and returns it
 	CreateAccessRequest(ctx context.Context, ar *api.AccessRequest) (*api.AccessRequest, error)
-	// ListAccessRequests returns all the AccessRequest matching the key criterias
+	// ListAccessRequests returns all the AccessRequest matching the key criterias.
+	// If the key has a project, the project-scoped AccessRequests for that
+	// project are also returned.
 	ListAccessRequests(ctx context.Context, key *AccessRequestKey) (*api.AccessRequestList, error)
-
-	// ListAccessRequests returns all the AccessBindings matching the specified role and namespace
+	// ListAccessRequestsByRole returns all the AccessRequests in the given namespace requesting the
+	// specified role
+	ListAccessRequestsByRole(ctx context.Context, roleName, namespace string) (*api.AccessRequestList, error)
+	// GetAccessRequest returns the AccessRequest with the given name and namespace
+	GetAccessRequest(ctx context.Context, name, namespace string) (*api.AccessRequest, error)
+	// UpdateAccessRequest updates the given AccessRequest and returns the updated object
+	UpdateAccessRequest(ctx context.Context, ar *api.AccessRequest) (*api.AccessRequest, error)
+
+	// GetRoleTemplate returns the RoleTemplate with the given name and namespace
+	GetRoleTemplate(ctx context.Context, name, namespace string) (*api.RoleTemplate, error)
+
+	// ListAccessRequests returns all the AccessBindings matching the specified role and namespace. All AccessBindings
+	// in the namespace are returned if roleName is empty.
 	ListAccessBindings(ctx context.Context, roleName, namespace string) (*api.AccessBindingList, error)
 
 	// GetApplication returns an Unstructured object that represents the Application.
@@ -122,6 +137,28 @@ func NewK8sPersister(config *rest.Config, logger log.Logger) (*K8sPersister, err
 		return nil, fmt.Errorf("error adding AccessRequest index for field %s: %w", accessRequestAppNameField, err)
 	}
 
+	err = cache.IndexField(context.Background(), &api.AccessRequest{}, accessRequestProjectNameField, func(obj client.Object) []string {
+		ar := obj.(*api.AccessRequest)
+		if !ar.IsProjectScoped() {
+			return nil
+		}
+		return []string{ar.Spec.Project.Name}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error adding AccessRequest index for field %s: %w", accessRequestProjectNameField, err)
+	}
+
+	err = cache.IndexField(context.Background(), &api.AccessRequest{}, accessRequestRoleField, func(obj client.Object) []string {
+		ar := obj.(*api.AccessRequest)
+		if ar.Spec.Role.TemplateRef.Name == "" {
+			return nil
+		}
+		return []string{ar.Spec.Role.TemplateRef.Name}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error adding AccessRequest index for field %s: %w", accessRequestRoleField, err)
+	}
+
 	err = cache.IndexField(context.Background(), &api.AccessBinding{}, accessBindingRoleField, func(obj client.Object) []string {
 		b := obj.(*api.AccessBinding)
 		if b.Spec.RoleTemplateRef.Name == "" {
@@ -191,31 +228,101 @@ func (c *K8sPersister) CreateAccessRequest(ctx context.Context, ar *api.AccessRe
 }
 
 func (c *K8sPersister) ListAccessRequests(ctx context.Context, key *AccessRequestKey) (*api.AccessRequestList, error) {
+	list := &api.AccessRequestList{}
+	if key.ApplicationName != "" {
+		selector := fields.SelectorFromSet(
+			fields.Set{
+				accessRequestUsernameField:     key.Username,
+				accessRequestAppNameField:      key.ApplicationName,
+				accessRequestAppNamespaceField: key.ApplicationNamespace,
+			},
+		)
+		err := c.client.List(ctx, list, &client.ListOptions{Namespace: key.Namespace, FieldSelector: selector})
+		if err != nil {
+			return nil, fmt.Errorf("error listing access request for user %s in app %s/%s from k8s: %w", key.Username, key.ApplicationNamespace, key.ApplicationName, err)
+		}
+	}
+	if key.ProjectName != "" {
+		selector := fields.SelectorFromSet(
+			fields.Set{
+				accessRequestUsernameField:    key.Username,
+				accessRequestProjectNameField: key.ProjectName,
+			},
+		)
+		projectList := &api.AccessRequestList{}
+		err := c.client.List(ctx, projectList, &client.ListOptions{Namespace: key.Namespace, FieldSelector: selector})
+		if err != nil {
+			return nil, fmt.Errorf("error listing access request for user %s in project %s from k8s: %w", key.Username, key.ProjectName, err)
+		}
+		list.Items = append(list.Items, projectList.Items...)
+	}
+	return list, nil
+}
+
+func (c *K8sPersister) ListAccessRequestsByRole(ctx context.Context, roleName, namespace string) (*api.AccessRequestList, error) {
 	var selector = fields.SelectorFromSet(
 		fields.Set{
-			accessRequestUsernameField:     key.Username,
-			accessRequestAppNameField:      key.ApplicationName,
-			accessRequestAppNamespaceField: key.ApplicationNamespace,
+			accessRequestRoleField: roleName,
 		},
 	)
 
 	list := &api.AccessRequestList{}
-	err := c.client.List(ctx, list, &client.ListOptions{Namespace: key.Namespace, FieldSelector: selector})
+	err := c.client.List(ctx, list, &client.ListOptions{Namespace: namespace, FieldSelector: selector})
 	if err != nil {
-		return nil, fmt.Errorf("error listing access request for user %s in app %s/%s from k8s: %w", key.Username, key.ApplicationNamespace, key.ApplicationName, err)
+		return nil, fmt.Errorf("error listing access requests for role %s in namespace %s from k8s: %w", roleName, namespace, err)
 	}
 	return list, nil
 }
 
+func (c *K8sPersister) GetAccessRequest(ctx context.Context, name, namespace string) (*api.AccessRequest, error) {
+	obj := &api.AccessRequest{}
+	key := client.ObjectKey{
+		Namespace: namespace,
+		Name:      name,
+	}
+	err := c.client.Get(ctx, key, obj)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving access request %s/%s from k8s: %w", namespace, name, err)
+	}
+	return obj, nil
+}
+
+func (c *K8sPersister) UpdateAccessRequest(ctx context.Context, ar *api.AccessRequest) (*api.AccessRequest, error) {
+	obj := ar.DeepCopy()
+	err := c.client.Update(ctx, obj, &client.UpdateOptions{
+		FieldManager: managerName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error updating access request %s/%s: %w", ar.GetNamespace(), ar.GetName(), err)
+	}
+	return obj, nil
+}
+
+func (c *K8sPersister) GetRoleTemplate(ctx context.Context, name, namespace string) (*api.RoleTemplate, error) {
+	obj := &api.RoleTemplate{}
+	key := client.ObjectKey{
+		Namespace: namespace,
+		Name:      name,
+	}
+	err := c.client.Get(ctx, key, obj)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving role template %s/%s from k8s: %w", namespace, name, err)
+	}
+	return obj, nil
+}
+
 func (c *K8sPersister) ListAccessBindings(ctx context.Context, roleName, namespace string) (*api.AccessBindingList, error) {
-	var selector = fields.SelectorFromSet(
-		fields.Set{
-			accessBindingRoleField: roleName,
-		},
-	)
+	opts := &client.ListOptions{Namespace: namespace}
+	if roleName != "" {
+		opts.FieldSelector = fields.SelectorFromSet(
+			fields.Set{
+				accessBindingRoleField: roleName,
+			},
+		)
+	}
 
 	list := &api.AccessBindingList{}
-	err := c.client.List(ctx, list, &client.ListOptions{Namespace: namespace, FieldSelector: selector})
+	err := c.client.List(ctx, list, opts)
 	if err != nil {
 		return nil, fmt.Errorf("error listing access bindings for role %s in namespace %s from k8s: %w", roleName, namespace, err)
 	}
@@ -353,6 +353,36 @@ func TestK8sPersister(t *testing.T) {
 		assert.Equal(t, ab.GetNamespace(), result.Items[0].Namespace)
 	})
 
+	t.Run("will list all AccessBindings in the namespace if role is empty", func(t *testing.T) {
+		// Given
+		nsName := "list-ab-all-roles"
+		ns := utils.NewNamespace(nsName)
+		err = k8sClient.Create(ctx, ns)
+		require.NoError(t, err)
+
+		for _, roleName := range []string{"some-role", "other-role"} {
+			ab := newDefaultAccessBinding()
+			ab.ObjectMeta.Namespace = nsName
+			ab.ObjectMeta.Name = "ab-" + roleName
+			ab.Spec.RoleTemplateRef.Name = roleName
+			err = k8sClient.Create(ctx, ab)
+			require.NoError(t, err)
+		}
+
+		// When
+		expectedItems := 2
+		eventually(func() (bool, error) {
+			result, err := p.ListAccessBindings(ctx, "", nsName)
+			return result != nil && len(result.Items) == expectedItems, err
+		}, 5*time.Second, time.Second)
+		result, err := p.ListAccessBindings(ctx, "", nsName)
+
+		// Then
+		assert.NoError(t, err)
+		require.NotNil(t, result)
+		assert.Equal(t, expectedItems, len(result.Items))
+	})
+
 	t.Run("will return empty if no AccessBindings are found", func(t *testing.T) {
 		// Given
 		nsName := "list-ab-notfound"
@@ -2,6 +2,7 @@ package backend
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"slices"
 	"strings"
@@ -13,24 +14,50 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
 )
 
 // Service defines the operations provided by the backend. Backend business
 // logic should be added in implementations of this interface
 type Service interface {
-	// CreateAccessRequest will create an AccessRequest for the given key requesting the role specified by the AccessBinding
-	CreateAccessRequest(ctx context.Context, key *AccessRequestKey, binding *api.AccessBinding) (*api.AccessRequest, error)
+	// CreateAccessRequest will create an AccessRequest for the given key requesting the role specified by the AccessBinding.
+	// The optional details define when, for how long and why the access is requested. Returns an error wrapping
+	// ErrInvalidAccessRequest if the details are not allowed by the RoleTemplate or wrapping api.ErrQuotaExceeded if
+	// the RoleTemplate quotas are exceeded.
+	CreateAccessRequest(ctx context.Context, key *AccessRequestKey, binding *api.AccessBinding, details AccessRequestDetails) (*api.AccessRequest, error)
 	// GetAccessRequestByRole will retrieve the access request for the specified role.
 	// Will return a nil value without any error if an access request isn't found for this role.
 	GetAccessRequestByRole(ctx context.Context, key *AccessRequestKey, roleName string) (*api.AccessRequest, error)
 	// ListAccessRequests will list non-expired access requests and optionally sort them by importance.
 	// The importance sort is based on status, role ordinal, name and creation date.
 	ListAccessRequests(ctx context.Context, key *AccessRequestKey, sort bool) ([]*api.AccessRequest, error)
+	// GetAccessRequest will retrieve the access request with the given name and namespace.
+	// Will return a nil value without any error if the access request isn't found.
+	GetAccessRequest(ctx context.Context, name, namespace string) (*api.AccessRequest, error)
+	// UpdateAccessRequestApproval will record the given approval decision in the access request.
+	UpdateAccessRequestApproval(ctx context.Context, ar *api.AccessRequest, approval *api.Approval) (*api.AccessRequest, error)
+	// RevokeAccessRequest will signal the controller to revoke the given access request.
+	RevokeAccessRequest(ctx context.Context, ar *api.AccessRequest, revocation *api.Revocation) (*api.AccessRequest, error)
+	// ExtendAccessRequest will append the given extension in the given access request.
+	ExtendAccessRequest(ctx context.Context, ar *api.AccessRequest, extension *api.Extension) (*api.AccessRequest, error)
+	// ReviewAccessRequest will record the given review in the given break-glass access request.
+	ReviewAccessRequest(ctx context.Context, ar *api.AccessRequest, review *api.Review) (*api.AccessRequest, error)
+
+	// GetRoleTemplate will retrieve the role template with the given name and namespace.
+	// Will return a nil value without any error if the role template isn't found.
+	GetRoleTemplate(ctx context.Context, name, namespace string) (*api.RoleTemplate, error)
 
 	// GetGrantingAccessBinding will return the first AccessBinding allowing at least one of the group to request the specified role
 	// AccessBinding can be located in the specified namespace or in the controller namespace.
+	// If targetGroup is provided, only bindings allowing the elevation of this group as a whole are considered.
+	// If breakGlass is true, only break-glass bindings are considered. Otherwise break-glass bindings are ignored.
 	// If no bindings are granting access, nil is returned
-	GetGrantingAccessBinding(ctx context.Context, roleName string, namespace string, groups []string, app *unstructured.Unstructured, project *unstructured.Unstructured) (*api.AccessBinding, error)
+	GetGrantingAccessBinding(ctx context.Context, roleName string, namespace string, groups []string, targetGroup string, breakGlass bool, app *unstructured.Unstructured, project *unstructured.Unstructured) (*api.AccessBinding, error)
+	// ListGrantableRoles will return the roles the given groups are allowed to request for the given application and
+	// project, sorted by ordinal and name. AccessBindings are evaluated in the specified namespace and in the controller
+	// namespace. Roles granted by break-glass bindings are returned separately from the regular ones. The returned
+	// RoleTemplates are rendered for the given application and roles whose RoleTemplate can't be retrieved are skipped.
+	ListGrantableRoles(ctx context.Context, namespace string, groups []string, app *unstructured.Unstructured, project *unstructured.Unstructured) ([]GrantableRole, error)
 
 	// GetApplication returns the Unstructured object representing the application. The Unstructured object
 	// can be used to evaluate granting AccessBinding.
@@ -45,6 +72,55 @@ type AccessRequestKey struct {
 	ApplicationName      string
 	ApplicationNamespace string
 	Username             string
+	// Group is the optional group targeted by the AccessRequest. If empty,
+	// the AccessRequest targets the user.
+	Group string
+	// ProjectName is the Argo CD project of the application. If no
+	// application is provided, the key targets the whole project.
+	ProjectName string
+	// Groups are the group claims of the requesting user. They are recorded
+	// in created AccessRequests so the controller can verify the granting
+	// AccessBinding.
+	Groups []string
+}
+
+// IsProjectScoped returns true if the key targets a project instead of a
+// single application.
+func (k *AccessRequestKey) IsProjectScoped() bool {
+	return k.ApplicationName == "" && k.ProjectName != ""
+}
+
+// AccessRequestDetails defines the optional details provided by the user
+// when creating an AccessRequest.
+type AccessRequestDetails struct {
+	// StartsAt is the time the access is scheduled to be granted. If nil, the
+	// access is granted as soon as possible.
+	StartsAt *time.Time
+	// Duration is the requested access duration. If zero, the RoleTemplate
+	// default duration is used, falling back to the backend default.
+	Duration time.Duration
+	// Justification explains why the access is needed.
+	Justification string
+	// TicketRef references the ticket associated with the access.
+	TicketRef string
+	// BreakGlass requests emergency access bypassing the approval and the
+	// plugin checks. The RoleTemplate must allow break-glass access.
+	BreakGlass bool
+}
+
+// GrantableRole defines a role that can be requested by a user along with
+// the AccessBinding granting it.
+type GrantableRole struct {
+	// Binding is the AccessBinding granting the role.
+	Binding *api.AccessBinding
+	// RoleTemplate is the RoleTemplate referenced by the Binding.
+	RoleTemplate *api.RoleTemplate
+	// DefaultDuration is the access duration used if the request doesn't
+	// define one.
+	DefaultDuration time.Duration
+	// MaxDuration is the maximum access duration that can be requested.
+	// Zero if there is no maximum.
+	MaxDuration time.Duration
 }
 
 // DefaultService is the real Service implementation
@@ -53,6 +129,7 @@ type DefaultService struct {
 	logger                log.Logger
 	namespace             string
 	accessRequestDuration time.Duration
+	signingKey            []byte
 }
 
 // requestStateOrder returns a map with AccessRequest.Status as the key
@@ -62,32 +139,45 @@ func requestStateOrder() map[api.Status]int {
 		// empty is the default and assumed to be the same as requested
 		"":                  0,
 		api.RequestedStatus: 0,
+		api.ScheduledStatus: 0,
 		api.GrantedStatus:   1,
 		api.DeniedStatus:    2,
 		api.InvalidStatus:   3,
 		api.ExpiredStatus:   4,
+		api.RevokedStatus:   5,
 	}
 }
 
+// ErrInvalidAccessRequest is returned when the AccessRequest can not be
+// created due to invalid input.
+var ErrInvalidAccessRequest = errors.New("invalid access request")
+
 const (
 	// Same as https://github.com/kubernetes/apiserver/blob/v0.31.1/pkg/storage/names/generate.go#L46
 	maxNameLength          = 63
 	randomLength           = 5
 	MaxGeneratedNameLength = maxNameLength - randomLength
+	// maxNameAttempts is the number of names generated for signed
+	// AccessRequests before giving up on name conflicts
+	maxNameAttempts = 5
 )
 
-// NewDefaultService will return a new DefaultService instance.
-func NewDefaultService(c Persister, l log.Logger, namespace string, arDuration time.Duration) *DefaultService {
+// NewDefaultService will return a new DefaultService instance. The given
+// signingKey is optional and when provided, all AccessRequests created by
+// the service as well as the approvals and reviews recorded by it are
+// signed with it.
+func NewDefaultService(c Persister, l log.Logger, namespace string, arDuration time.Duration, signingKey []byte) *DefaultService {
 	return &DefaultService{
 		k8s:                   c,
 		logger:                l,
 		namespace:             namespace,
 		accessRequestDuration: arDuration,
+		signingKey:            signingKey,
 	}
 }
 
 // GetAccessRequestByRole will find the AccessRequest based on the given key and roleName.
-// Result will discard Expired and Denied AccessRequests.
+// Result will discard Expired, Denied and Revoked AccessRequests.
 func (s *DefaultService) GetAccessRequestByRole(ctx context.Context, key *AccessRequestKey, roleName string) (*api.AccessRequest, error) {
 
 	// get all access requests
@@ -99,7 +189,10 @@ func (s *DefaultService) GetAccessRequestByRole(ctx context.Context, key *Access
 	// find the first access request matching the requested role
 	for _, ar := range accessRequests {
 		if ar.Spec.Role.TemplateRef.Name == roleName &&
-			ar.Status.RequestState != api.DeniedStatus {
+			ar.Spec.Subject.Group == key.Group &&
+			ar.IsProjectScoped() == key.IsProjectScoped() &&
+			ar.Status.RequestState != api.DeniedStatus &&
+			ar.Status.RequestState != api.RevokedStatus {
 			return ar, nil
 		}
 	}
@@ -132,7 +225,98 @@ func (s *DefaultService) ListAccessRequests(ctx context.Context, key *AccessRequ
 	return filtered, nil
 }
 
-func (s *DefaultService) GetGrantingAccessBinding(ctx context.Context, roleName string, namespace string, groups []string, app *unstructured.Unstructured, project *unstructured.Unstructured) (*api.AccessBinding, error) {
+// GetAccessRequest will retrieve the AccessRequest with the given name and namespace.
+func (s *DefaultService) GetAccessRequest(ctx context.Context, name, namespace string) (*api.AccessRequest, error) {
+	ar, err := s.k8s.GetAccessRequest(ctx, name, namespace)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return ar, nil
+}
+
+// UpdateAccessRequestApproval will set the given approval in the AccessRequest spec.
+// The approval is signed if a signing key is configured.
+func (s *DefaultService) UpdateAccessRequestApproval(ctx context.Context, ar *api.AccessRequest, approval *api.Approval) (*api.AccessRequest, error) {
+	obj := ar.DeepCopy()
+	obj.Spec.Approval = approval
+	if len(s.signingKey) > 0 {
+		err := obj.SignApproval(s.signingKey)
+		if err != nil {
+			return nil, fmt.Errorf("error signing access request approval: %w", err)
+		}
+	}
+	updated, err := s.k8s.UpdateAccessRequest(ctx, obj)
+	if err != nil {
+		return nil, fmt.Errorf("error updating access request approval: %w", err)
+	}
+	return updated, nil
+}
+
+// RevokeAccessRequest will set the given revocation in the AccessRequest spec.
+func (s *DefaultService) RevokeAccessRequest(ctx context.Context, ar *api.AccessRequest, revocation *api.Revocation) (*api.AccessRequest, error) {
+	obj := ar.DeepCopy()
+	obj.Spec.Revocation = revocation
+	updated, err := s.k8s.UpdateAccessRequest(ctx, obj)
+	if err != nil {
+		return nil, fmt.Errorf("error updating access request revocation: %w", err)
+	}
+	return updated, nil
+}
+
+// ExtendAccessRequest will append the given extension in the AccessRequest
+// spec. The extensions are signed if a signing key is configured.
+func (s *DefaultService) ExtendAccessRequest(ctx context.Context, ar *api.AccessRequest, extension *api.Extension) (*api.AccessRequest, error) {
+	obj := ar.DeepCopy()
+	obj.Spec.Extensions = append(obj.Spec.Extensions, *extension)
+	if len(s.signingKey) > 0 {
+		err := obj.SignExtensions(s.signingKey)
+		if err != nil {
+			return nil, fmt.Errorf("error signing access request extensions: %w", err)
+		}
+	}
+	updated, err := s.k8s.UpdateAccessRequest(ctx, obj)
+	if err != nil {
+		return nil, fmt.Errorf("error updating access request extensions: %w", err)
+	}
+	return updated, nil
+}
+
+// ReviewAccessRequest will set the given review in the AccessRequest spec and
+// remove the pending review label. The review is signed if a signing key is
+// configured.
+func (s *DefaultService) ReviewAccessRequest(ctx context.Context, ar *api.AccessRequest, review *api.Review) (*api.AccessRequest, error) {
+	obj := ar.DeepCopy()
+	obj.Spec.Review = review
+	delete(obj.Labels, api.PendingReviewLabel)
+	if len(s.signingKey) > 0 {
+		err := obj.SignReview(s.signingKey)
+		if err != nil {
+			return nil, fmt.Errorf("error signing access request review: %w", err)
+		}
+	}
+	updated, err := s.k8s.UpdateAccessRequest(ctx, obj)
+	if err != nil {
+		return nil, fmt.Errorf("error updating access request review: %w", err)
+	}
+	return updated, nil
+}
+
+// GetRoleTemplate will retrieve the RoleTemplate with the given name and namespace.
+func (s *DefaultService) GetRoleTemplate(ctx context.Context, name, namespace string) (*api.RoleTemplate, error) {
+	rt, err := s.k8s.GetRoleTemplate(ctx, name, namespace)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return rt, nil
+}
+
+func (s *DefaultService) GetGrantingAccessBinding(ctx context.Context, roleName string, namespace string, groups []string, targetGroup string, breakGlass bool, app *unstructured.Unstructured, project *unstructured.Unstructured) (*api.AccessBinding, error) {
 	bindings, err := s.listAccessBindings(ctx, roleName, namespace)
 	if err != nil {
 		return nil, fmt.Errorf("error retrieving access bindings for role %s: %w", roleName, err)
@@ -146,35 +330,153 @@ func (s *DefaultService) GetGrantingAccessBinding(ctx context.Context, roleName
 	s.logger.Debug(fmt.Sprintf("Found %d bindings referencing role %s", len(bindings), roleName))
 	var grantingBinding *api.AccessBinding
 	for i, binding := range bindings {
-
-		subjects, err := binding.RenderSubjects(app, project)
+		granted, err := binding.Grants(groups, targetGroup, breakGlass, app, project)
 		if err != nil {
 			s.logger.Error(err, fmt.Sprintf("Cannot render subjects %s:", binding.Name))
 			continue
 		}
-
-		s.logger.Debug("matching subjects with user groups", "subjects", subjects, "groups", groups)
-		if s.matchSubject(subjects, groups) {
+		if granted {
 			grantingBinding = &bindings[i]
 			break
 		}
+		s.logger.Debug(fmt.Sprintf("AccessBinding %s does not grant role %s to groups %v", binding.Name, roleName, groups))
 	}
 
 	return grantingBinding, nil
 }
 
-// matchSubject returns true if groups contains at least one of subjects
-func (s *DefaultService) matchSubject(subjects, groups []string) bool {
-	for _, subject := range subjects {
-		if found := slices.Contains(groups, subject); found {
-			return true
+func (s *DefaultService) ListGrantableRoles(ctx context.Context, namespace string, groups []string, app *unstructured.Unstructured, project *unstructured.Unstructured) ([]GrantableRole, error) {
+	bindings, err := s.listAccessBindings(ctx, "", namespace)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving access bindings: %w", err)
+	}
+
+	roles := []GrantableRole{}
+	found := map[string]bool{}
+	for i := range bindings {
+		binding := &bindings[i]
+		roleName := binding.Spec.RoleTemplateRef.Name
+		roleKey := fmt.Sprintf("%s/%s/%t", binding.Namespace, roleName, binding.Spec.BreakGlass)
+		if found[roleKey] {
+			continue
+		}
+		granted, err := binding.Grants(groups, "", binding.Spec.BreakGlass, app, project)
+		if err != nil {
+			s.logger.Error(err, fmt.Sprintf("Cannot render subjects %s:", binding.Name))
+			continue
+		}
+		if !granted {
+			continue
+		}
+		rt, err := s.GetRoleTemplate(ctx, roleName, binding.Namespace)
+		if err != nil {
+			s.logger.Error(err, fmt.Sprintf("Cannot retrieve role template %s/%s", binding.Namespace, roleName))
+			continue
+		}
+		if rt == nil || (binding.Spec.BreakGlass && rt.Spec.BreakGlass == nil) {
+			s.logger.Debug(fmt.Sprintf("AccessBinding %s references a role that can not be requested: %s", binding.Name, roleName))
+			continue
+		}
+		rt, err = rt.Render(project.GetName(), app.GetName(), app.GetNamespace())
+		if err != nil {
+			s.logger.Error(err, fmt.Sprintf("Cannot render role template %s/%s", binding.Namespace, roleName))
+			continue
+		}
+		found[roleKey] = true
+		roles = append(roles, GrantableRole{
+			Binding:         binding,
+			RoleTemplate:    rt,
+			DefaultDuration: s.defaultDuration(rt, binding.Spec.BreakGlass),
+			MaxDuration:     maxDuration(rt, binding.Spec.BreakGlass),
+		})
+	}
+	slices.SortStableFunc(roles, func(a, b GrantableRole) int {
+		if a.Binding.Spec.Ordinal != b.Binding.Spec.Ordinal {
+			return a.Binding.Spec.Ordinal - b.Binding.Spec.Ordinal
+		}
+		return strings.Compare(a.Binding.Spec.RoleTemplateRef.Name, b.Binding.Spec.RoleTemplateRef.Name)
+	})
+	return roles, nil
+}
+
+// defaultDuration returns the duration of the AccessRequests created for
+// the given rt without an explicit duration. The given rt is optional.
+func (s *DefaultService) defaultDuration(rt *api.RoleTemplate, breakGlass bool) time.Duration {
+	duration := s.accessRequestDuration
+	if rt != nil && rt.Spec.DefaultDuration != nil {
+		duration = rt.Spec.DefaultDuration.Duration
+	}
+	if breakGlass && rt != nil && rt.Spec.BreakGlass != nil && duration > rt.Spec.BreakGlass.MaxDuration.Duration {
+		duration = rt.Spec.BreakGlass.MaxDuration.Duration
+	}
+	return duration
+}
+
+// maxDuration returns the maximum duration allowed by the given rt or zero
+// if there is no maximum.
+func maxDuration(rt *api.RoleTemplate, breakGlass bool) time.Duration {
+	var duration time.Duration
+	if rt.Spec.MaxDuration != nil {
+		duration = rt.Spec.MaxDuration.Duration
+	}
+	if breakGlass && rt.Spec.BreakGlass != nil {
+		if duration == 0 || rt.Spec.BreakGlass.MaxDuration.Duration < duration {
+			duration = rt.Spec.BreakGlass.MaxDuration.Duration
 		}
 	}
-	return false
+	return duration
 }
 
-func (s *DefaultService) CreateAccessRequest(ctx context.Context, key *AccessRequestKey, binding *api.AccessBinding) (*api.AccessRequest, error) {
+func (s *DefaultService) CreateAccessRequest(ctx context.Context, key *AccessRequestKey, binding *api.AccessBinding, details AccessRequestDetails) (*api.AccessRequest, error) {
 	roleName := binding.Spec.RoleTemplateRef.Name
+	requestedAt := time.Now()
+	var startsAtTime *metav1.Time
+	if details.StartsAt != nil {
+		if !details.StartsAt.After(requestedAt) {
+			return nil, fmt.Errorf("%w: startsAt must be in the future", ErrInvalidAccessRequest)
+		}
+		requestedAt = *details.StartsAt
+		startsAtTime = &metav1.Time{Time: *details.StartsAt}
+	}
+	if details.Duration < 0 {
+		return nil, fmt.Errorf("%w: duration must be positive", ErrInvalidAccessRequest)
+	}
+	if details.BreakGlass && details.StartsAt != nil {
+		return nil, fmt.Errorf("%w: break-glass access can not be scheduled", ErrInvalidAccessRequest)
+	}
+	rt, err := s.GetRoleTemplate(ctx, roleName, binding.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving role template %s: %w", roleName, err)
+	}
+	if details.BreakGlass && (rt == nil || rt.Spec.BreakGlass == nil) {
+		return nil, fmt.Errorf("%w: role %s does not allow break-glass access", ErrInvalidAccessRequest, roleName)
+	}
+	duration := details.Duration
+	if duration == 0 {
+		duration = s.defaultDuration(rt, details.BreakGlass)
+	}
+	if rt != nil {
+		err = rt.ValidateDuration(duration)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidAccessRequest, err)
+		}
+		err = rt.ValidateJustification(details.Justification, details.TicketRef)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidAccessRequest, err)
+		}
+		if details.BreakGlass {
+			err = rt.ValidateBreakGlass(duration)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidAccessRequest, err)
+			}
+		}
+		if rt.Spec.AccessWindows != nil {
+			_, err = rt.Spec.AccessWindows.AllowedUntil(requestedAt, requestedAt.Add(duration))
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrInvalidAccessRequest, err)
+			}
+		}
+	}
 	ar := &api.AccessRequest{
 		TypeMeta: metav1.TypeMeta{
 			Kind:       "AccessRequest",
@@ -186,8 +488,9 @@ func (s *DefaultService) CreateAccessRequest(ctx context.Context, key *AccessReq
 		},
 		Spec: api.AccessRequestSpec{
 			Duration: metav1.Duration{
-				Duration: s.accessRequestDuration,
+				Duration: duration,
 			},
+			StartsAt: startsAtTime,
 			Role: api.TargetRole{
 				TemplateRef: api.TargetRoleTemplate{
 					Name:      binding.Spec.RoleTemplateRef.Name,
@@ -196,22 +499,75 @@ func (s *DefaultService) CreateAccessRequest(ctx context.Context, key *AccessReq
 				Ordinal:      binding.Spec.Ordinal,
 				FriendlyName: binding.Spec.FriendlyName,
 			},
-			Application: api.TargetApplication{
-				Name:      key.ApplicationName,
-				Namespace: key.ApplicationNamespace,
-			},
 			Subject: api.Subject{
 				Username: key.Username,
+				Group:    key.Group,
+				Groups:   key.Groups,
 			},
+			Justification: details.Justification,
+			TicketRef:     details.TicketRef,
+			BreakGlass:    details.BreakGlass,
 		},
 	}
-	ar, err := s.k8s.CreateAccessRequest(ctx, ar)
+	if details.BreakGlass {
+		ar.SetLabels(map[string]string{
+			api.BreakGlassLabel:    "true",
+			api.PendingReviewLabel: "true",
+		})
+	}
+	if key.IsProjectScoped() {
+		ar.Spec.Project = &api.TargetAppProject{Name: key.ProjectName}
+	} else {
+		ar.Spec.Application = api.TargetApplication{
+			Name:      key.ApplicationName,
+			Namespace: key.ApplicationNamespace,
+		}
+	}
+	if rt != nil && rt.Spec.Quotas != nil {
+		existing, err := s.k8s.ListAccessRequestsByRole(ctx, roleName, key.Namespace)
+		if err != nil {
+			return nil, fmt.Errorf("error listing access requests for role %s: %w", roleName, err)
+		}
+		err = rt.Spec.Quotas.Check(ar, existing.Items, time.Now())
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(s.signingKey) > 0 {
+		return s.createSignedAccessRequest(ctx, ar)
+	}
+	ar, err = s.k8s.CreateAccessRequest(ctx, ar)
 	if err != nil {
 		return nil, fmt.Errorf("error creating access request from k8s: %w", err)
 	}
 	return ar, nil
 }
 
+// createSignedAccessRequest will sign and create the given ar. The name is
+// covered by the signature so it is generated from the ar generateName
+// before the AccessRequest is created. A new name is generated if the
+// previous one is already taken.
+func (s *DefaultService) createSignedAccessRequest(ctx context.Context, ar *api.AccessRequest) (*api.AccessRequest, error) {
+	prefix := ar.GetGenerateName()
+	ar.SetGenerateName("")
+	for attempt := 1; ; attempt++ {
+		ar.SetName(prefix + utilrand.String(randomLength))
+		err := ar.Sign(s.signingKey)
+		if err != nil {
+			return nil, fmt.Errorf("error signing access request: %w", err)
+		}
+		created, err := s.k8s.CreateAccessRequest(ctx, ar)
+		if apierrors.IsAlreadyExists(err) && attempt < maxNameAttempts {
+			s.logger.Debug(fmt.Sprintf("AccessRequest %s already exists: generating a new name", ar.GetName()))
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error creating access request from k8s: %w", err)
+		}
+		return created, nil
+	}
+}
+
 func getAccessRequestPrefix(username, roleName string) string {
 	// If username is an email, we don't care about the email domain
 	username, _, _ = strings.Cut(username, "@")
@@ -21,6 +21,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
 )
 
 const (
@@ -40,7 +41,7 @@ func serviceSetup(t *testing.T) *serviceFixture {
 	logger.EXPECT().Debug(mock.Anything, mock.Anything).Maybe()
 	logger.EXPECT().Debug(mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Maybe()
 	logger.EXPECT().Info(mock.Anything, mock.Anything).Maybe()
-	svc := backend.NewDefaultService(persister, logger, ControllerNamespace, AccessRequestDuration)
+	svc := backend.NewDefaultService(persister, logger, ControllerNamespace, AccessRequestDuration, nil)
 	return &serviceFixture{
 		persister: persister,
 		logger:    logger,
@@ -57,15 +58,17 @@ func TestServiceCreateAccessRequest(t *testing.T) {
 			ApplicationName:      "some-app",
 			ApplicationNamespace: "app-ns",
 			Username:             "some-user",
+			Groups:               []string{"group1", "group2"},
 		}
 		ab := newDefaultAccessBinding()
+		f.persister.EXPECT().GetRoleTemplate(mock.Anything, ab.Spec.RoleTemplateRef.Name, ab.GetNamespace()).Return(nil, errors.NewNotFound(schema.GroupResource{}, "some-err"))
 		f.persister.EXPECT().CreateAccessRequest(mock.Anything, mock.Anything).
 			RunAndReturn(func(ctx context.Context, ar *api.AccessRequest) (*api.AccessRequest, error) {
 				return ar, nil
 			})
 
 		// When
-		result, err := f.svc.CreateAccessRequest(context.Background(), key, ab)
+		result, err := f.svc.CreateAccessRequest(context.Background(), key, ab, backend.AccessRequestDetails{})
 
 		// Then
 		assert.NoError(t, err)
@@ -75,10 +78,129 @@ func TestServiceCreateAccessRequest(t *testing.T) {
 		assert.Equal(t, key.ApplicationName, result.Spec.Application.Name)
 		assert.Equal(t, key.ApplicationNamespace, result.Spec.Application.Namespace)
 		assert.Equal(t, key.Username, result.Spec.Subject.Username)
+		assert.Equal(t, key.Groups, result.Spec.Subject.Groups)
 		assert.Equal(t, ab.Spec.FriendlyName, result.Spec.Role.FriendlyName)
 		assert.Equal(t, ab.Spec.Ordinal, result.Spec.Role.Ordinal)
 		assert.Equal(t, ab.Spec.RoleTemplateRef.Name, result.Spec.Role.TemplateRef.Name)
 		assert.Equal(t, AccessRequestDuration, result.Spec.Duration.Duration)
+		assert.NotContains(t, result.GetAnnotations(), api.SignatureAnnotation)
+	})
+	t.Run("will sign access request if signing key is configured", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		signingKey := []byte("some-secret")
+		svc := backend.NewDefaultService(f.persister, f.logger, ControllerNamespace, AccessRequestDuration, signingKey)
+		key := &backend.AccessRequestKey{
+			Namespace:            "some-namespace",
+			ApplicationName:      "some-app",
+			ApplicationNamespace: "app-ns",
+			Username:             "some-user",
+			Groups:               []string{"group1"},
+		}
+		ab := newDefaultAccessBinding()
+		f.persister.EXPECT().GetRoleTemplate(mock.Anything, ab.Spec.RoleTemplateRef.Name, ab.GetNamespace()).Return(nil, errors.NewNotFound(schema.GroupResource{}, "some-err"))
+		f.persister.EXPECT().CreateAccessRequest(mock.Anything, mock.Anything).
+			RunAndReturn(func(ctx context.Context, ar *api.AccessRequest) (*api.AccessRequest, error) {
+				return ar, nil
+			})
+
+		// When
+		result, err := svc.CreateAccessRequest(context.Background(), key, ab, backend.AccessRequestDetails{})
+
+		// Then
+		assert.NoError(t, err)
+		require.NotNil(t, result)
+		assert.NotEmpty(t, result.GetAnnotations()[api.SignatureAnnotation])
+		assert.NoError(t, result.VerifySignature(signingKey))
+		assert.Empty(t, result.GetGenerateName())
+		assert.Regexp(t, "^some-user-test-role-[a-z0-9]{5}$", result.GetName())
+	})
+	t.Run("will generate a new name if the signed access request already exists", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		signingKey := []byte("some-secret")
+		svc := backend.NewDefaultService(f.persister, f.logger, ControllerNamespace, AccessRequestDuration, signingKey)
+		key := &backend.AccessRequestKey{
+			Namespace:            "some-namespace",
+			ApplicationName:      "some-app",
+			ApplicationNamespace: "app-ns",
+			Username:             "some-user",
+			Groups:               []string{"group1"},
+		}
+		ab := newDefaultAccessBinding()
+		names := []string{}
+		f.persister.EXPECT().GetRoleTemplate(mock.Anything, ab.Spec.RoleTemplateRef.Name, ab.GetNamespace()).Return(nil, errors.NewNotFound(schema.GroupResource{}, "some-err"))
+		f.persister.EXPECT().CreateAccessRequest(mock.Anything, mock.Anything).
+			RunAndReturn(func(ctx context.Context, ar *api.AccessRequest) (*api.AccessRequest, error) {
+				names = append(names, ar.GetName())
+				if len(names) == 1 {
+					return nil, errors.NewAlreadyExists(schema.GroupResource{}, ar.GetName())
+				}
+				return ar, nil
+			}).
+			Twice()
+
+		// When
+		result, err := svc.CreateAccessRequest(context.Background(), key, ab, backend.AccessRequestDetails{})
+
+		// Then
+		assert.NoError(t, err)
+		require.NotNil(t, result)
+		require.Len(t, names, 2)
+		assert.Equal(t, names[1], result.GetName())
+		assert.Regexp(t, "^some-user-test-role-[a-z0-9]{5}$", result.GetName())
+		assert.NoError(t, result.VerifySignature(signingKey))
+	})
+	t.Run("will return error if all generated names already exist", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		signingKey := []byte("some-secret")
+		svc := backend.NewDefaultService(f.persister, f.logger, ControllerNamespace, AccessRequestDuration, signingKey)
+		key := &backend.AccessRequestKey{
+			Namespace:            "some-namespace",
+			ApplicationName:      "some-app",
+			ApplicationNamespace: "app-ns",
+			Username:             "some-user",
+			Groups:               []string{"group1"},
+		}
+		ab := newDefaultAccessBinding()
+		f.persister.EXPECT().GetRoleTemplate(mock.Anything, ab.Spec.RoleTemplateRef.Name, ab.GetNamespace()).Return(nil, errors.NewNotFound(schema.GroupResource{}, "some-err"))
+		f.persister.EXPECT().CreateAccessRequest(mock.Anything, mock.Anything).
+			Return(nil, errors.NewAlreadyExists(schema.GroupResource{}, "some-name")).
+			Times(5)
+
+		// When
+		result, err := svc.CreateAccessRequest(context.Background(), key, ab, backend.AccessRequestDetails{})
+
+		// Then
+		assert.True(t, errors.IsAlreadyExists(err))
+		assert.Nil(t, result)
+	})
+	t.Run("will create project-scoped access request successfully", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		key := &backend.AccessRequestKey{
+			Namespace:   "some-namespace",
+			Username:    "some-user",
+			ProjectName: "some-project",
+		}
+		ab := newDefaultAccessBinding()
+		f.persister.EXPECT().GetRoleTemplate(mock.Anything, ab.Spec.RoleTemplateRef.Name, ab.GetNamespace()).Return(nil, errors.NewNotFound(schema.GroupResource{}, "some-err"))
+		f.persister.EXPECT().CreateAccessRequest(mock.Anything, mock.Anything).
+			RunAndReturn(func(ctx context.Context, ar *api.AccessRequest) (*api.AccessRequest, error) {
+				return ar, nil
+			})
+
+		// When
+		result, err := f.svc.CreateAccessRequest(context.Background(), key, ab, backend.AccessRequestDetails{})
+
+		// Then
+		assert.NoError(t, err)
+		require.NotNil(t, result)
+		assert.True(t, result.IsProjectScoped())
+		assert.Equal(t, "some-project", result.Spec.Project.Name)
+		assert.Empty(t, result.Spec.Application.Name)
+		assert.Empty(t, result.Spec.Application.Namespace)
 	})
 	t.Run("will return error if k8s request fails", func(t *testing.T) {
 		// Given
@@ -90,16 +212,335 @@ func TestServiceCreateAccessRequest(t *testing.T) {
 			Username:             "some-user",
 		}
 		ab := newDefaultAccessBinding()
+		rt := utils.NewRoleTemplate(ab.Spec.RoleTemplateRef.Name, ab.GetNamespace(), "role", nil)
+		f.persister.EXPECT().GetRoleTemplate(mock.Anything, ab.Spec.RoleTemplateRef.Name, ab.GetNamespace()).Return(rt, nil)
 		f.persister.EXPECT().CreateAccessRequest(mock.Anything, mock.Anything).Return(nil, fmt.Errorf("some internal error"))
 
 		// When
-		result, err := f.svc.CreateAccessRequest(context.Background(), key, ab)
+		result, err := f.svc.CreateAccessRequest(context.Background(), key, ab, backend.AccessRequestDetails{})
 
 		// Then
 		assert.Error(t, err)
 		assert.Nil(t, result)
 		assert.Contains(t, err.Error(), "some internal error")
 	})
+	t.Run("will schedule access request if startsAt is provided", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		key := &backend.AccessRequestKey{
+			Namespace:            "some-namespace",
+			ApplicationName:      "some-app",
+			ApplicationNamespace: "app-ns",
+			Username:             "some-user",
+		}
+		ab := newDefaultAccessBinding()
+		rt := utils.NewRoleTemplate(ab.Spec.RoleTemplateRef.Name, ab.GetNamespace(), "role", nil)
+		rt.Spec.MaxDuration = &metav1.Duration{Duration: time.Hour}
+		f.persister.EXPECT().GetRoleTemplate(mock.Anything, ab.Spec.RoleTemplateRef.Name, ab.GetNamespace()).Return(rt, nil)
+		f.persister.EXPECT().CreateAccessRequest(mock.Anything, mock.Anything).
+			RunAndReturn(func(ctx context.Context, ar *api.AccessRequest) (*api.AccessRequest, error) {
+				return ar, nil
+			})
+		startsAt := time.Now().Add(time.Hour)
+
+		// When
+		result, err := f.svc.CreateAccessRequest(context.Background(), key, ab, backend.AccessRequestDetails{StartsAt: &startsAt})
+
+		// Then
+		require.NoError(t, err)
+		require.NotNil(t, result.Spec.StartsAt)
+		assert.True(t, startsAt.Equal(result.Spec.StartsAt.Time))
+	})
+	t.Run("will return invalid error if startsAt is in the past", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		key := &backend.AccessRequestKey{Namespace: "some-namespace", Username: "some-user"}
+		startsAt := time.Now().Add(-time.Minute)
+
+		// When
+		result, err := f.svc.CreateAccessRequest(context.Background(), key, newDefaultAccessBinding(), backend.AccessRequestDetails{StartsAt: &startsAt})
+
+		// Then
+		assert.ErrorIs(t, err, backend.ErrInvalidAccessRequest)
+		assert.Nil(t, result)
+	})
+	t.Run("will return invalid error if duration exceeds the role maximum", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		key := &backend.AccessRequestKey{Namespace: "some-namespace", Username: "some-user"}
+		ab := newDefaultAccessBinding()
+		rt := utils.NewRoleTemplate(ab.Spec.RoleTemplateRef.Name, ab.GetNamespace(), "role", nil)
+		rt.Spec.MaxDuration = &metav1.Duration{Duration: time.Second}
+		f.persister.EXPECT().GetRoleTemplate(mock.Anything, ab.Spec.RoleTemplateRef.Name, ab.GetNamespace()).Return(rt, nil)
+		startsAt := time.Now().Add(time.Hour)
+
+		// When
+		result, err := f.svc.CreateAccessRequest(context.Background(), key, ab, backend.AccessRequestDetails{StartsAt: &startsAt})
+
+		// Then
+		assert.ErrorIs(t, err, backend.ErrInvalidAccessRequest)
+		assert.Contains(t, err.Error(), "exceeds the maximum duration")
+		assert.Nil(t, result)
+	})
+	t.Run("will return invalid error if access windows do not allow the request", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		key := &backend.AccessRequestKey{Namespace: "some-namespace", Username: "some-user"}
+		ab := newDefaultAccessBinding()
+		rt := utils.NewRoleTemplate(ab.Spec.RoleTemplateRef.Name, ab.GetNamespace(), "role", nil)
+		rt.Spec.AccessWindows = &api.AccessWindows{
+			Blackouts: []api.BlackoutWindow{{
+				Start:  metav1.NewTime(time.Now().Add(-time.Hour)),
+				End:    metav1.NewTime(time.Now().Add(time.Hour)),
+				Reason: "release freeze",
+			}},
+		}
+		f.persister.EXPECT().GetRoleTemplate(mock.Anything, ab.Spec.RoleTemplateRef.Name, ab.GetNamespace()).Return(rt, nil)
+
+		// When
+		result, err := f.svc.CreateAccessRequest(context.Background(), key, ab, backend.AccessRequestDetails{})
+
+		// Then
+		assert.ErrorIs(t, err, backend.ErrInvalidAccessRequest)
+		assert.ErrorIs(t, err, api.ErrOutsideAccessWindow)
+		assert.Contains(t, err.Error(), "release freeze")
+		assert.Nil(t, result)
+	})
+	t.Run("will create access request with the role default duration", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		key := &backend.AccessRequestKey{Namespace: "some-namespace", Username: "some-user"}
+		ab := newDefaultAccessBinding()
+		rt := utils.NewRoleTemplate(ab.Spec.RoleTemplateRef.Name, ab.GetNamespace(), "role", nil)
+		rt.Spec.DefaultDuration = &metav1.Duration{Duration: 15 * time.Minute}
+		f.persister.EXPECT().GetRoleTemplate(mock.Anything, ab.Spec.RoleTemplateRef.Name, ab.GetNamespace()).Return(rt, nil)
+		f.persister.EXPECT().CreateAccessRequest(mock.Anything, mock.Anything).
+			RunAndReturn(func(ctx context.Context, ar *api.AccessRequest) (*api.AccessRequest, error) {
+				return ar, nil
+			})
+
+		// When
+		result, err := f.svc.CreateAccessRequest(context.Background(), key, ab, backend.AccessRequestDetails{})
+
+		// Then
+		assert.NoError(t, err)
+		require.NotNil(t, result)
+		assert.Equal(t, 15*time.Minute, result.Spec.Duration.Duration)
+	})
+	t.Run("will create access request with the requested duration", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		key := &backend.AccessRequestKey{Namespace: "some-namespace", Username: "some-user"}
+		ab := newDefaultAccessBinding()
+		rt := utils.NewRoleTemplate(ab.Spec.RoleTemplateRef.Name, ab.GetNamespace(), "role", nil)
+		rt.Spec.DefaultDuration = &metav1.Duration{Duration: 15 * time.Minute}
+		rt.Spec.MaxDuration = &metav1.Duration{Duration: time.Hour}
+		f.persister.EXPECT().GetRoleTemplate(mock.Anything, ab.Spec.RoleTemplateRef.Name, ab.GetNamespace()).Return(rt, nil)
+		f.persister.EXPECT().CreateAccessRequest(mock.Anything, mock.Anything).
+			RunAndReturn(func(ctx context.Context, ar *api.AccessRequest) (*api.AccessRequest, error) {
+				return ar, nil
+			})
+
+		// When
+		result, err := f.svc.CreateAccessRequest(context.Background(), key, ab, backend.AccessRequestDetails{Duration: 45 * time.Minute})
+
+		// Then
+		assert.NoError(t, err)
+		require.NotNil(t, result)
+		assert.Equal(t, 45*time.Minute, result.Spec.Duration.Duration)
+	})
+	t.Run("will return invalid error if the requested duration exceeds the role maximum", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		key := &backend.AccessRequestKey{Namespace: "some-namespace", Username: "some-user"}
+		ab := newDefaultAccessBinding()
+		rt := utils.NewRoleTemplate(ab.Spec.RoleTemplateRef.Name, ab.GetNamespace(), "role", nil)
+		rt.Spec.MaxDuration = &metav1.Duration{Duration: time.Hour}
+		f.persister.EXPECT().GetRoleTemplate(mock.Anything, ab.Spec.RoleTemplateRef.Name, ab.GetNamespace()).Return(rt, nil)
+
+		// When
+		result, err := f.svc.CreateAccessRequest(context.Background(), key, ab, backend.AccessRequestDetails{Duration: 2 * time.Hour})
+
+		// Then
+		assert.ErrorIs(t, err, backend.ErrInvalidAccessRequest)
+		assert.Contains(t, err.Error(), "exceeds the maximum duration of 1h0m0s")
+		assert.Nil(t, result)
+	})
+	t.Run("will create break-glass access request capped to the break-glass maximum duration", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		key := &backend.AccessRequestKey{Namespace: "some-namespace", Username: "some-user"}
+		ab := newDefaultAccessBinding()
+		ab.Spec.BreakGlass = true
+		rt := utils.NewRoleTemplate(ab.Spec.RoleTemplateRef.Name, ab.GetNamespace(), "role", nil)
+		rt.Spec.DefaultDuration = &metav1.Duration{Duration: 4 * time.Hour}
+		rt.Spec.BreakGlass = &api.BreakGlassSpec{
+			MaxDuration: metav1.Duration{Duration: time.Hour},
+			Reviewers:   []string{"some-auditor"},
+		}
+		f.persister.EXPECT().GetRoleTemplate(mock.Anything, ab.Spec.RoleTemplateRef.Name, ab.GetNamespace()).Return(rt, nil)
+		f.persister.EXPECT().CreateAccessRequest(mock.Anything, mock.Anything).
+			RunAndReturn(func(ctx context.Context, ar *api.AccessRequest) (*api.AccessRequest, error) {
+				return ar, nil
+			})
+
+		// When
+		result, err := f.svc.CreateAccessRequest(context.Background(), key, ab, backend.AccessRequestDetails{BreakGlass: true})
+
+		// Then
+		assert.NoError(t, err)
+		require.NotNil(t, result)
+		assert.True(t, result.Spec.BreakGlass)
+		assert.True(t, result.IsPendingReview())
+		assert.Equal(t, time.Hour, result.Spec.Duration.Duration)
+		assert.Equal(t, "true", result.GetLabels()[api.BreakGlassLabel])
+		assert.Equal(t, "true", result.GetLabels()[api.PendingReviewLabel])
+	})
+	t.Run("will return invalid error if the role does not allow break-glass", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		key := &backend.AccessRequestKey{Namespace: "some-namespace", Username: "some-user"}
+		ab := newDefaultAccessBinding()
+		rt := utils.NewRoleTemplate(ab.Spec.RoleTemplateRef.Name, ab.GetNamespace(), "role", nil)
+		f.persister.EXPECT().GetRoleTemplate(mock.Anything, ab.Spec.RoleTemplateRef.Name, ab.GetNamespace()).Return(rt, nil)
+
+		// When
+		result, err := f.svc.CreateAccessRequest(context.Background(), key, ab, backend.AccessRequestDetails{BreakGlass: true})
+
+		// Then
+		assert.ErrorIs(t, err, backend.ErrInvalidAccessRequest)
+		assert.Contains(t, err.Error(), "does not allow break-glass access")
+		assert.Nil(t, result)
+	})
+	t.Run("will return invalid error if the requested duration exceeds the break-glass maximum", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		key := &backend.AccessRequestKey{Namespace: "some-namespace", Username: "some-user"}
+		ab := newDefaultAccessBinding()
+		rt := utils.NewRoleTemplate(ab.Spec.RoleTemplateRef.Name, ab.GetNamespace(), "role", nil)
+		rt.Spec.BreakGlass = &api.BreakGlassSpec{
+			MaxDuration: metav1.Duration{Duration: time.Hour},
+			Reviewers:   []string{"some-auditor"},
+		}
+		f.persister.EXPECT().GetRoleTemplate(mock.Anything, ab.Spec.RoleTemplateRef.Name, ab.GetNamespace()).Return(rt, nil)
+
+		// When
+		result, err := f.svc.CreateAccessRequest(context.Background(), key, ab, backend.AccessRequestDetails{BreakGlass: true, Duration: 2 * time.Hour})
+
+		// Then
+		assert.ErrorIs(t, err, backend.ErrInvalidAccessRequest)
+		assert.Contains(t, err.Error(), "exceeds the break-glass maximum duration of 1h0m0s")
+		assert.Nil(t, result)
+	})
+	t.Run("will return quota error if the role quota is exceeded", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		key := &backend.AccessRequestKey{Namespace: "some-namespace", Username: "some-user"}
+		ab := newDefaultAccessBinding()
+		rt := utils.NewRoleTemplate(ab.Spec.RoleTemplateRef.Name, ab.GetNamespace(), "role", nil)
+		rt.Spec.Quotas = &api.Quotas{User: &api.Quota{MaxConcurrent: ptr.To(int32(1))}}
+		granted := utils.NewAccessRequestGranted(utils.WithName("granted"))
+		granted.Spec.Subject.Username = key.Username
+		granted.Spec.Role.TemplateRef = api.TargetRoleTemplate{Name: ab.Spec.RoleTemplateRef.Name, Namespace: ab.GetNamespace()}
+		f.persister.EXPECT().GetRoleTemplate(mock.Anything, ab.Spec.RoleTemplateRef.Name, ab.GetNamespace()).Return(rt, nil)
+		f.persister.EXPECT().ListAccessRequestsByRole(mock.Anything, ab.Spec.RoleTemplateRef.Name, key.Namespace).
+			Return(&api.AccessRequestList{Items: []api.AccessRequest{*granted}}, nil)
+
+		// When
+		result, err := f.svc.CreateAccessRequest(context.Background(), key, ab, backend.AccessRequestDetails{})
+
+		// Then
+		assert.ErrorIs(t, err, api.ErrQuotaExceeded)
+		assert.Contains(t, err.Error(), "user quota allows at most 1 concurrent requests")
+		assert.Nil(t, result)
+	})
+	t.Run("will create access request within the role quota", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		key := &backend.AccessRequestKey{Namespace: "some-namespace", Username: "some-user"}
+		ab := newDefaultAccessBinding()
+		rt := utils.NewRoleTemplate(ab.Spec.RoleTemplateRef.Name, ab.GetNamespace(), "role", nil)
+		rt.Spec.Quotas = &api.Quotas{User: &api.Quota{MaxConcurrent: ptr.To(int32(1))}}
+		f.persister.EXPECT().GetRoleTemplate(mock.Anything, ab.Spec.RoleTemplateRef.Name, ab.GetNamespace()).Return(rt, nil)
+		f.persister.EXPECT().ListAccessRequestsByRole(mock.Anything, ab.Spec.RoleTemplateRef.Name, key.Namespace).
+			Return(&api.AccessRequestList{}, nil)
+		f.persister.EXPECT().CreateAccessRequest(mock.Anything, mock.Anything).
+			RunAndReturn(func(ctx context.Context, ar *api.AccessRequest) (*api.AccessRequest, error) {
+				return ar, nil
+			})
+
+		// When
+		result, err := f.svc.CreateAccessRequest(context.Background(), key, ab, backend.AccessRequestDetails{})
+
+		// Then
+		assert.NoError(t, err)
+		assert.NotNil(t, result)
+	})
+	t.Run("will return invalid error if the required justification is missing", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		key := &backend.AccessRequestKey{Namespace: "some-namespace", Username: "some-user"}
+		ab := newDefaultAccessBinding()
+		rt := utils.NewRoleTemplate(ab.Spec.RoleTemplateRef.Name, ab.GetNamespace(), "role", nil)
+		rt.Spec.Justification = &api.JustificationSpec{Required: true}
+		f.persister.EXPECT().GetRoleTemplate(mock.Anything, ab.Spec.RoleTemplateRef.Name, ab.GetNamespace()).Return(rt, nil)
+
+		// When
+		result, err := f.svc.CreateAccessRequest(context.Background(), key, ab, backend.AccessRequestDetails{TicketRef: "INC-123"})
+
+		// Then
+		assert.ErrorIs(t, err, backend.ErrInvalidAccessRequest)
+		assert.Contains(t, err.Error(), "justification is required")
+		assert.Nil(t, result)
+	})
+	t.Run("will create access request with justification", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		key := &backend.AccessRequestKey{Namespace: "some-namespace", Username: "some-user"}
+		ab := newDefaultAccessBinding()
+		rt := utils.NewRoleTemplate(ab.Spec.RoleTemplateRef.Name, ab.GetNamespace(), "role", nil)
+		rt.Spec.Justification = &api.JustificationSpec{Required: true, TicketPattern: "^INC-[0-9]+$"}
+		f.persister.EXPECT().GetRoleTemplate(mock.Anything, ab.Spec.RoleTemplateRef.Name, ab.GetNamespace()).Return(rt, nil)
+		f.persister.EXPECT().CreateAccessRequest(mock.Anything, mock.Anything).
+			RunAndReturn(func(ctx context.Context, ar *api.AccessRequest) (*api.AccessRequest, error) {
+				return ar, nil
+			})
+
+		// When
+		result, err := f.svc.CreateAccessRequest(context.Background(), key, ab, backend.AccessRequestDetails{Justification: "incident response", TicketRef: "INC-123"})
+
+		// Then
+		assert.NoError(t, err)
+		require.NotNil(t, result)
+		assert.Equal(t, "incident response", result.Spec.Justification)
+		assert.Equal(t, "INC-123", result.Spec.TicketRef)
+	})
+	t.Run("will evaluate access windows at the scheduled start time", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		key := &backend.AccessRequestKey{Namespace: "some-namespace", Username: "some-user"}
+		ab := newDefaultAccessBinding()
+		rt := utils.NewRoleTemplate(ab.Spec.RoleTemplateRef.Name, ab.GetNamespace(), "role", nil)
+		rt.Spec.AccessWindows = &api.AccessWindows{
+			Blackouts: []api.BlackoutWindow{{
+				Start: metav1.NewTime(time.Now().Add(-time.Hour)),
+				End:   metav1.NewTime(time.Now().Add(time.Hour)),
+			}},
+		}
+		f.persister.EXPECT().GetRoleTemplate(mock.Anything, ab.Spec.RoleTemplateRef.Name, ab.GetNamespace()).Return(rt, nil)
+		f.persister.EXPECT().CreateAccessRequest(mock.Anything, mock.Anything).
+			RunAndReturn(func(ctx context.Context, ar *api.AccessRequest) (*api.AccessRequest, error) {
+				return ar, nil
+			})
+		startsAt := time.Now().Add(2 * time.Hour)
+
+		// When
+		result, err := f.svc.CreateAccessRequest(context.Background(), key, ab, backend.AccessRequestDetails{StartsAt: &startsAt})
+
+		// Then
+		assert.NoError(t, err)
+		assert.NotNil(t, result)
+	})
 }
 
 func TestServiceListAccessRequest(t *testing.T) {
@@ -222,6 +663,32 @@ func TestServiceGetAccessRequestByRole(t *testing.T) {
 		assert.Equal(t, ar.GetName(), result.GetName())
 		assert.Equal(t, ar.GetNamespace(), result.GetNamespace())
 	})
+	t.Run("will not return application access request when requesting project scope", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		key := &backend.AccessRequestKey{
+			Namespace:            "some-namespace",
+			ApplicationName:      "some-app",
+			ApplicationNamespace: "app-ns",
+			Username:             "some-user",
+			ProjectName:          "some-project",
+		}
+		roleName := "some-role"
+		ar := newAccessRequest(key, roleName)
+		projectKey := &backend.AccessRequestKey{
+			Namespace:   key.Namespace,
+			Username:    key.Username,
+			ProjectName: key.ProjectName,
+		}
+		f.persister.EXPECT().ListAccessRequests(mock.Anything, projectKey).Return(&api.AccessRequestList{Items: []api.AccessRequest{*ar}}, nil)
+
+		// When
+		result, err := f.svc.GetAccessRequestByRole(context.Background(), projectKey, roleName)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Nil(t, result)
+	})
 	t.Run("will return nil if no access request match role", func(t *testing.T) {
 		// Given
 		f := serviceSetup(t)
@@ -241,6 +708,27 @@ func TestServiceGetAccessRequestByRole(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Nil(t, ar)
 	})
+	t.Run("will return nil if access request for role is revoked", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		key := &backend.AccessRequestKey{
+			Namespace:            "some-namespace",
+			ApplicationName:      "some-app",
+			ApplicationNamespace: "app-ns",
+			Username:             "some-user",
+		}
+		roleName := "some-role"
+		revoked := newAccessRequest(key, roleName)
+		revoked.Status.RequestState = api.RevokedStatus
+		f.persister.EXPECT().ListAccessRequests(mock.Anything, key).Return(&api.AccessRequestList{Items: []api.AccessRequest{*revoked}}, nil)
+
+		// When
+		ar, err := f.svc.GetAccessRequestByRole(context.Background(), key, roleName)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Nil(t, ar)
+	})
 	t.Run("will return error if k8s request fails", func(t *testing.T) {
 		// Given
 		f := serviceSetup(t)
@@ -277,7 +765,7 @@ func TestServiceGetGrantingAccessBinding(t *testing.T) {
 		f.persister.EXPECT().ListAccessBindings(mock.Anything, roleName, ControllerNamespace).Return(&api.AccessBindingList{}, nil)
 
 		// When
-		result, err := f.svc.GetGrantingAccessBinding(context.Background(), roleName, namespace, groups, app, project)
+		result, err := f.svc.GetGrantingAccessBinding(context.Background(), roleName, namespace, groups, "", false, app, project)
 
 		// Then
 		assert.NoError(t, err)
@@ -298,13 +786,65 @@ func TestServiceGetGrantingAccessBinding(t *testing.T) {
 		f.persister.EXPECT().ListAccessBindings(mock.Anything, roleName, ControllerNamespace).Return(&api.AccessBindingList{Items: []api.AccessBinding{*ab}}, nil)
 
 		// When
-		result, err := f.svc.GetGrantingAccessBinding(context.Background(), roleName, namespace, groups, app, project)
+		result, err := f.svc.GetGrantingAccessBinding(context.Background(), roleName, namespace, groups, "", false, app, project)
 
 		// Then
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
 		assert.Equal(t, ab, result)
 	})
+	t.Run("will only return binding allowing the target group", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		app := &unstructured.Unstructured{}
+		project := &unstructured.Unstructured{}
+		roleName := "some-role"
+		namespace := "some-namespace"
+		subject := "my-subject"
+		groups := []string{subject}
+		ab := newAccessBinding(namespace, roleName, subject)
+		ab2 := newAccessBinding(namespace, roleName, subject)
+		ab2.Name = "group-binding"
+		ab2.Spec.GroupTargets = []string{"on-call"}
+		f.persister.EXPECT().ListAccessBindings(mock.Anything, roleName, namespace).Return(&api.AccessBindingList{Items: []api.AccessBinding{*ab, *ab2}}, nil)
+		f.persister.EXPECT().ListAccessBindings(mock.Anything, roleName, ControllerNamespace).Return(&api.AccessBindingList{}, nil)
+
+		// When
+		result, err := f.svc.GetGrantingAccessBinding(context.Background(), roleName, namespace, groups, "on-call", false, app, project)
+		other, otherErr := f.svc.GetGrantingAccessBinding(context.Background(), roleName, namespace, groups, "admins", false, app, project)
+
+		// Then
+		assert.NoError(t, err)
+		assert.NoError(t, otherErr)
+		assert.Equal(t, ab2, result)
+		assert.Nil(t, other)
+	})
+	t.Run("will only return break-glass bindings for break-glass requests", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		app := &unstructured.Unstructured{}
+		project := &unstructured.Unstructured{}
+		roleName := "some-role"
+		namespace := "some-namespace"
+		subject := "my-subject"
+		groups := []string{subject}
+		ab := newAccessBinding(namespace, roleName, subject)
+		ab2 := newAccessBinding(namespace, roleName, subject)
+		ab2.Name = "break-glass-binding"
+		ab2.Spec.BreakGlass = true
+		f.persister.EXPECT().ListAccessBindings(mock.Anything, roleName, namespace).Return(&api.AccessBindingList{Items: []api.AccessBinding{*ab2, *ab}}, nil)
+		f.persister.EXPECT().ListAccessBindings(mock.Anything, roleName, ControllerNamespace).Return(&api.AccessBindingList{}, nil)
+
+		// When
+		result, err := f.svc.GetGrantingAccessBinding(context.Background(), roleName, namespace, groups, "", true, app, project)
+		other, otherErr := f.svc.GetGrantingAccessBinding(context.Background(), roleName, namespace, groups, "", false, app, project)
+
+		// Then
+		assert.NoError(t, err)
+		assert.NoError(t, otherErr)
+		assert.Equal(t, ab2, result)
+		assert.Equal(t, ab, other)
+	})
 	t.Run("will prioritize access binding from target namespace", func(t *testing.T) {
 		// Given
 		f := serviceSetup(t)
@@ -321,7 +861,7 @@ func TestServiceGetGrantingAccessBinding(t *testing.T) {
 		f.persister.EXPECT().ListAccessBindings(mock.Anything, roleName, ControllerNamespace).Return(&api.AccessBindingList{Items: []api.AccessBinding{*ab2}}, nil)
 
 		// When
-		result, err := f.svc.GetGrantingAccessBinding(context.Background(), roleName, namespace, groups, app, project)
+		result, err := f.svc.GetGrantingAccessBinding(context.Background(), roleName, namespace, groups, "", false, app, project)
 
 		// Then
 		assert.NoError(t, err)
@@ -342,7 +882,7 @@ func TestServiceGetGrantingAccessBinding(t *testing.T) {
 		f.persister.EXPECT().ListAccessBindings(mock.Anything, roleName, ControllerNamespace).Return(&api.AccessBindingList{Items: []api.AccessBinding{*ab}}, nil).Maybe()
 
 		// When
-		result, err := f.svc.GetGrantingAccessBinding(context.Background(), roleName, namespace, groups, app, project)
+		result, err := f.svc.GetGrantingAccessBinding(context.Background(), roleName, namespace, groups, "", false, app, project)
 
 		// Then
 		assert.Error(t, err)
@@ -363,7 +903,7 @@ func TestServiceGetGrantingAccessBinding(t *testing.T) {
 		f.persister.EXPECT().ListAccessBindings(mock.Anything, roleName, ControllerNamespace).Return(nil, fmt.Errorf("some internal error"))
 
 		// When
-		result, err := f.svc.GetGrantingAccessBinding(context.Background(), roleName, namespace, groups, app, project)
+		result, err := f.svc.GetGrantingAccessBinding(context.Background(), roleName, namespace, groups, "", false, app, project)
 
 		// Then
 		assert.Error(t, err)
@@ -383,7 +923,7 @@ func TestServiceGetGrantingAccessBinding(t *testing.T) {
 		f.persister.EXPECT().ListAccessBindings(mock.Anything, roleName, ControllerNamespace).Return(&api.AccessBindingList{}, nil)
 
 		// When
-		result, err := f.svc.GetGrantingAccessBinding(context.Background(), roleName, namespace, groups, app, project)
+		result, err := f.svc.GetGrantingAccessBinding(context.Background(), roleName, namespace, groups, "", false, app, project)
 
 		// Then
 		assert.NoError(t, err)
@@ -403,7 +943,7 @@ func TestServiceGetGrantingAccessBinding(t *testing.T) {
 		f.persister.EXPECT().ListAccessBindings(mock.Anything, roleName, ControllerNamespace).Return(&api.AccessBindingList{}, nil)
 
 		// When
-		result, err := f.svc.GetGrantingAccessBinding(context.Background(), roleName, namespace, groups, app, project)
+		result, err := f.svc.GetGrantingAccessBinding(context.Background(), roleName, namespace, groups, "", false, app, project)
 
 		// Then
 		assert.NoError(t, err)
@@ -437,7 +977,7 @@ func TestServiceGetGrantingAccessBinding(t *testing.T) {
 		f.persister.EXPECT().ListAccessBindings(mock.Anything, roleName, ControllerNamespace).Return(&api.AccessBindingList{}, nil)
 
 		// When
-		result, err := f.svc.GetGrantingAccessBinding(context.Background(), roleName, namespace, groups, app, project)
+		result, err := f.svc.GetGrantingAccessBinding(context.Background(), roleName, namespace, groups, "", false, app, project)
 
 		// Then
 		assert.NoError(t, err)
@@ -462,7 +1002,7 @@ func TestServiceGetGrantingAccessBinding(t *testing.T) {
 		}).Once()
 
 		// When
-		result, err := f.svc.GetGrantingAccessBinding(context.Background(), roleName, namespace, groups, app, project)
+		result, err := f.svc.GetGrantingAccessBinding(context.Background(), roleName, namespace, groups, "", false, app, project)
 
 		// Then
 		assert.NoError(t, err)
@@ -471,6 +1011,182 @@ func TestServiceGetGrantingAccessBinding(t *testing.T) {
 	})
 }
 
+func TestServiceListGrantableRoles(t *testing.T) {
+	namespace := "some-namespace"
+	groups := []string{"my-subject"}
+	app := &unstructured.Unstructured{}
+	project := &unstructured.Unstructured{}
+	newRoleTemplate := func(name string) *api.RoleTemplate {
+		rt := utils.NewRoleTemplate(name, namespace, name, []string{"some-policy"})
+		rt.Spec.Description = fmt.Sprintf("%s description", name)
+		return rt
+	}
+	t.Run("will return the roles granted to the groups", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		devops := newAccessBinding(namespace, "devops", "my-subject")
+		devops.Name = "devops"
+		devops.Spec.Ordinal = 2
+		admin := newAccessBinding(ControllerNamespace, "admin", "my-subject")
+		admin.Name = "admin"
+		admin.Spec.Ordinal = 1
+		other := newAccessBinding(namespace, "other", "another-subject")
+		other.Name = "other"
+		rt := newRoleTemplate("devops")
+		rt.Spec.DefaultDuration = &metav1.Duration{Duration: time.Hour}
+		rt.Spec.MaxDuration = &metav1.Duration{Duration: 2 * time.Hour}
+		f.persister.EXPECT().ListAccessBindings(mock.Anything, "", namespace).Return(&api.AccessBindingList{Items: []api.AccessBinding{*devops, *other}}, nil)
+		f.persister.EXPECT().ListAccessBindings(mock.Anything, "", ControllerNamespace).Return(&api.AccessBindingList{Items: []api.AccessBinding{*admin}}, nil)
+		f.persister.EXPECT().GetRoleTemplate(mock.Anything, "devops", namespace).Return(rt, nil)
+		f.persister.EXPECT().GetRoleTemplate(mock.Anything, "admin", ControllerNamespace).Return(newRoleTemplate("admin"), nil)
+
+		// When
+		result, err := f.svc.ListGrantableRoles(context.Background(), namespace, groups, app, project)
+
+		// Then
+		assert.NoError(t, err)
+		require.Len(t, result, 2)
+		assert.Equal(t, "admin", result[0].Binding.Name)
+		assert.Equal(t, AccessRequestDuration, result[0].DefaultDuration)
+		assert.Equal(t, time.Duration(0), result[0].MaxDuration)
+		assert.Equal(t, "devops", result[1].Binding.Name)
+		assert.Equal(t, rt, result[1].RoleTemplate)
+		assert.Equal(t, time.Hour, result[1].DefaultDuration)
+		assert.Equal(t, 2*time.Hour, result[1].MaxDuration)
+	})
+	t.Run("will return each role only once", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		ab := newAccessBinding(namespace, "devops", "my-subject")
+		ab2 := newAccessBinding(namespace, "devops", "my-subject")
+		f.persister.EXPECT().ListAccessBindings(mock.Anything, "", namespace).Return(&api.AccessBindingList{Items: []api.AccessBinding{*ab, *ab2}}, nil)
+		f.persister.EXPECT().ListAccessBindings(mock.Anything, "", ControllerNamespace).Return(&api.AccessBindingList{}, nil)
+		f.persister.EXPECT().GetRoleTemplate(mock.Anything, "devops", namespace).Return(newRoleTemplate("devops"), nil).Once()
+
+		// When
+		result, err := f.svc.ListGrantableRoles(context.Background(), namespace, groups, app, project)
+
+		// Then
+		assert.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, namespace, result[0].Binding.Namespace)
+	})
+	t.Run("will return roles with the same name from different namespaces", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		ab := newAccessBinding(namespace, "devops", "my-subject")
+		ab2 := newAccessBinding(ControllerNamespace, "devops", "my-subject")
+		rt2 := newRoleTemplate("devops")
+		rt2.Spec.Description = "global devops description"
+		f.persister.EXPECT().ListAccessBindings(mock.Anything, "", namespace).Return(&api.AccessBindingList{Items: []api.AccessBinding{*ab}}, nil)
+		f.persister.EXPECT().ListAccessBindings(mock.Anything, "", ControllerNamespace).Return(&api.AccessBindingList{Items: []api.AccessBinding{*ab2}}, nil)
+		f.persister.EXPECT().GetRoleTemplate(mock.Anything, "devops", namespace).Return(newRoleTemplate("devops"), nil).Once()
+		f.persister.EXPECT().GetRoleTemplate(mock.Anything, "devops", ControllerNamespace).Return(rt2, nil).Once()
+
+		// When
+		result, err := f.svc.ListGrantableRoles(context.Background(), namespace, groups, app, project)
+
+		// Then
+		assert.NoError(t, err)
+		require.Len(t, result, 2)
+		assert.Equal(t, "devops description", result[0].RoleTemplate.Spec.Description)
+		assert.Equal(t, "global devops description", result[1].RoleTemplate.Spec.Description)
+	})
+	t.Run("will render the role description for the application", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		ab := newAccessBinding(namespace, "devops", "my-subject")
+		rt := newRoleTemplate("devops")
+		rt.Spec.Description = "Write access to {{.application}} in {{.project}}"
+		renderApp := &unstructured.Unstructured{}
+		renderApp.SetName("some-app")
+		renderApp.SetNamespace(namespace)
+		renderProject := &unstructured.Unstructured{}
+		renderProject.SetName("some-project")
+		f.persister.EXPECT().ListAccessBindings(mock.Anything, "", namespace).Return(&api.AccessBindingList{Items: []api.AccessBinding{*ab}}, nil)
+		f.persister.EXPECT().ListAccessBindings(mock.Anything, "", ControllerNamespace).Return(&api.AccessBindingList{}, nil)
+		f.persister.EXPECT().GetRoleTemplate(mock.Anything, "devops", namespace).Return(rt, nil)
+
+		// When
+		result, err := f.svc.ListGrantableRoles(context.Background(), namespace, groups, renderApp, renderProject)
+
+		// Then
+		assert.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, "Write access to some-app in some-project", result[0].RoleTemplate.Spec.Description)
+		assert.Equal(t, "Write access to {{.application}} in {{.project}}", rt.Spec.Description)
+	})
+	t.Run("will skip roles if fails to retrieve the RoleTemplate", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		f.logger.EXPECT().Error(mock.Anything, mock.Anything).Once()
+		ab := newAccessBinding(namespace, "devops", "my-subject")
+		admin := newAccessBinding(namespace, "admin", "my-subject")
+		f.persister.EXPECT().ListAccessBindings(mock.Anything, "", namespace).Return(&api.AccessBindingList{Items: []api.AccessBinding{*ab, *admin}}, nil)
+		f.persister.EXPECT().ListAccessBindings(mock.Anything, "", ControllerNamespace).Return(&api.AccessBindingList{}, nil)
+		f.persister.EXPECT().GetRoleTemplate(mock.Anything, "devops", namespace).Return(nil, fmt.Errorf("some error"))
+		f.persister.EXPECT().GetRoleTemplate(mock.Anything, "admin", namespace).Return(newRoleTemplate("admin"), nil)
+
+		// When
+		result, err := f.svc.ListGrantableRoles(context.Background(), namespace, groups, app, project)
+
+		// Then
+		assert.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, "admin", result[0].Binding.Spec.RoleTemplateRef.Name)
+	})
+	t.Run("will return break-glass roles capped to the break-glass maximum duration", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		ab := newAccessBinding(namespace, "devops", "my-subject")
+		ab.Spec.BreakGlass = true
+		rt := newRoleTemplate("devops")
+		rt.Spec.DefaultDuration = &metav1.Duration{Duration: 4 * time.Hour}
+		rt.Spec.MaxDuration = &metav1.Duration{Duration: 8 * time.Hour}
+		rt.Spec.BreakGlass = &api.BreakGlassSpec{MaxDuration: metav1.Duration{Duration: time.Hour}}
+		f.persister.EXPECT().ListAccessBindings(mock.Anything, "", namespace).Return(&api.AccessBindingList{Items: []api.AccessBinding{*ab}}, nil)
+		f.persister.EXPECT().ListAccessBindings(mock.Anything, "", ControllerNamespace).Return(&api.AccessBindingList{}, nil)
+		f.persister.EXPECT().GetRoleTemplate(mock.Anything, "devops", namespace).Return(rt, nil)
+
+		// When
+		result, err := f.svc.ListGrantableRoles(context.Background(), namespace, groups, app, project)
+
+		// Then
+		assert.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.True(t, result[0].Binding.Spec.BreakGlass)
+		assert.Equal(t, time.Hour, result[0].DefaultDuration)
+		assert.Equal(t, time.Hour, result[0].MaxDuration)
+	})
+	t.Run("will skip roles without RoleTemplate", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		ab := newAccessBinding(namespace, "devops", "my-subject")
+		f.persister.EXPECT().ListAccessBindings(mock.Anything, "", namespace).Return(&api.AccessBindingList{Items: []api.AccessBinding{*ab}}, nil)
+		f.persister.EXPECT().ListAccessBindings(mock.Anything, "", ControllerNamespace).Return(&api.AccessBindingList{}, nil)
+		f.persister.EXPECT().GetRoleTemplate(mock.Anything, "devops", namespace).Return(nil, errors.NewNotFound(schema.GroupResource{}, "devops"))
+
+		// When
+		result, err := f.svc.ListGrantableRoles(context.Background(), namespace, groups, app, project)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Empty(t, result)
+	})
+	t.Run("will return error if fails to list bindings", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		f.persister.EXPECT().ListAccessBindings(mock.Anything, "", namespace).Return(nil, fmt.Errorf("some error"))
+
+		// When
+		result, err := f.svc.ListGrantableRoles(context.Background(), namespace, groups, app, project)
+
+		// Then
+		assert.ErrorContains(t, err, "some error")
+		assert.Nil(t, result)
+	})
+}
+
 func TestServiceGetApplication(t *testing.T) {
 	t.Run("will return the application when found", func(t *testing.T) {
 		// Given
@@ -575,6 +1291,322 @@ func TestServiceGetAppProject(t *testing.T) {
 	})
 }
 
+func TestServiceGetAccessRequest(t *testing.T) {
+	t.Run("will return the access request when found", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		ar := utils.NewAccessRequestRequested()
+		f.persister.EXPECT().GetAccessRequest(mock.Anything, ar.GetName(), ar.GetNamespace()).Return(ar, nil)
+
+		// When
+		result, err := f.svc.GetAccessRequest(context.Background(), ar.GetName(), ar.GetNamespace())
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, ar, result)
+	})
+	t.Run("will return nil if not found", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		f.persister.EXPECT().GetAccessRequest(mock.Anything, "my-name", "my-namespace").Return(nil, errors.NewNotFound(schema.GroupResource{}, "some-err"))
+
+		// When
+		result, err := f.svc.GetAccessRequest(context.Background(), "my-name", "my-namespace")
+
+		// Then
+		assert.NoError(t, err)
+		assert.Nil(t, result)
+	})
+	t.Run("will return error if k8s request fails", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		f.persister.EXPECT().GetAccessRequest(mock.Anything, "my-name", "my-namespace").Return(nil, fmt.Errorf("some internal error"))
+
+		// When
+		result, err := f.svc.GetAccessRequest(context.Background(), "my-name", "my-namespace")
+
+		// Then
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		assert.Contains(t, err.Error(), "some internal error")
+	})
+}
+
+func TestServiceUpdateAccessRequestApproval(t *testing.T) {
+	t.Run("will update the access request with the approval", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		ar := utils.NewAccessRequestRequested()
+		approval := &api.Approval{
+			Decision: api.ApprovedDecision,
+			Approver: "some-approver",
+		}
+		f.persister.EXPECT().UpdateAccessRequest(mock.Anything, mock.Anything).
+			RunAndReturn(func(_ context.Context, ar *api.AccessRequest) (*api.AccessRequest, error) {
+				return ar, nil
+			})
+
+		// When
+		result, err := f.svc.UpdateAccessRequestApproval(context.Background(), ar, approval)
+
+		// Then
+		assert.NoError(t, err)
+		require.NotNil(t, result)
+		assert.Equal(t, approval, result.Spec.Approval)
+		assert.Nil(t, ar.Spec.Approval)
+		assert.NotContains(t, result.GetAnnotations(), api.ApprovalSignatureAnnotation)
+	})
+	t.Run("will sign the approval if signing key is configured", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		signingKey := []byte("some-secret")
+		svc := backend.NewDefaultService(f.persister, f.logger, ControllerNamespace, AccessRequestDuration, signingKey)
+		ar := utils.NewAccessRequestRequested()
+		require.NoError(t, ar.Sign(signingKey))
+		approval := &api.Approval{
+			Decision: api.ApprovedDecision,
+			Approver: "some-approver",
+		}
+		f.persister.EXPECT().UpdateAccessRequest(mock.Anything, mock.Anything).
+			RunAndReturn(func(_ context.Context, ar *api.AccessRequest) (*api.AccessRequest, error) {
+				return ar, nil
+			})
+
+		// When
+		result, err := svc.UpdateAccessRequestApproval(context.Background(), ar, approval)
+
+		// Then
+		assert.NoError(t, err)
+		require.NotNil(t, result)
+		assert.NoError(t, result.VerifyApprovalSignature(signingKey))
+		assert.NotContains(t, ar.GetAnnotations(), api.ApprovalSignatureAnnotation)
+	})
+	t.Run("will return error if k8s request fails", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		ar := utils.NewAccessRequestRequested()
+		f.persister.EXPECT().UpdateAccessRequest(mock.Anything, mock.Anything).Return(nil, fmt.Errorf("some internal error"))
+
+		// When
+		result, err := f.svc.UpdateAccessRequestApproval(context.Background(), ar, &api.Approval{})
+
+		// Then
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		assert.Contains(t, err.Error(), "some internal error")
+	})
+}
+
+func TestServiceRevokeAccessRequest(t *testing.T) {
+	t.Run("will update the access request with the revocation", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		ar := utils.NewAccessRequestGranted()
+		revocation := &api.Revocation{
+			Revoker: "some-admin",
+			Reason:  "some-reason",
+		}
+		f.persister.EXPECT().UpdateAccessRequest(mock.Anything, mock.Anything).
+			RunAndReturn(func(_ context.Context, ar *api.AccessRequest) (*api.AccessRequest, error) {
+				return ar, nil
+			})
+
+		// When
+		result, err := f.svc.RevokeAccessRequest(context.Background(), ar, revocation)
+
+		// Then
+		assert.NoError(t, err)
+		require.NotNil(t, result)
+		assert.Equal(t, revocation, result.Spec.Revocation)
+		assert.Nil(t, ar.Spec.Revocation)
+	})
+	t.Run("will return error if k8s request fails", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		ar := utils.NewAccessRequestGranted()
+		f.persister.EXPECT().UpdateAccessRequest(mock.Anything, mock.Anything).Return(nil, fmt.Errorf("some internal error"))
+
+		// When
+		result, err := f.svc.RevokeAccessRequest(context.Background(), ar, &api.Revocation{})
+
+		// Then
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		assert.Contains(t, err.Error(), "some internal error")
+	})
+}
+
+func TestServiceExtendAccessRequest(t *testing.T) {
+	t.Run("will append the extension in the access request", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		ar := utils.NewAccessRequestGranted()
+		extension := &api.Extension{
+			Duration:  metav1.Duration{Duration: time.Minute},
+			Requester: "some-user",
+		}
+		f.persister.EXPECT().UpdateAccessRequest(mock.Anything, mock.Anything).
+			RunAndReturn(func(_ context.Context, ar *api.AccessRequest) (*api.AccessRequest, error) {
+				return ar, nil
+			})
+
+		// When
+		result, err := f.svc.ExtendAccessRequest(context.Background(), ar, extension)
+
+		// Then
+		assert.NoError(t, err)
+		require.NotNil(t, result)
+		require.Len(t, result.Spec.Extensions, 1)
+		assert.Equal(t, *extension, result.Spec.Extensions[0])
+		assert.Empty(t, ar.Spec.Extensions)
+		assert.NotContains(t, result.GetAnnotations(), api.ExtensionsSignatureAnnotation)
+	})
+	t.Run("will sign the extensions if signing key is configured", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		signingKey := []byte("some-secret")
+		svc := backend.NewDefaultService(f.persister, f.logger, ControllerNamespace, AccessRequestDuration, signingKey)
+		ar := utils.NewAccessRequestGranted()
+		ar.SetUID("some-uid")
+		require.NoError(t, ar.Sign(signingKey))
+		extension := &api.Extension{
+			Duration:  metav1.Duration{Duration: time.Minute},
+			Requester: "some-user",
+		}
+		f.persister.EXPECT().UpdateAccessRequest(mock.Anything, mock.Anything).
+			RunAndReturn(func(_ context.Context, ar *api.AccessRequest) (*api.AccessRequest, error) {
+				return ar, nil
+			})
+
+		// When
+		result, err := svc.ExtendAccessRequest(context.Background(), ar, extension)
+
+		// Then
+		assert.NoError(t, err)
+		require.NotNil(t, result)
+		assert.NoError(t, result.VerifyExtensionsSignature(signingKey))
+	})
+	t.Run("will return error if k8s request fails", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		ar := utils.NewAccessRequestGranted()
+		f.persister.EXPECT().UpdateAccessRequest(mock.Anything, mock.Anything).Return(nil, fmt.Errorf("some internal error"))
+
+		// When
+		result, err := f.svc.ExtendAccessRequest(context.Background(), ar, &api.Extension{})
+
+		// Then
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		assert.Contains(t, err.Error(), "some internal error")
+	})
+}
+
+func TestServiceReviewAccessRequest(t *testing.T) {
+	t.Run("will record the review and remove the pending review label", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		ar := utils.NewAccessRequestGranted()
+		ar.Spec.BreakGlass = true
+		ar.SetLabels(map[string]string{api.BreakGlassLabel: "true", api.PendingReviewLabel: "true"})
+		review := &api.Review{Reviewer: "some-auditor", Comment: "ok"}
+		f.persister.EXPECT().UpdateAccessRequest(mock.Anything, mock.Anything).
+			RunAndReturn(func(_ context.Context, ar *api.AccessRequest) (*api.AccessRequest, error) {
+				return ar, nil
+			})
+
+		// When
+		result, err := f.svc.ReviewAccessRequest(context.Background(), ar, review)
+
+		// Then
+		assert.NoError(t, err)
+		require.NotNil(t, result)
+		assert.Equal(t, review, result.Spec.Review)
+		assert.False(t, result.IsPendingReview())
+		assert.NotContains(t, result.GetLabels(), api.PendingReviewLabel)
+		assert.Equal(t, "true", result.GetLabels()[api.BreakGlassLabel])
+		assert.True(t, ar.IsPendingReview())
+		assert.NotContains(t, result.GetAnnotations(), api.ReviewSignatureAnnotation)
+	})
+	t.Run("will sign the review if signing key is configured", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		signingKey := []byte("some-secret")
+		svc := backend.NewDefaultService(f.persister, f.logger, ControllerNamespace, AccessRequestDuration, signingKey)
+		ar := utils.NewAccessRequestGranted()
+		ar.Spec.BreakGlass = true
+		require.NoError(t, ar.Sign(signingKey))
+		review := &api.Review{Reviewer: "some-auditor", Comment: "ok"}
+		f.persister.EXPECT().UpdateAccessRequest(mock.Anything, mock.Anything).
+			RunAndReturn(func(_ context.Context, ar *api.AccessRequest) (*api.AccessRequest, error) {
+				return ar, nil
+			})
+
+		// When
+		result, err := svc.ReviewAccessRequest(context.Background(), ar, review)
+
+		// Then
+		assert.NoError(t, err)
+		require.NotNil(t, result)
+		assert.NoError(t, result.VerifyReviewSignature(signingKey))
+	})
+	t.Run("will return error if k8s request fails", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		ar := utils.NewAccessRequestGranted()
+		f.persister.EXPECT().UpdateAccessRequest(mock.Anything, mock.Anything).Return(nil, fmt.Errorf("some internal error"))
+
+		// When
+		result, err := f.svc.ReviewAccessRequest(context.Background(), ar, &api.Review{})
+
+		// Then
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		assert.Contains(t, err.Error(), "some internal error")
+	})
+}
+
+func TestServiceGetRoleTemplate(t *testing.T) {
+	t.Run("will return the role template when found", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		rt := utils.NewRoleTemplate("my-name", "my-namespace", "some-role", []string{})
+		f.persister.EXPECT().GetRoleTemplate(mock.Anything, "my-name", "my-namespace").Return(rt, nil)
+
+		// When
+		result, err := f.svc.GetRoleTemplate(context.Background(), "my-name", "my-namespace")
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, rt, result)
+	})
+	t.Run("will return nil if not found", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		f.persister.EXPECT().GetRoleTemplate(mock.Anything, "my-name", "my-namespace").Return(nil, errors.NewNotFound(schema.GroupResource{}, "some-err"))
+
+		// When
+		result, err := f.svc.GetRoleTemplate(context.Background(), "my-name", "my-namespace")
+
+		// Then
+		assert.NoError(t, err)
+		assert.Nil(t, result)
+	})
+	t.Run("will return error if k8s request fails", func(t *testing.T) {
+		// Given
+		f := serviceSetup(t)
+		f.persister.EXPECT().GetRoleTemplate(mock.Anything, "my-name", "my-namespace").Return(nil, fmt.Errorf("some internal error"))
+
+		// When
+		result, err := f.svc.GetRoleTemplate(context.Background(), "my-name", "my-namespace")
+
+		// Then
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		assert.Contains(t, err.Error(), "some internal error")
+	})
+}
+
 func Test_defaultAccessRequestSort(t *testing.T) {
 
 	t.Run("equals on object equality", func(t *testing.T) {
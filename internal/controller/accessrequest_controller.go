@@ -21,10 +21,14 @@ import (
 	"fmt"
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields" // Required for Watching
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types" // Required for Watching
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder" // Required for Watching
@@ -40,14 +44,19 @@ import (
 	api "github.com/argoproj-labs/ephemeral-access/api/ephemeral-access/v1alpha1"
 	"github.com/argoproj-labs/ephemeral-access/internal/controller/config"
 	"github.com/argoproj-labs/ephemeral-access/pkg/log"
+	"github.com/argoproj-labs/ephemeral-access/pkg/plugin"
 )
 
 // AccessRequestReconciler reconciles a AccessRequest object
 type AccessRequestReconciler struct {
 	client.Client
-	Scheme  *runtime.Scheme
-	Service *Service
-	Config  config.ControllerConfigurer
+	Scheme   *runtime.Scheme
+	Service  *Service
+	Config   config.ControllerConfigurer
+	Recorder record.EventRecorder
+	// Archiver is optional and when provided, concluded AccessRequests are
+	// archived before being deleted.
+	Archiver Archiver
 }
 
 const (
@@ -60,6 +69,33 @@ const (
 	userField                  = ".spec.subject.username"
 	appField                   = ".spec.application.name"
 	appNamespaceField          = ".spec.application.namespace"
+	targetProjectField         = ".spec.project.name"
+
+	// EventReasonFinalizerError is used when the AccessRequest cleanup fails
+	EventReasonFinalizerError = "FinalizerError"
+	// EventReasonValidationError is used when the AccessRequest can not be validated
+	EventReasonValidationError = "ValidationError"
+	// EventReasonApplicationError is used when the Application can not be retrieved
+	EventReasonApplicationError = "ApplicationError"
+	// EventReasonProjectError is used when the AppProject targeted by a
+	// project-scoped AccessRequest can not be retrieved
+	EventReasonProjectError = "ProjectError"
+	// EventReasonRoleTemplateError is used when the RoleTemplate can not be retrieved or rendered
+	EventReasonRoleTemplateError = "RoleTemplateError"
+	// EventReasonRevocationError is used when the access revocation fails
+	EventReasonRevocationError = "RevocationError"
+	// EventReasonPermissionError is used when the access can not be granted or removed
+	EventReasonPermissionError = "PermissionError"
+	// EventReasonArchiveError is used when a concluded AccessRequest can not
+	// be archived before being deleted
+	EventReasonArchiveError = "ArchiveError"
+
+	// ConditionReasonReconciled is used in the Ready condition when the
+	// AccessRequest reconciliation concludes without errors
+	ConditionReasonReconciled = "Reconciled"
+	// ConditionReasonResolved is used when the Application or the
+	// RoleTemplate are resolved successfully
+	ConditionReasonResolved = "Resolved"
 )
 
 // +kubebuilder:rbac:groups=ephemeral-access.argoproj-labs.io,resources=accessrequests,verbs=get;list;watch;create;update;patch;delete
@@ -68,19 +104,24 @@ const (
 // +kubebuilder:rbac:groups=ephemeral-access.argoproj-labs.io,resources=roletemplates,verbs=get;list;watch
 // +kubebuilder:rbac:groups=ephemeral-access.argoproj-labs.io,resources=roletemplates/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=ephemeral-access.argoproj-labs.io,resources=roletemplates/finalizers,verbs=update
+// +kubebuilder:rbac:groups=ephemeral-access.argoproj-labs.io,resources=accessbindings,verbs=get;list;watch
 // +kubebuilder:rbac:groups=argoproj.io,resources=appprojects,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups=argoproj.io,resources=applications,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get
 
 // Reconcile is the main function that will be invoked on every change in
 // AccessRequests desired state. It will:
 //  1. Handle the accessrequest finalizer
 //  2. Validate the AccessRequest
-//  3. Verify if AccessRequest is expired
+//  3. Verify if AccessRequest is expired or revoked
 //     3.1 If so, remove the user from the elevated role
-//     3.2 Update the accessrequest status to "expired"
+//     3.2 Update the accessrequest status to "expired" or "revoked"
 //  4. Verify if user has the necessary access to be promoted
 //     4.1 If they don't, update the accessrequest status to "denied"
 //  5. Invoke preconfigured plugin to check if access can be granted
+//     5.1 If the plugin response is pending, requeue the AccessRequest
+//     5.2 If the plugin denies the access, update the accessrequest status to "denied"
 //  8. Assign user in the desired role in the AppProject
 //  9. Update the accessrequest status to "granted"
 func (r *AccessRequestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -97,10 +138,13 @@ func (r *AccessRequestReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{}, err
 	}
 
+	initialStatus := ar.Status.DeepCopy()
+
 	// check if the object is being deleted and properly handle it
 	logger.Debug("Handling finalizer")
 	deleted, err := r.handleFinalizer(ctx, ar)
 	if err != nil {
+		r.Recorder.Event(ar, corev1.EventTypeWarning, EventReasonFinalizerError, err.Error())
 		return ctrl.Result{}, fmt.Errorf("error handling finalizer: %w", err)
 	}
 	// stop the reconciliation as the object was deleted
@@ -112,7 +156,7 @@ func (r *AccessRequestReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	// stop if the reconciliation was previously concluded
 	if isConcluded(ar) {
 		logger.Debug(fmt.Sprintf("Reconciliation concluded as the AccessRequest is %s: skipping...", string(ar.Status.RequestState)))
-		return ctrl.Result{}, nil
+		return r.handleConcluded(ctx, ar)
 	}
 
 	logger.Debug("Validating AccessRequest")
@@ -120,56 +164,155 @@ func (r *AccessRequestReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	if err != nil {
 		if _, ok := err.(*AccessRequestConflictError); ok {
 			logger.Error(err, "AccessRequest conflict error")
-			ar.UpdateStatusHistory(api.InvalidStatus, err.Error())
+			details := err.Error()
+			ar.UpdateStatusHistory(api.InvalidStatus, details)
+			setReconciled(ar)
 			err = r.Status().Update(ctx, ar)
 			if err != nil {
 				return reconcile.Result{}, fmt.Errorf("error updating status to invalid: %s", err)
 			}
+			recordStatusEvent(r.Recorder, ar, api.InvalidStatus, details)
 			return ctrl.Result{}, nil
 		}
 		logger.Info(fmt.Sprintf("Validation error: %s", err))
+		r.Recorder.Event(ar, corev1.EventTypeWarning, EventReasonValidationError, err.Error())
+		r.updateFailedConditions(ctx, ar, EventReasonValidationError, err)
 		return ctrl.Result{}, fmt.Errorf("error validating the AccessRequest: %w", err)
 	}
 
-	application, err := r.getApplication(ctx, ar)
-	if err != nil {
-		// TODO send an event to explain why the access request is failing
-		return ctrl.Result{}, fmt.Errorf("error getting Argo CD Application: %w", err)
+	// the application is nil for project-scoped AccessRequests
+	var application *argocd.Application
+	var projectName string
+	if ar.IsProjectScoped() {
+		project, err := r.getProject(ctx, ar)
+		if err != nil {
+			r.Recorder.Eventf(ar, corev1.EventTypeWarning, EventReasonProjectError,
+				"Error getting Argo CD AppProject %s/%s: %s", ar.GetNamespace(), ar.Spec.Project.Name, err)
+			r.updateFailedConditions(ctx, ar, EventReasonProjectError, err, api.ConditionProjectResolved)
+			return ctrl.Result{}, fmt.Errorf("error getting Argo CD AppProject: %w", err)
+		}
+		projectName = project.GetName()
+		ar.SetCondition(api.ConditionProjectResolved, metav1.ConditionTrue, ConditionReasonResolved,
+			fmt.Sprintf("AppProject %s/%s resolved", project.GetNamespace(), project.GetName()))
+	} else {
+		application, err = r.getApplication(ctx, ar)
+		if err != nil {
+			r.Recorder.Eventf(ar, corev1.EventTypeWarning, EventReasonApplicationError,
+				"Error getting Argo CD Application %s/%s: %s", ar.Spec.Application.Namespace, ar.Spec.Application.Name, err)
+			r.updateFailedConditions(ctx, ar, EventReasonApplicationError, err, api.ConditionApplicationResolved)
+			return ctrl.Result{}, fmt.Errorf("error getting Argo CD Application: %w", err)
+		}
+		projectName = application.Spec.Project
+		ar.SetCondition(api.ConditionApplicationResolved, metav1.ConditionTrue, ConditionReasonResolved,
+			fmt.Sprintf("Application %s/%s resolved", application.GetNamespace(), application.GetName()))
 	}
 
 	roleTemplate, err := r.getRoleTemplate(ctx, ar)
 	if err != nil {
-		// TODO send an event to explain why the access request is failing
+		r.Recorder.Eventf(ar, corev1.EventTypeWarning, EventReasonRoleTemplateError,
+			"Error getting RoleTemplate %s/%s: %s", ar.Spec.Role.TemplateRef.Namespace, ar.Spec.Role.TemplateRef.Name, err)
+		r.updateFailedConditions(ctx, ar, EventReasonRoleTemplateError, err, api.ConditionRoleTemplateResolved)
 		return ctrl.Result{}, fmt.Errorf("error getting RoleTemplate %s/%s: %w", ar.Spec.Role.TemplateRef.Namespace, ar.Spec.Role.TemplateRef.Name, err)
 	}
 
-	renderedRt, err := roleTemplate.Render(application.Spec.Project, application.GetName(), application.GetNamespace())
+	var renderedRt *api.RoleTemplate
+	if ar.IsProjectScoped() {
+		renderedRt, err = roleTemplate.RenderProject(projectName)
+	} else {
+		renderedRt, err = roleTemplate.Render(projectName, application.GetName(), application.GetNamespace())
+	}
 	if err != nil {
+		r.Recorder.Eventf(ar, corev1.EventTypeWarning, EventReasonRoleTemplateError, "Error rendering RoleTemplate: %s", err)
+		r.updateFailedConditions(ctx, ar, EventReasonRoleTemplateError, err, api.ConditionRoleTemplateResolved)
 		return ctrl.Result{}, fmt.Errorf("roleTemplate error: %w", err)
 	}
+	ar.SetCondition(api.ConditionRoleTemplateResolved, metav1.ConditionTrue, ConditionReasonResolved,
+		fmt.Sprintf("RoleTemplate %s/%s resolved", roleTemplate.GetNamespace(), roleTemplate.GetName()))
 
 	// initialize the status if not done yet
 	if ar.Status.RequestState == "" {
 		logger.Debug("Initializing status")
 		ar.UpdateStatusHistory(api.RequestedStatus, "")
-		ar.Status.TargetProject = application.Spec.Project
-		ar.Status.RoleName = renderedRt.AppProjectRoleName(application.GetName(), application.GetNamespace())
+		ar.Status.TargetProject = projectName
+		ar.Status.RoleName = appProjectRoleName(ar, renderedRt)
 		ar.Status.RoleTemplateHash = RoleTemplateHash(renderedRt)
-		r.Status().Update(ctx, ar)
+		err = r.Status().Update(ctx, ar)
+		if err == nil {
+			recordStatusEvent(r.Recorder, ar, api.RequestedStatus, "")
+		}
+	}
+
+	if ar.IsRevoking() {
+		logger.Debug("Handling revocation")
+		status, err := r.Service.HandleRevocation(ctx, ar, application, renderedRt)
+		if err != nil {
+			logger.Error(err, "HandleRevocation error")
+			r.Recorder.Event(ar, corev1.EventTypeWarning, EventReasonRevocationError, err.Error())
+			r.updateFailedConditions(ctx, ar, EventReasonRevocationError, err)
+			return ctrl.Result{}, fmt.Errorf("error handling revocation: %w", err)
+		}
+		err = r.updateReconciledConditions(ctx, ar, initialStatus)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("error updating conditions: %w", err)
+		}
+		result := buildResult(status, ar, r.Config.ControllerRequeueInterval())
+		logger.Info("Reconciliation concluded", "status", status, "result", result)
+		return result, nil
 	}
 
 	logger.Debug("Handling permission")
 	status, err := r.Service.HandlePermission(ctx, ar, application, renderedRt)
 	if err != nil {
 		logger.Error(err, "HandlePermission error")
+		r.Recorder.Event(ar, corev1.EventTypeWarning, EventReasonPermissionError, err.Error())
+		r.updateFailedConditions(ctx, ar, EventReasonPermissionError, err)
 		return ctrl.Result{}, fmt.Errorf("error handling permission: %w", err)
 	}
+	err = r.updateReconciledConditions(ctx, ar, initialStatus)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("error updating conditions: %w", err)
+	}
 
 	result := buildResult(status, ar, r.Config.ControllerRequeueInterval())
 	logger.Info("Reconciliation concluded", "status", status, "result", result)
 	return result, nil
 }
 
+// setReconciled will mark the given ar as Ready and record the generation
+// observed by the controller.
+func setReconciled(ar *api.AccessRequest) {
+	ar.Status.ObservedGeneration = ar.GetGeneration()
+	ar.SetCondition(api.ConditionReady, metav1.ConditionTrue, ConditionReasonReconciled, "AccessRequest reconciled successfully")
+}
+
+// updateReconciledConditions will mark the given ar as Ready and update its
+// status if the conditions or the observed generation differ from the
+// given initialStatus.
+func (r *AccessRequestReconciler) updateReconciledConditions(ctx context.Context, ar *api.AccessRequest, initialStatus *api.AccessRequestStatus) error {
+	setReconciled(ar)
+	if ar.Status.ObservedGeneration == initialStatus.ObservedGeneration &&
+		equality.Semantic.DeepEqual(ar.Status.Conditions, initialStatus.Conditions) {
+		return nil
+	}
+	return r.Status().Update(ctx, ar)
+}
+
+// updateFailedConditions will set the given conditionTypes and the Ready
+// condition as false in the given ar using the given reason and err as
+// message. Failing to update the status is only logged as the
+// reconciliation is already returning the given err.
+func (r *AccessRequestReconciler) updateFailedConditions(ctx context.Context, ar *api.AccessRequest, reason string, err error, conditionTypes ...string) {
+	ar.Status.ObservedGeneration = ar.GetGeneration()
+	for _, conditionType := range conditionTypes {
+		ar.SetCondition(conditionType, metav1.ConditionFalse, reason, err.Error())
+	}
+	ar.SetCondition(api.ConditionReady, metav1.ConditionFalse, reason, err.Error())
+	updateErr := r.Status().Update(ctx, ar)
+	if updateErr != nil {
+		log.FromContext(ctx).Error(updateErr, "Error updating AccessRequest conditions")
+	}
+}
+
 type AccessRequestConflictError struct {
 	message string
 }
@@ -185,15 +328,22 @@ func NewAccessRequestConflictError(msg string) *AccessRequestConflictError {
 }
 
 // Validate will verify if there are existing AccessRequests for the same
-// user/app/role already in progress.
+// subject principal (user or group)/app/role already in progress and if new
+// AccessRequests are allowed by the RoleTemplate quotas.
 func (r *AccessRequestReconciler) Validate(ctx context.Context, ar *api.AccessRequest) error {
-	arList, err := r.findAccessRequestsByUserAndApp(ctx,
-		ar.GetNamespace(),
-		ar.Spec.Subject.Username,
-		ar.Spec.Application.Name,
-		ar.Spec.Application.Namespace)
+	var arList *api.AccessRequestList
+	var err error
+	if ar.IsProjectScoped() {
+		arList, err = r.findAccessRequestsByUserAndProject(ctx, ar.GetNamespace(), ar.Spec.Subject.Principal(), ar.Spec.Project.Name)
+	} else {
+		arList, err = r.findAccessRequestsByUserAndApp(ctx,
+			ar.GetNamespace(),
+			ar.Spec.Subject.Principal(),
+			ar.Spec.Application.Name,
+			ar.Spec.Application.Namespace)
+	}
 	if err != nil {
-		return fmt.Errorf("error finding AccessRequests by user and app: %w", err)
+		return fmt.Errorf("error finding AccessRequests by user and target: %w", err)
 	}
 	for _, arResp := range arList.Items {
 		// skip if it is the same AccessRequest
@@ -209,7 +359,8 @@ func (r *AccessRequestReconciler) Validate(ctx context.Context, ar *api.AccessRe
 		// if the existing request is pending or granted, then the new request is
 		// a duplicate and must be rejected
 		if arResp.Status.RequestState == api.GrantedStatus ||
-			arResp.Status.RequestState == api.RequestedStatus {
+			arResp.Status.RequestState == api.RequestedStatus ||
+			arResp.Status.RequestState == api.ScheduledStatus {
 			return NewAccessRequestConflictError(fmt.Sprintf("found existing AccessRequest (%s/%s) in %s state", arResp.GetNamespace(), arResp.GetName(), string(arResp.Status.RequestState)))
 		}
 		// if the existing request reconciliation isn't initialized yet, then we
@@ -219,21 +370,107 @@ func (r *AccessRequestReconciler) Validate(ctx context.Context, ar *api.AccessRe
 			return NewAccessRequestConflictError(fmt.Sprintf("found older AccessRequest (%s/%s) in progress", arResp.GetNamespace(), arResp.GetName()))
 		}
 	}
+	// quotas are only verified before the reconciliation is initialized
+	if ar.Status.RequestState == "" {
+		return r.validateQuotas(ctx, ar)
+	}
 	return nil
 }
 
 // isConcluded will check the status of the given AccessRequest
 // to determine if it is concluded. Concluded AccessRequest means
-// it is in Denied, Expired or Invalid status.
+// it is in Denied, Expired, Invalid or Revoked status.
 func isConcluded(ar *api.AccessRequest) bool {
 	switch ar.Status.RequestState {
-	case api.DeniedStatus, api.ExpiredStatus, api.InvalidStatus:
+	case api.DeniedStatus, api.ExpiredStatus, api.InvalidStatus, api.RevokedStatus:
 		return true
 	default:
 		return false
 	}
 }
 
+// handleConcluded will delete the given concluded ar once the configured
+// TTL has elapsed since its conclusion. If an Archiver is configured, the ar
+// is archived first and is only deleted if archiving succeeds. The returned
+// result will requeue the ar when its TTL elapses. Break-glass AccessRequests
+// that were granted are retained until they are reviewed. If signatures are
+// required, only reviews recorded by the backend are accounted for. The ar
+// is also retained while the RoleTemplate quotas account for it.
+func (r *AccessRequestReconciler) handleConcluded(ctx context.Context, ar *api.AccessRequest) (ctrl.Result, error) {
+	ttl := r.Config.ControllerConcludedTTL()
+	if ttl <= 0 || !ar.ObjectMeta.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+	if ar.GrantedAt() != nil && isPendingReview(r.Config, ar) {
+		log.FromContext(ctx).Debug("Retaining concluded AccessRequest pending review")
+		return ctrl.Result{}, nil
+	}
+	deleteAt := concludedAt(ar).Add(ttl)
+	retainUntil, err := r.quotaRetention(ctx, ar)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if retainUntil.After(deleteAt) {
+		deleteAt = retainUntil
+	}
+	remaining := time.Until(deleteAt)
+	if remaining > 0 {
+		return ctrl.Result{Requeue: true, RequeueAfter: remaining}, nil
+	}
+
+	logger := log.FromContext(ctx)
+	if r.Archiver != nil {
+		logger.Debug("Archiving concluded AccessRequest")
+		err := r.Archiver.Archive(ctx, ar)
+		if err != nil {
+			archiveErrorsTotal.Inc()
+			r.Recorder.Event(ar, corev1.EventTypeWarning, EventReasonArchiveError, err.Error())
+			return ctrl.Result{}, fmt.Errorf("error archiving AccessRequest: %w", err)
+		}
+	}
+	uid := ar.GetUID()
+	resourceVersion := ar.GetResourceVersion()
+	err = r.Delete(ctx, ar, client.Preconditions{UID: &uid, ResourceVersion: &resourceVersion})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("error deleting concluded AccessRequest: %w", err)
+	}
+	concludedDeletedTotal.WithLabelValues(string(ar.Status.RequestState)).Inc()
+	logger.Info("Concluded AccessRequest deleted", "status", ar.Status.RequestState, "ttl", ttl.String())
+	return ctrl.Result{}, nil
+}
+
+// quotaRetention returns the time until the given ar is accounted for by the
+// quotas of its RoleTemplate. Returns the zero time if the RoleTemplate
+// doesn't exist or doesn't define quotas.
+func (r *AccessRequestReconciler) quotaRetention(ctx context.Context, ar *api.AccessRequest) (time.Time, error) {
+	rt, err := r.getRoleTemplate(ctx, ar)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, fmt.Errorf("error getting RoleTemplate %s/%s: %w", ar.Spec.Role.TemplateRef.Namespace, ar.Spec.Role.TemplateRef.Name, err)
+	}
+	if rt.Spec.Quotas == nil {
+		return time.Time{}, nil
+	}
+	return rt.Spec.Quotas.RetainUntil(ar), nil
+}
+
+// concludedAt returns the time the given ar transitioned to its current
+// status. It will fallback to the creation timestamp if the history doesn't
+// have a matching entry.
+func concludedAt(ar *api.AccessRequest) time.Time {
+	for i := len(ar.Status.History) - 1; i >= 0; i-- {
+		if ar.Status.History[i].RequestState == ar.Status.RequestState {
+			return ar.Status.History[i].TransitionTime.Time
+		}
+	}
+	return ar.GetCreationTimestamp().Time
+}
+
 // buildResult will verify the given status and determine when this access
 // request should be requeued.
 func buildResult(status api.Status, ar *api.AccessRequest, requeueInterval time.Duration) ctrl.Result {
@@ -242,9 +479,17 @@ func buildResult(status api.Status, ar *api.AccessRequest, requeueInterval time.
 	case api.RequestedStatus:
 		result.Requeue = true
 		result.RequeueAfter = requeueInterval
+	case api.ScheduledStatus:
+		result.Requeue = true
+		result.RequeueAfter = time.Until(ar.Spec.StartsAt.Time)
 	case api.GrantedStatus:
 		result.Requeue = true
 		result.RequeueAfter = ar.Status.ExpiresAt.Sub(time.Now())
+		// the access is expired or revoked but the plugin didn't
+		// conclude revoking it yet
+		if ar.IsExpiring() || ar.IsRevoking() {
+			result.RequeueAfter = requeueInterval
+		}
 	}
 	return result
 }
@@ -269,6 +514,22 @@ func (r *AccessRequestReconciler) getApplication(ctx context.Context, ar *api.Ac
 	return application, nil
 }
 
+// getProject will retrieve the AppProject targeted by the given
+// project-scoped ar. The AppProject must live in the same namespace as the
+// AccessRequest.
+func (r *AccessRequestReconciler) getProject(ctx context.Context, ar *api.AccessRequest) (*argocd.AppProject, error) {
+	project := &argocd.AppProject{}
+	objKey := client.ObjectKey{
+		Namespace: ar.GetNamespace(),
+		Name:      ar.Spec.Project.Name,
+	}
+	err := r.Get(ctx, objKey, project)
+	if err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
 func (r *AccessRequestReconciler) getRoleTemplate(ctx context.Context, ar *api.AccessRequest) (*api.RoleTemplate, error) {
 	roleTemplate := &api.RoleTemplate{}
 	objKey := client.ObjectKey{
@@ -312,9 +573,10 @@ func (r *AccessRequestReconciler) handleFinalizer(ctx context.Context, ar *api.A
 
 	// The object is being deleted
 	if controllerutil.ContainsFinalizer(ar, AccessRequestFinalizerName) {
-		// if the access request is not expired yet then
+		// if the access request is not expired or revoked yet then
 		// execute the cleanup procedure before removing the finalizer
-		if ar.Status.RequestState != api.ExpiredStatus {
+		if ar.Status.RequestState != api.ExpiredStatus &&
+			ar.Status.RequestState != api.RevokedStatus {
 			// this is a best effort to update policies that eventually changed
 			// in the project. Errors are ignored as it is more important to
 			// remove the user from the role.
@@ -326,6 +588,23 @@ func (r *AccessRequestReconciler) handleFinalizer(ctx context.Context, ar *api.A
 			}
 		}
 
+		// the plugin must be notified if the access was granted
+		if ar.Status.RequestState == api.GrantedStatus {
+			// the application is only informational at this point and may
+			// not exist anymore. It is always nil for project-scoped requests.
+			var app *argocd.Application
+			if !ar.IsProjectScoped() {
+				app, _ = r.getApplication(ctx, ar)
+			}
+			resp, err := r.Service.PluginRevokeAccess(ctx, ar, app)
+			if err != nil {
+				return false, fmt.Errorf("error revoking access: %w", err)
+			}
+			if resp.Status == plugin.RevokePending {
+				return false, fmt.Errorf("plugin revoke access pending: %s", resp.Message)
+			}
+		}
+
 		// remove our finalizer from the list and update it.
 		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
 			err := r.Get(ctx, client.ObjectKeyFromObject(ar), ar)
@@ -407,6 +686,29 @@ func (r *AccessRequestReconciler) findAccessRequestsByUserAndApp(ctx context.Con
 	return arList, nil
 }
 
+// findAccessRequestsByUserAndProject will list all project-scoped
+// AccessRequests in the given namespace filtering by the given username and
+// projName.
+func (r *AccessRequestReconciler) findAccessRequestsByUserAndProject(ctx context.Context, namespace, username, projName string) (*api.AccessRequestList, error) {
+	arList := &api.AccessRequestList{}
+	selector := fields.SelectorFromSet(
+		fields.Set{
+			userField:          username,
+			targetProjectField: projName,
+		})
+
+	listOps := &client.ListOptions{
+		FieldSelector: selector,
+		Namespace:     namespace,
+	}
+
+	err := r.List(ctx, arList, listOps)
+	if err != nil {
+		return nil, fmt.Errorf("List error: %w", err)
+	}
+	return arList, nil
+}
+
 // callReconcileForProject will retrieve all AccessRequest resources referencing
 // the given project and build a list of reconcile requests to be sent to the
 // controller. Only non-concluded AccessRequests will be added to the reconciliation
@@ -494,17 +796,19 @@ func createRoleTemplateIndex(mgr ctrl.Manager) error {
 }
 
 // createRoleTemplateIndex will create an AccessRequest index by the following fields:
-// - .spec.subject.username
+// - .spec.subject.username (or .spec.subject.group if provided)
 // - .spec.application.name
 // - .spec.application.namespace
+// - .spec.project.name
 func createUserAppIndex(mgr ctrl.Manager) error {
 	err := mgr.GetFieldIndexer().
 		IndexField(context.Background(), &api.AccessRequest{}, userField, func(rawObj client.Object) []string {
 			ar := rawObj.(*api.AccessRequest)
-			if ar.Spec.Subject.Username == "" {
+			principal := ar.Spec.Subject.Principal()
+			if principal == "" {
 				return nil
 			}
-			return []string{ar.Spec.Subject.Username}
+			return []string{principal}
 		})
 	if err != nil {
 		return fmt.Errorf("error creating username field index: %w", err)
@@ -531,6 +835,17 @@ func createUserAppIndex(mgr ctrl.Manager) error {
 	if err != nil {
 		return fmt.Errorf("error creating application namespace field index: %w", err)
 	}
+	err = mgr.GetFieldIndexer().
+		IndexField(context.Background(), &api.AccessRequest{}, targetProjectField, func(rawObj client.Object) []string {
+			ar := rawObj.(*api.AccessRequest)
+			if !ar.IsProjectScoped() || ar.Spec.Project.Name == "" {
+				return nil
+			}
+			return []string{ar.Spec.Project.Name}
+		})
+	if err != nil {
+		return fmt.Errorf("error creating target project field index: %w", err)
+	}
 	return nil
 }
 
@@ -548,6 +863,10 @@ func (r *AccessRequestReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	if err != nil {
 		return fmt.Errorf("userapp index error: %w", err)
 	}
+	err = registerGrantedCollector(mgr.GetClient())
+	if err != nil {
+		return fmt.Errorf("metrics registration error: %w", err)
+	}
 
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&api.AccessRequest{}).
@@ -26,6 +26,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -70,6 +71,8 @@ var _ = Describe("AccessRequest Controller", func() {
 		policies []string
 	}
 
+	const subjectGroup = "some-group"
+
 	setup := func(r resources) *fixture {
 		By("Creating the namespace")
 		ns := utils.NewNamespace(r.namespace)
@@ -122,6 +125,16 @@ var _ = Describe("AccessRequest Controller", func() {
 		rt := utils.NewRoleTemplate(r.roleTemplateName, r.roleTemplateNamespace, r.roleName, r.policies)
 		By("Instantiate the AccessRequest initial state")
 		ar := utils.NewAccessRequest(r.arName, r.namespace, r.appName, r.namespace, r.roleTemplateName, r.roleTemplateNamespace, r.subject)
+		ar.Spec.Subject.Groups = []string{subjectGroup}
+
+		By("Creating the AccessBinding authorizing the AccessRequest subject")
+		binding := utils.NewAccessBinding(r.roleTemplateName, r.roleTemplateNamespace, r.roleTemplateName, []string{subjectGroup})
+		err = k8sClient.Create(ctx, binding)
+		if err != nil {
+			statusErr, ok := err.(*errors.StatusError)
+			Expect(ok).To(BeTrue())
+			Expect(statusErr.ErrStatus.Code).NotTo(Equal(409))
+		}
 
 		return &fixture{
 			namespace:      ns,
@@ -183,6 +196,15 @@ var _ = Describe("AccessRequest Controller", func() {
 				err := k8sClient.Create(ctx, f.roletemplate)
 				Expect(err).NotTo(HaveOccurred())
 			})
+			It("will validate the roletemplate and update its status", func() {
+				rt := &api.RoleTemplate{}
+				Eventually(func() bool {
+					err := k8sClient.Get(ctx, client.ObjectKeyFromObject(f.roletemplate), rt)
+					Expect(err).NotTo(HaveOccurred())
+					return rt.Status.Synced
+				}, timeout, interval).Should(BeTrue())
+				Expect(rt.Status.SyncHash).NotTo(BeEmpty())
+			})
 			It("will apply the access request resource in k8s", func() {
 				f.accessrequests[0].Spec.Duration = metav1.Duration{Duration: time.Second * 5}
 				err := k8sClient.Create(ctx, f.accessrequests[0])
@@ -209,6 +231,10 @@ var _ = Describe("AccessRequest Controller", func() {
 				Expect(ar.Status.History).Should(HaveLen(2))
 				Expect(ar.Status.History[0].RequestState).To(Equal(api.RequestedStatus))
 				Expect(ar.Status.History[1].RequestState).To(Equal(api.GrantedStatus))
+				Expect(meta.IsStatusConditionTrue(ar.Status.Conditions, api.ConditionGranted)).To(BeTrue())
+				Expect(meta.IsStatusConditionTrue(ar.Status.Conditions, api.ConditionProjectPatched)).To(BeTrue())
+				Expect(meta.IsStatusConditionTrue(ar.Status.Conditions, api.ConditionApplicationResolved)).To(BeTrue())
+				Expect(meta.IsStatusConditionTrue(ar.Status.Conditions, api.ConditionRoleTemplateResolved)).To(BeTrue())
 			})
 			It("will validate Argo CD AppProject", func() {
 				key := client.ObjectKey{
@@ -247,6 +273,9 @@ var _ = Describe("AccessRequest Controller", func() {
 				Expect(ar.Status.History[0].RequestState).To(Equal(api.RequestedStatus))
 				Expect(ar.Status.History[1].RequestState).To(Equal(api.GrantedStatus))
 				Expect(ar.Status.History[2].RequestState).To(Equal(api.ExpiredStatus))
+				Expect(meta.IsStatusConditionFalse(ar.Status.Conditions, api.ConditionGranted)).To(BeTrue())
+				Expect(meta.IsStatusConditionTrue(ar.Status.Conditions, api.ConditionReady)).To(BeTrue())
+				Expect(ar.Status.ObservedGeneration).To(Equal(ar.GetGeneration()))
 			})
 			It("will validate if subject is removed from Argo CD role", func() {
 				key := client.ObjectKey{
@@ -609,6 +638,11 @@ var _ = Describe("AccessRequest Controller", func() {
 				rtRace := utils.NewRoleTemplate("racerole", roleTemplateNamespace, roleName, policies)
 				err = k8sClient.Create(ctx, rtRace)
 				Expect(err).NotTo(HaveOccurred())
+				for _, name := range []string{"anotherrole", "racerole"} {
+					binding := utils.NewAccessBinding(name, roleTemplateNamespace, name, []string{subjectGroup})
+					err = k8sClient.Create(ctx, binding)
+					Expect(err).NotTo(HaveOccurred())
+				}
 			})
 			It("will apply the AccessRequests resources in k8s", func() {
 				f.accessrequests[0].Spec.Duration = metav1.Duration{Duration: time.Minute}
@@ -631,6 +665,7 @@ var _ = Describe("AccessRequest Controller", func() {
 		When("creating conflicting AccessRequest", func() {
 			It("will create conflicting AccessRequest", func() {
 				conflictAR := utils.NewAccessRequest("conflict", namespace, appName, namespace, roleTemplateName, roleTemplateNamespace, subject01)
+				conflictAR.Spec.Subject.Groups = []string{subjectGroup}
 				err := k8sClient.Create(ctx, conflictAR)
 				Expect(err).NotTo(HaveOccurred())
 			})
@@ -653,6 +688,7 @@ var _ = Describe("AccessRequest Controller", func() {
 		When("creating an AccessRequest for the same user/app but different role", func() {
 			It("will create the AccessRequest successfully", func() {
 				anotherroleAR := utils.NewAccessRequest("anotherrole", namespace, appName, namespace, "anotherrole", roleTemplateNamespace, subject01)
+				anotherroleAR.Spec.Subject.Groups = []string{subjectGroup}
 				anotherroleAR.Spec.Duration = metav1.Duration{Duration: time.Minute}
 				err := k8sClient.Create(ctx, anotherroleAR)
 				Expect(err).NotTo(HaveOccurred())
@@ -677,8 +713,10 @@ var _ = Describe("AccessRequest Controller", func() {
 		When("creating two AccessRequests at the same time", func() {
 			It("will create them successfully", func() {
 				race1AR := utils.NewAccessRequest("race1", namespace, appName, namespace, "racerole", roleTemplateNamespace, subject01)
+				race1AR.Spec.Subject.Groups = []string{subjectGroup}
 				race1AR.Spec.Duration = metav1.Duration{Duration: time.Minute}
 				race2AR := utils.NewAccessRequest("race2", namespace, appName, namespace, "racerole", roleTemplateNamespace, subject01)
+				race2AR.Spec.Subject.Groups = []string{subjectGroup}
 				race2AR.Spec.Duration = metav1.Duration{Duration: time.Minute}
 				go func() {
 					err := k8sClient.Create(ctx, race1AR)
@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	api "github.com/argoproj-labs/ephemeral-access/api/ephemeral-access/v1alpha1"
+)
+
+// archiveHTTPTimeout defines how long the HTTP archiver will wait for the
+// archive endpoint to respond.
+const archiveHTTPTimeout = 30 * time.Second
+
+// Archiver defines the sink used to persist concluded AccessRequests before
+// they are deleted from the cluster.
+type Archiver interface {
+	// Archive persists the given ar. Implementations must be idempotent as
+	// the same AccessRequest may be archived more than once if the deletion
+	// fails.
+	Archive(ctx context.Context, ar *api.AccessRequest) error
+}
+
+// ArchiveRecord is the JSON document written by the archivers. The
+// AccessRequest includes its full status history.
+type ArchiveRecord struct {
+	ArchivedAt    metav1.Time        `json:"archivedAt"`
+	AccessRequest *api.AccessRequest `json:"accessRequest"`
+}
+
+// NewArchiver will return the Archiver matching the given configuration. If
+// dir is provided, records are written as files in the local directory. If
+// url is provided, records are sent with HTTP POST requests. Returns nil if
+// no sink is configured.
+func NewArchiver(dir, url string) (Archiver, error) {
+	switch {
+	case dir != "" && url != "":
+		return nil, fmt.Errorf("only one archive sink can be configured: dir=%q url=%q", dir, url)
+	case dir != "":
+		return &DirArchiver{Dir: dir}, nil
+	case url != "":
+		return &HTTPArchiver{
+			URL:    url,
+			Client: &http.Client{Timeout: archiveHTTPTimeout},
+		}, nil
+	}
+	return nil, nil
+}
+
+// DirArchiver writes one JSON file per AccessRequest in a local directory.
+type DirArchiver struct {
+	Dir string
+}
+
+// Archive implements Archiver. The file is named after the AccessRequest
+// namespace, name and UID so archiving the same object twice overrides the
+// previous record.
+func (a *DirArchiver) Archive(ctx context.Context, ar *api.AccessRequest) error {
+	data, err := newArchiveRecord(ar)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(a.Dir, 0o750)
+	if err != nil {
+		return fmt.Errorf("error creating archive directory: %w", err)
+	}
+	fileName := fmt.Sprintf("%s_%s_%s.json", ar.GetNamespace(), ar.GetName(), ar.GetUID())
+	path := filepath.Join(a.Dir, fileName)
+	// write to a temporary file first so a partial record is never left
+	// behind if the controller is interrupted
+	tmp := path + ".tmp"
+	err = os.WriteFile(tmp, data, 0o640)
+	if err != nil {
+		return fmt.Errorf("error writing archive file: %w", err)
+	}
+	err = os.Rename(tmp, path)
+	if err != nil {
+		return fmt.Errorf("error renaming archive file: %w", err)
+	}
+	return nil
+}
+
+// HTTPArchiver sends the AccessRequest records to a generic HTTP endpoint.
+type HTTPArchiver struct {
+	URL    string
+	Client *http.Client
+}
+
+// Archive implements Archiver. The record is sent in the body of a POST
+// request and any non 2xx response is considered a failure.
+func (a *HTTPArchiver) Archive(ctx context.Context, ar *api.AccessRequest) error {
+	data, err := newArchiveRecord(ar)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.URL, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("error creating archive request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := a.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending archive request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("archive endpoint returned status %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+func newArchiveRecord(ar *api.AccessRequest) ([]byte, error) {
+	obj := ar.DeepCopy()
+	obj.SetGroupVersionKind(api.GroupVersion.WithKind("AccessRequest"))
+	obj.SetManagedFields(nil)
+	record := ArchiveRecord{
+		ArchivedAt:    metav1.Now(),
+		AccessRequest: obj,
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling archive record: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,316 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	api "github.com/argoproj-labs/ephemeral-access/api/ephemeral-access/v1alpha1"
+	"github.com/argoproj-labs/ephemeral-access/test/mocks"
+	"github.com/argoproj-labs/ephemeral-access/test/utils"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type archiverFunc func(ctx context.Context, ar *api.AccessRequest) error
+
+func (f archiverFunc) Archive(ctx context.Context, ar *api.AccessRequest) error {
+	return f(ctx, ar)
+}
+
+func newConcludedAccessRequest(concludedAt time.Time) *api.AccessRequest {
+	ar := utils.NewAccessRequestExpired()
+	ar.SetUID(types.UID("some-uid"))
+	ar.Status.History = []api.AccessRequestHistory{
+		{TransitionTime: metav1.NewTime(concludedAt.Add(-time.Hour)), RequestState: api.GrantedStatus},
+		{TransitionTime: metav1.NewTime(concludedAt), RequestState: api.ExpiredStatus},
+	}
+	return ar
+}
+
+func TestNewArchiver(t *testing.T) {
+	t.Run("will return nil if no sink is configured", func(t *testing.T) {
+		archiver, err := NewArchiver("", "")
+		assert.NoError(t, err)
+		assert.Nil(t, archiver)
+	})
+	t.Run("will return error if both sinks are configured", func(t *testing.T) {
+		archiver, err := NewArchiver("/tmp", "http://archive")
+		assert.Error(t, err)
+		assert.Nil(t, archiver)
+	})
+	t.Run("will return the archiver for the configured sink", func(t *testing.T) {
+		archiver, err := NewArchiver("/tmp", "")
+		require.NoError(t, err)
+		assert.IsType(t, &DirArchiver{}, archiver)
+		archiver, err = NewArchiver("", "http://archive")
+		require.NoError(t, err)
+		assert.IsType(t, &HTTPArchiver{}, archiver)
+	})
+}
+
+func TestDirArchiver(t *testing.T) {
+	t.Run("will write the AccessRequest with its history as JSON", func(t *testing.T) {
+		// Given
+		dir := filepath.Join(t.TempDir(), "archive")
+		archiver := &DirArchiver{Dir: dir}
+		ar := newConcludedAccessRequest(time.Now())
+
+		// When
+		err := archiver.Archive(context.Background(), ar)
+
+		// Then
+		require.NoError(t, err)
+		data, err := os.ReadFile(filepath.Join(dir, "access-request-namespace_access-request-name_some-uid.json"))
+		require.NoError(t, err)
+		record := &ArchiveRecord{}
+		require.NoError(t, json.Unmarshal(data, record))
+		assert.False(t, record.ArchivedAt.IsZero())
+		assert.Equal(t, "AccessRequest", record.AccessRequest.Kind)
+		assert.Equal(t, ar.GetName(), record.AccessRequest.GetName())
+		assert.Len(t, record.AccessRequest.Status.History, 2)
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, entries, 1)
+	})
+}
+
+func TestHTTPArchiver(t *testing.T) {
+	t.Run("will post the AccessRequest as JSON", func(t *testing.T) {
+		// Given
+		var received *ArchiveRecord
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			body, _ := io.ReadAll(r.Body)
+			received = &ArchiveRecord{}
+			assert.NoError(t, json.Unmarshal(body, received))
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer server.Close()
+		archiver := &HTTPArchiver{URL: server.URL, Client: server.Client()}
+		ar := newConcludedAccessRequest(time.Now())
+
+		// When
+		err := archiver.Archive(context.Background(), ar)
+
+		// Then
+		require.NoError(t, err)
+		require.NotNil(t, received)
+		assert.Equal(t, ar.GetName(), received.AccessRequest.GetName())
+		assert.Len(t, received.AccessRequest.Status.History, 2)
+	})
+	t.Run("will return error if endpoint responds with failure", func(t *testing.T) {
+		// Given
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("some error"))
+		}))
+		defer server.Close()
+		archiver := &HTTPArchiver{URL: server.URL, Client: server.Client()}
+
+		// When
+		err := archiver.Archive(context.Background(), newConcludedAccessRequest(time.Now()))
+
+		// Then
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "500")
+		assert.Contains(t, err.Error(), "some error")
+	})
+}
+
+func TestHandleConcluded(t *testing.T) {
+	signingKey := []byte("some-secret")
+	newReconcilerWithSignature := func(t *testing.T, ttl time.Duration, archiver Archiver, requireSignature bool, objs ...client.Object) (*AccessRequestReconciler, client.Client) {
+		t.Helper()
+		scheme := runtime.NewScheme()
+		require.NoError(t, api.AddToScheme(scheme))
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+		configMock := mocks.NewMockConfigurer(t)
+		configMock.EXPECT().ControllerConcludedTTL().Return(ttl)
+		configMock.EXPECT().ControllerRequireSignature().Return(requireSignature).Maybe()
+		configMock.EXPECT().ControllerSigningKey().Return(signingKey).Maybe()
+		return &AccessRequestReconciler{
+			Client:   c,
+			Config:   configMock,
+			Recorder: record.NewFakeRecorder(10),
+			Archiver: archiver,
+		}, c
+	}
+	newReconciler := func(t *testing.T, ttl time.Duration, archiver Archiver, objs ...client.Object) (*AccessRequestReconciler, client.Client) {
+		t.Helper()
+		return newReconcilerWithSignature(t, ttl, archiver, false, objs...)
+	}
+	t.Run("will requeue if the TTL is not elapsed", func(t *testing.T) {
+		// Given
+		ar := newConcludedAccessRequest(time.Now().Add(-time.Hour))
+		r, c := newReconciler(t, 2*time.Hour, nil, ar)
+
+		// When
+		result, err := r.handleConcluded(context.Background(), ar)
+
+		// Then
+		require.NoError(t, err)
+		assert.InDelta(t, time.Hour.Seconds(), result.RequeueAfter.Seconds(), 5)
+		err = c.Get(context.Background(), client.ObjectKeyFromObject(ar), &api.AccessRequest{})
+		assert.NoError(t, err)
+	})
+	t.Run("will retain while the RoleTemplate quotas account for it", func(t *testing.T) {
+		// Given
+		ar := newConcludedAccessRequest(time.Now().Add(-3 * time.Hour))
+		ar.Status.ExpiresAt = &metav1.Time{Time: time.Now().Add(-3 * time.Hour)}
+		rt := utils.NewRoleTemplate(ar.Spec.Role.TemplateRef.Name, ar.Spec.Role.TemplateRef.Namespace, "some-role", nil)
+		rt.Spec.Quotas = &api.Quotas{User: &api.Quota{Cooldown: &metav1.Duration{Duration: 4 * time.Hour}}}
+		r, c := newReconciler(t, 2*time.Hour, nil, ar, rt)
+
+		// When
+		result, err := r.handleConcluded(context.Background(), ar)
+
+		// Then
+		require.NoError(t, err)
+		assert.InDelta(t, time.Hour.Seconds(), result.RequeueAfter.Seconds(), 5)
+		err = c.Get(context.Background(), client.ObjectKeyFromObject(ar), &api.AccessRequest{})
+		assert.NoError(t, err)
+	})
+	t.Run("will archive and delete if the TTL is elapsed", func(t *testing.T) {
+		// Given
+		ar := newConcludedAccessRequest(time.Now().Add(-3 * time.Hour))
+		var archived *api.AccessRequest
+		archiver := archiverFunc(func(ctx context.Context, ar *api.AccessRequest) error {
+			archived = ar
+			return nil
+		})
+		r, c := newReconciler(t, 2*time.Hour, archiver, ar)
+		current := &api.AccessRequest{}
+		require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(ar), current))
+		deleted := concludedDeletedTotal.WithLabelValues(string(api.ExpiredStatus))
+		before := testutil.ToFloat64(deleted)
+
+		// When
+		result, err := r.handleConcluded(context.Background(), current)
+
+		// Then
+		require.NoError(t, err)
+		assert.Zero(t, result.RequeueAfter)
+		require.NotNil(t, archived)
+		assert.Equal(t, ar.GetName(), archived.GetName())
+		err = c.Get(context.Background(), client.ObjectKeyFromObject(ar), &api.AccessRequest{})
+		assert.True(t, apierrors.IsNotFound(err))
+		assert.Equal(t, before+1, testutil.ToFloat64(deleted))
+	})
+	t.Run("will not delete if archiving fails", func(t *testing.T) {
+		// Given
+		ar := newConcludedAccessRequest(time.Now().Add(-3 * time.Hour))
+		archiver := archiverFunc(func(ctx context.Context, ar *api.AccessRequest) error {
+			return errors.New("some error")
+		})
+		r, c := newReconciler(t, 2*time.Hour, archiver, ar)
+		before := testutil.ToFloat64(archiveErrorsTotal)
+
+		// When
+		_, err := r.handleConcluded(context.Background(), ar)
+
+		// Then
+		assert.Error(t, err)
+		err = c.Get(context.Background(), client.ObjectKeyFromObject(ar), &api.AccessRequest{})
+		assert.NoError(t, err)
+		assert.Equal(t, before+1, testutil.ToFloat64(archiveErrorsTotal))
+	})
+	t.Run("will keep break-glass AccessRequests pending review", func(t *testing.T) {
+		// Given
+		ar := newConcludedAccessRequest(time.Now().Add(-3 * time.Hour))
+		ar.Spec.BreakGlass = true
+		r, c := newReconciler(t, 2*time.Hour, nil, ar)
+
+		// When
+		result, err := r.handleConcluded(context.Background(), ar)
+
+		// Then
+		require.NoError(t, err)
+		assert.Zero(t, result.RequeueAfter)
+		err = c.Get(context.Background(), client.ObjectKeyFromObject(ar), &api.AccessRequest{})
+		assert.NoError(t, err)
+	})
+	t.Run("will delete reviewed break-glass AccessRequests", func(t *testing.T) {
+		// Given
+		ar := newConcludedAccessRequest(time.Now().Add(-3 * time.Hour))
+		ar.Spec.BreakGlass = true
+		ar.Spec.Review = &api.Review{Reviewer: "auditor", ReviewedAt: metav1.Now()}
+		r, c := newReconciler(t, 2*time.Hour, nil, ar)
+		current := &api.AccessRequest{}
+		require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(ar), current))
+
+		// When
+		_, err := r.handleConcluded(context.Background(), current)
+
+		// Then
+		require.NoError(t, err)
+		err = c.Get(context.Background(), client.ObjectKeyFromObject(ar), &api.AccessRequest{})
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+	t.Run("will keep break-glass AccessRequests with forged reviews if signatures are required", func(t *testing.T) {
+		// Given
+		ar := newConcludedAccessRequest(time.Now().Add(-3 * time.Hour))
+		ar.Spec.BreakGlass = true
+		require.NoError(t, ar.Sign(signingKey))
+		ar.Spec.Review = &api.Review{Reviewer: "auditor", ReviewedAt: metav1.Now()}
+		r, c := newReconcilerWithSignature(t, 2*time.Hour, nil, true, ar)
+
+		// When
+		result, err := r.handleConcluded(context.Background(), ar)
+
+		// Then
+		require.NoError(t, err)
+		assert.Zero(t, result.RequeueAfter)
+		err = c.Get(context.Background(), client.ObjectKeyFromObject(ar), &api.AccessRequest{})
+		assert.NoError(t, err)
+	})
+	t.Run("will delete break-glass AccessRequests reviewed by the backend if signatures are required", func(t *testing.T) {
+		// Given
+		ar := newConcludedAccessRequest(time.Now().Add(-3 * time.Hour))
+		ar.Spec.BreakGlass = true
+		require.NoError(t, ar.Sign(signingKey))
+		ar.Spec.Review = &api.Review{Reviewer: "auditor", ReviewedAt: metav1.Now()}
+		require.NoError(t, ar.SignReview(signingKey))
+		r, c := newReconcilerWithSignature(t, 2*time.Hour, nil, true, ar)
+		current := &api.AccessRequest{}
+		require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(ar), current))
+
+		// When
+		_, err := r.handleConcluded(context.Background(), current)
+
+		// Then
+		require.NoError(t, err)
+		err = c.Get(context.Background(), client.ObjectKeyFromObject(ar), &api.AccessRequest{})
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+	t.Run("will keep concluded AccessRequests if TTL is disabled", func(t *testing.T) {
+		// Given
+		ar := newConcludedAccessRequest(time.Now().Add(-3 * time.Hour))
+		r, c := newReconciler(t, 0, nil, ar)
+
+		// When
+		result, err := r.handleConcluded(context.Background(), ar)
+
+		// Then
+		require.NoError(t, err)
+		assert.Zero(t, result.RequeueAfter)
+		err = c.Get(context.Background(), client.ObjectKeyFromObject(ar), &api.AccessRequest{})
+		assert.NoError(t, err)
+	})
+}
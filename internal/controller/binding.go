@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	argocd "github.com/argoproj-labs/ephemeral-access/api/argoproj/v1alpha1"
+	api "github.com/argoproj-labs/ephemeral-access/api/ephemeral-access/v1alpha1"
+	"github.com/argoproj-labs/ephemeral-access/pkg/log"
+)
+
+// isAuthorized will verify if an AccessBinding authorizes the subject of the
+// given ar to request its role. The bindings are retrieved from the
+// RoleTemplate namespace and evaluated with the group claims recorded in
+// the ar, the same way the backend does when the AccessRequest is created.
+// The recorded group claims are provided by the requester and can only be
+// trusted if signatures are required, as the signature covers the subject.
+// Otherwise this check is not a security boundary against AccessRequests
+// created directly in Kubernetes, which is why the controller refuses to
+// start without signatures unless explicitly allowed.
+func (s *Service) isAuthorized(ctx context.Context, ar *api.AccessRequest) (bool, error) {
+	logger := log.FromContext(ctx)
+	bindings := &api.AccessBindingList{}
+	err := s.k8sClient.List(ctx, bindings, client.InNamespace(ar.Spec.Role.TemplateRef.Namespace))
+	if err != nil {
+		return false, fmt.Errorf("error listing AccessBindings: %w", err)
+	}
+
+	var app, project *unstructured.Unstructured
+	for i := range bindings.Items {
+		binding := &bindings.Items[i]
+		if binding.Spec.RoleTemplateRef.Name != ar.Spec.Role.TemplateRef.Name {
+			continue
+		}
+		// the Argo CD objects are only retrieved if there is a candidate binding
+		if project == nil {
+			app, project, err = s.getBindingObjects(ctx, ar)
+			if err != nil {
+				return false, err
+			}
+		}
+		granted, err := binding.Grants(ar.Spec.Subject.Groups, ar.Spec.Subject.Group, ar.Spec.BreakGlass, app, project)
+		if err != nil {
+			logger.Error(err, "Error evaluating AccessBinding", "binding", binding.GetName())
+			continue
+		}
+		if granted {
+			logger.Debug("AccessRequest authorized", "binding", binding.GetName())
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// getBindingObjects returns the Argo CD Application and AppProject targeted
+// by the given ar as they are provided to the AccessBinding templates. The
+// Application is nil for project-scoped AccessRequests.
+func (s *Service) getBindingObjects(ctx context.Context, ar *api.AccessRequest) (*unstructured.Unstructured, *unstructured.Unstructured, error) {
+	var app *unstructured.Unstructured
+	var err error
+	if !ar.IsProjectScoped() {
+		app, err = s.getUnstructured(ctx, argocd.ApplicationGroupVersionKind, ar.Spec.Application.Name, ar.Spec.Application.Namespace)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error getting Argo CD Application %s/%s: %w", ar.Spec.Application.Namespace, ar.Spec.Application.Name, err)
+		}
+	}
+	project, err := s.getUnstructured(ctx, argocd.AppProjectGroupVersionKind, ar.Status.TargetProject, ar.GetNamespace())
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting Argo CD AppProject %s/%s: %w", ar.GetNamespace(), ar.Status.TargetProject, err)
+	}
+	return app, project, nil
+}
+
+func (s *Service) getUnstructured(ctx context.Context, gvk schema.GroupVersionKind, name, ns string) (*unstructured.Unstructured, error) {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	err := s.k8sClient.Get(ctx, client.ObjectKey{Namespace: ns, Name: name}, obj)
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
@@ -16,6 +16,8 @@ type Configurer interface {
 	LogConfigurer
 	MetricsConfigurer
 	ControllerConfigurer
+	PluginConfigurer
+	WebhookConfigurer
 }
 
 // LogConfigurer defines the accessor methods for log configurations.
@@ -30,6 +32,18 @@ type MetricsConfigurer interface {
 	MetricsSecure() bool
 }
 
+// PluginConfigurer defines the accessor methods for plugin configurations.
+type PluginConfigurer interface {
+	PluginPath() string
+}
+
+// WebhookConfigurer defines the accessor methods for the admission webhooks
+// configurations.
+type WebhookConfigurer interface {
+	WebhookEnabled() bool
+	WebhookMaxAccessDuration() time.Duration
+}
+
 // ControllerConfigurer defines the accessor methods for the controller's
 // configurations.
 type ControllerConfigurer interface {
@@ -38,6 +52,15 @@ type ControllerConfigurer interface {
 	ControllerHealthProbeAddr() string
 	ControllerEnableHTTP2() bool
 	ControllerRequeueInterval() time.Duration
+	ControllerRoleSweeperInterval() time.Duration
+	ControllerRoleSweeperDryRun() bool
+	ControllerServerSideApply() bool
+	ControllerConcludedTTL() time.Duration
+	ControllerArchiveDir() string
+	ControllerArchiveURL() string
+	ControllerRequireSignature() bool
+	ControllerSigningKey() []byte
+	ControllerAllowUnsigned() bool
 }
 
 // MetricsAddress acessor method
@@ -60,6 +83,11 @@ func (c *Config) LogFormat() string {
 	return c.Log.Format
 }
 
+// PluginPath acessor method
+func (c *Config) PluginPath() string {
+	return c.Plugin.Path
+}
+
 // EnableLeaderElection acessor method
 func (c *Config) EnableLeaderElection() bool {
 	return c.Controller.EnableLeaderElection
@@ -85,6 +113,61 @@ func (c *Config) ControllerRequeueInterval() time.Duration {
 	return c.Controller.RequeueInterval
 }
 
+// ControllerRoleSweeperInterval acessor method
+func (c *Config) ControllerRoleSweeperInterval() time.Duration {
+	return c.Controller.RoleSweeperInterval
+}
+
+// ControllerRoleSweeperDryRun acessor method
+func (c *Config) ControllerRoleSweeperDryRun() bool {
+	return c.Controller.RoleSweeperDryRun
+}
+
+// ControllerServerSideApply acessor method
+func (c *Config) ControllerServerSideApply() bool {
+	return c.Controller.ServerSideApply
+}
+
+// ControllerConcludedTTL acessor method
+func (c *Config) ControllerConcludedTTL() time.Duration {
+	return c.Controller.ConcludedTTL
+}
+
+// ControllerArchiveDir acessor method
+func (c *Config) ControllerArchiveDir() string {
+	return c.Controller.ArchiveDir
+}
+
+// ControllerArchiveURL acessor method
+func (c *Config) ControllerArchiveURL() string {
+	return c.Controller.ArchiveURL
+}
+
+// ControllerRequireSignature acessor method
+func (c *Config) ControllerRequireSignature() bool {
+	return c.Controller.RequireSignature
+}
+
+// ControllerSigningKey acessor method
+func (c *Config) ControllerSigningKey() []byte {
+	return []byte(c.Controller.SigningKey)
+}
+
+// ControllerAllowUnsigned acessor method
+func (c *Config) ControllerAllowUnsigned() bool {
+	return c.Controller.AllowUnsigned
+}
+
+// WebhookEnabled acessor method
+func (c *Config) WebhookEnabled() bool {
+	return c.Webhook.Enabled
+}
+
+// WebhookMaxAccessDuration acessor method
+func (c *Config) WebhookMaxAccessDuration() time.Duration {
+	return c.Webhook.MaxAccessDuration
+}
+
 // Config defines all configurations available for this controller
 type Config struct {
 	// Metrics defines the metrics configurations
@@ -93,6 +176,31 @@ type Config struct {
 	Log LogConfig `env:", prefix=EPHEMERAL_LOG_"`
 	// Controller defines the controller configurations
 	Controller ControllerConfig `env:", prefix=EPHEMERAL_CONTROLLER_"`
+	// Plugin defines the plugin configurations
+	Plugin PluginConfig `env:", prefix=EPHEMERAL_PLUGIN_"`
+	// Webhook defines the admission webhooks configurations
+	Webhook WebhookConfig `env:", prefix=EPHEMERAL_WEBHOOK_"`
+}
+
+// WebhookConfig defines the admission webhooks configurations
+type WebhookConfig struct {
+	// Enabled If set, the validating admission webhooks will be registered
+	// in the controller webhook server. Requires the webhook server TLS
+	// certificates to be provided.
+	Enabled bool `env:"ENABLED, default=false"`
+	// MaxAccessDuration defines the maximum duration allowed in
+	// AccessRequests. Longer durations are rejected at admission.
+	// Valid time units are "ms", "s", "m", "h".
+	// Default: 24 hours
+	MaxAccessDuration time.Duration `env:"MAX_ACCESS_DURATION, default=24h"`
+}
+
+// PluginConfig defines the plugin configurations
+type PluginConfig struct {
+	// Path is the full path of the plugin binary to be loaded by the
+	// controller. If not provided, no plugin is loaded and all
+	// AccessRequests are allowed by default.
+	Path string `env:"PATH"`
 }
 
 // MetricsConfig defines the metrics configurations
@@ -121,6 +229,43 @@ type ControllerConfig struct {
 	// Valid time units are "ms", "s", "m", "h".
 	// Default: 3 minutes
 	RequeueInterval time.Duration `env:"REQUEUE_INTERVAL, default=3m"`
+	// RoleSweeperInterval determines the interval the controller will remove
+	// orphaned ephemeral roles from AppProjects. The sweeper is disabled
+	// unless an interval greater than 0 is set.
+	// Valid time units are "ms", "s", "m", "h".
+	// Default: 0 (disabled)
+	RoleSweeperInterval time.Duration `env:"ROLE_SWEEPER_INTERVAL, default=0"`
+	// RoleSweeperDryRun If set, the orphaned ephemeral roles are only
+	// reported without being removed from AppProjects.
+	RoleSweeperDryRun bool `env:"ROLE_SWEEPER_DRY_RUN, default=false"`
+	// ServerSideApply If set, the ephemeral AppProject roles are managed with
+	// server-side apply instead of merge patches. Requires the AppProject CRD
+	// to declare spec.roles as a list map keyed by name which is verified
+	// when the controller starts.
+	ServerSideApply bool `env:"SERVER_SIDE_APPLY, default=false"`
+	// ConcludedTTL determines how long concluded AccessRequests (denied,
+	// expired, invalid or revoked) are kept before being deleted. Set to 0
+	// to keep them forever.
+	// Valid time units are "ms", "s", "m", "h".
+	// Default: 0
+	ConcludedTTL time.Duration `env:"CONCLUDED_TTL, default=0"`
+	// ArchiveDir If set, concluded AccessRequests are written as JSON files
+	// in this directory before being deleted.
+	ArchiveDir string `env:"ARCHIVE_DIR"`
+	// ArchiveURL If set, concluded AccessRequests are sent as JSON to this
+	// HTTP endpoint before being deleted.
+	ArchiveURL string `env:"ARCHIVE_URL"`
+	// RequireSignature If set, AccessRequests without a valid signature
+	// generated by the backend with the SigningKey are marked as invalid.
+	RequireSignature bool `env:"REQUIRE_SIGNATURE, default=false"`
+	// SigningKey is the shared secret used by the backend to sign the
+	// AccessRequests. Required if RequireSignature is set.
+	SigningKey string `env:"SIGNING_KEY"`
+	// AllowUnsigned If set, the controller starts without RequireSignature.
+	// In this case AccessBindings are evaluated with the group claims
+	// provided by the requester, which can be forged by anyone allowed to
+	// create AccessRequests in Kubernetes.
+	AllowUnsigned bool `env:"ALLOW_UNSIGNED, default=false"`
 }
 
 // LogConfig defines the log configurations
@@ -138,7 +283,7 @@ type LogConfig struct {
 // String prints the config state
 func (c *Config) String() string {
 	return fmt.Sprintf(
-		"Metrics: [ Address: %s Secure: %t ] Log [ Level: %s Format: %s ] Controller [ EnableLeaderElection: %t HealthProbeAddress: %s EnableHTTP2: %t RequeueInterval: %s]",
+		"Metrics: [ Address: %s Secure: %t ] Log [ Level: %s Format: %s ] Controller [ EnableLeaderElection: %t HealthProbeAddress: %s EnableHTTP2: %t RequeueInterval: %s RoleSweeperInterval: %s RoleSweeperDryRun: %t ServerSideApply: %t ConcludedTTL: %s ArchiveDir: %s ArchiveURL: %s RequireSignature: %t ] Plugin [ Path: %s ] Webhook [ Enabled: %t MaxAccessDuration: %s ]",
 		c.Metrics.Address,
 		c.Metrics.Secure,
 		c.Log.Level,
@@ -147,6 +292,16 @@ func (c *Config) String() string {
 		c.Controller.HealthProbeAddr,
 		c.Controller.EnableHTTP2,
 		c.Controller.RequeueInterval,
+		c.Controller.RoleSweeperInterval,
+		c.Controller.RoleSweeperDryRun,
+		c.Controller.ServerSideApply,
+		c.Controller.ConcludedTTL,
+		c.Controller.ArchiveDir,
+		c.Controller.ArchiveURL,
+		c.Controller.RequireSignature,
+		c.Plugin.Path,
+		c.Webhook.Enabled,
+		c.Webhook.MaxAccessDuration,
 	)
 }
 
@@ -159,5 +314,8 @@ func ReadEnvConfigs() (Configurer, error) {
 	if err != nil {
 		return nil, fmt.Errorf("envconfig.Process error: %w", err)
 	}
+	if config.Controller.RequireSignature && config.Controller.SigningKey == "" {
+		return nil, fmt.Errorf("EPHEMERAL_CONTROLLER_SIGNING_KEY must be provided when EPHEMERAL_CONTROLLER_REQUIRE_SIGNATURE is set")
+	}
 	return &config, nil
 }
@@ -24,6 +24,18 @@ func TestConfiguration(t *testing.T) {
 		assert.Equal(t, ":8082", config.ControllerHealthProbeAddr())
 		assert.Equal(t, false, config.ControllerEnableHTTP2())
 		assert.Equal(t, time.Minute*3, config.ControllerRequeueInterval())
+		assert.Equal(t, time.Duration(0), config.ControllerRoleSweeperInterval())
+		assert.Equal(t, false, config.ControllerRoleSweeperDryRun())
+		assert.Equal(t, false, config.ControllerServerSideApply())
+		assert.Equal(t, time.Duration(0), config.ControllerConcludedTTL())
+		assert.Equal(t, "", config.ControllerArchiveDir())
+		assert.Equal(t, "", config.ControllerArchiveURL())
+		assert.Equal(t, false, config.ControllerRequireSignature())
+		assert.Empty(t, config.ControllerSigningKey())
+		assert.Equal(t, false, config.ControllerAllowUnsigned())
+		assert.Equal(t, "", config.PluginPath())
+		assert.Equal(t, false, config.WebhookEnabled())
+		assert.Equal(t, time.Hour*24, config.WebhookMaxAccessDuration())
 	})
 	t.Run("will validate if env vars are set properly", func(t *testing.T) {
 		// Given
@@ -36,6 +48,18 @@ func TestConfiguration(t *testing.T) {
 		t.Setenv("EPHEMERAL_CONTROLLER_HEALTH_PROBE_ADDR", ":1313")
 		t.Setenv("EPHEMERAL_CONTROLLER_ENABLE_HTTP2", "true")
 		t.Setenv("EPHEMERAL_CONTROLLER_REQUEUE_INTERVAL", "1s")
+		t.Setenv("EPHEMERAL_CONTROLLER_ROLE_SWEEPER_INTERVAL", "10m")
+		t.Setenv("EPHEMERAL_CONTROLLER_ROLE_SWEEPER_DRY_RUN", "true")
+		t.Setenv("EPHEMERAL_CONTROLLER_SERVER_SIDE_APPLY", "true")
+		t.Setenv("EPHEMERAL_CONTROLLER_CONCLUDED_TTL", "720h")
+		t.Setenv("EPHEMERAL_CONTROLLER_ARCHIVE_DIR", "/tmp/archive")
+		t.Setenv("EPHEMERAL_CONTROLLER_ARCHIVE_URL", "http://archive")
+		t.Setenv("EPHEMERAL_CONTROLLER_REQUIRE_SIGNATURE", "true")
+		t.Setenv("EPHEMERAL_CONTROLLER_SIGNING_KEY", "some-secret")
+		t.Setenv("EPHEMERAL_CONTROLLER_ALLOW_UNSIGNED", "true")
+		t.Setenv("EPHEMERAL_PLUGIN_PATH", "/tmp/plugin")
+		t.Setenv("EPHEMERAL_WEBHOOK_ENABLED", "true")
+		t.Setenv("EPHEMERAL_WEBHOOK_MAX_ACCESS_DURATION", "8h")
 
 		// When
 		config, err := config.ReadEnvConfigs()
@@ -51,5 +75,28 @@ func TestConfiguration(t *testing.T) {
 		assert.Equal(t, ":1313", config.ControllerHealthProbeAddr())
 		assert.Equal(t, true, config.ControllerEnableHTTP2())
 		assert.Equal(t, time.Second, config.ControllerRequeueInterval())
+		assert.Equal(t, time.Minute*10, config.ControllerRoleSweeperInterval())
+		assert.Equal(t, true, config.ControllerRoleSweeperDryRun())
+		assert.Equal(t, true, config.ControllerServerSideApply())
+		assert.Equal(t, time.Hour*720, config.ControllerConcludedTTL())
+		assert.Equal(t, "/tmp/archive", config.ControllerArchiveDir())
+		assert.Equal(t, "http://archive", config.ControllerArchiveURL())
+		assert.Equal(t, true, config.ControllerRequireSignature())
+		assert.Equal(t, []byte("some-secret"), config.ControllerSigningKey())
+		assert.Equal(t, true, config.ControllerAllowUnsigned())
+		assert.Equal(t, "/tmp/plugin", config.PluginPath())
+		assert.Equal(t, true, config.WebhookEnabled())
+		assert.Equal(t, time.Hour*8, config.WebhookMaxAccessDuration())
+	})
+	t.Run("will return error if signature is required without signing key", func(t *testing.T) {
+		// Given
+		t.Setenv("EPHEMERAL_CONTROLLER_REQUIRE_SIGNATURE", "true")
+
+		// When
+		config, err := config.ReadEnvConfigs()
+
+		// Then
+		assert.ErrorContains(t, err, "EPHEMERAL_CONTROLLER_SIGNING_KEY must be provided")
+		assert.Nil(t, config)
 	})
 }
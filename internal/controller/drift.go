@@ -0,0 +1,199 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	argocd "github.com/argoproj-labs/ephemeral-access/api/argoproj/v1alpha1"
+	api "github.com/argoproj-labs/ephemeral-access/api/ephemeral-access/v1alpha1"
+	"github.com/argoproj-labs/ephemeral-access/pkg/log"
+)
+
+const (
+	// EventReasonUnauthorizedSubjectRemoved is used when a subject without a
+	// matching AccessRequest is removed from an ephemeral role
+	EventReasonUnauthorizedSubjectRemoved = "UnauthorizedSubjectRemoved"
+	// EventReasonSubjectRestored is used when a subject with a granted
+	// AccessRequest is restored in an ephemeral role
+	EventReasonSubjectRestored = "SubjectRestored"
+
+	driftActionRemoved  = "removed"
+	driftActionRestored = "restored"
+)
+
+// ProjectDriftReconciler detects and corrects manual changes in the members
+// of the ephemeral roles managed by this controller in AppProjects.
+type ProjectDriftReconciler struct {
+	client.Client
+	Recorder record.EventRecorder
+	// ServerSideApply defines if the corrections are persisted with
+	// server-side apply instead of merge patches.
+	ServerSideApply bool
+}
+
+// driftCorrection describes a change applied in an AppProject role to
+// revert a drift.
+type driftCorrection struct {
+	role    string
+	subject string
+	action  string
+}
+
+// Reconcile is invoked on every AppProject change. It compares the groups of
+// every ephemeral role in the AppProject with the AccessRequests targeting
+// it:
+//  1. Subjects without a requested, scheduled or granted AccessRequest for
+//     the role are removed.
+//  2. Subjects with a granted AccessRequest that is not expiring or being
+//     revoked are restored in the role.
+//
+// A Warning event is recorded in the AppProject for each correction. As the
+// subject is added in the role before the AccessRequest status is updated
+// to granted, subjects of AccessRequests that are not concluded yet are
+// not considered unauthorized so the access being granted isn't reverted.
+func (r *ProjectDriftReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	logger.Debug("Drift detection started")
+
+	project := &argocd.AppProject{}
+	err := r.Get(ctx, req.NamespacedName, project)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("error retrieving AppProject: %w", err)
+	}
+
+	// This makes a requirement that the AccessRequest has to live in the
+	// same namespace as the AppProject.
+	list := &api.AccessRequestList{}
+	err = r.List(ctx, list,
+		client.InNamespace(project.GetNamespace()),
+		client.MatchingFields{projectField: project.GetName()})
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("error listing AccessRequests: %w", err)
+	}
+
+	original := project.DeepCopy()
+	corrections := correctDrift(project, list.Items)
+	if len(corrections) == 0 {
+		logger.Debug("No drift detected")
+		return ctrl.Result{}, nil
+	}
+
+	err = patchProject(ctx, r.Client, r.ServerSideApply, original, project)
+	if err != nil {
+		if apierrors.IsConflict(err) {
+			logger.Info("AppProject changed while correcting drift: retrying")
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("error patching AppProject: %w", err)
+	}
+	for _, c := range corrections {
+		logger.Info("AppProject drift corrected", "role", c.role, "subject", c.subject, "action", c.action)
+		driftCorrectionsTotal.WithLabelValues(project.GetName(), c.action).Inc()
+		switch c.action {
+		case driftActionRemoved:
+			r.Recorder.Eventf(project, corev1.EventTypeWarning, EventReasonUnauthorizedSubjectRemoved,
+				"Subject %s without AccessRequest removed from role %s", c.subject, c.role)
+		case driftActionRestored:
+			r.Recorder.Eventf(project, corev1.EventTypeWarning, EventReasonSubjectRestored,
+				"Subject %s with granted AccessRequest restored in role %s", c.subject, c.role)
+		}
+	}
+	return ctrl.Result{}, nil
+}
+
+// correctDrift will update the ephemeral roles in the given project based on
+// the given ars and return the list of corrections applied.
+func correctDrift(project *argocd.AppProject, ars []api.AccessRequest) []driftCorrection {
+	// subjects with AccessRequests in progress per role
+	allowed := make(map[string][]string)
+	// subjects that must be present per role
+	granted := make(map[string][]string)
+	now := time.Now()
+	for _, ar := range ars {
+		if ar.Status.TargetProject != project.GetName() || ar.Status.RoleName == "" {
+			continue
+		}
+		principal := ar.Spec.Subject.Principal()
+		switch ar.Status.RequestState {
+		case api.RequestedStatus, api.ScheduledStatus:
+			// the access may be granted before the status is updated
+			allowed[ar.Status.RoleName] = append(allowed[ar.Status.RoleName], principal)
+			continue
+		case api.GrantedStatus:
+			allowed[ar.Status.RoleName] = append(allowed[ar.Status.RoleName], principal)
+		default:
+			continue
+		}
+		if ar.Status.ExpiresAt != nil && ar.Status.ExpiresAt.After(now) && !ar.IsRevoking() {
+			granted[ar.Status.RoleName] = append(granted[ar.Status.RoleName], principal)
+		}
+	}
+
+	corrections := []driftCorrection{}
+	for idx, role := range project.Spec.Roles {
+		if !strings.HasPrefix(role.Name, api.AppProjectRolePrefix) {
+			continue
+		}
+		groups := []string{}
+		for _, group := range role.Groups {
+			if !slices.Contains(allowed[role.Name], group) {
+				corrections = append(corrections, driftCorrection{role: role.Name, subject: group, action: driftActionRemoved})
+				continue
+			}
+			groups = append(groups, group)
+		}
+		for _, subject := range granted[role.Name] {
+			if !slices.Contains(groups, subject) {
+				corrections = append(corrections, driftCorrection{role: role.Name, subject: subject, action: driftActionRestored})
+				groups = append(groups, subject)
+			}
+		}
+		project.Spec.Roles[idx].Groups = groups
+	}
+	return corrections
+}
+
+// callReconcileForAccessRequest will build the reconcile request for the
+// AppProject targeted by the given AccessRequest so the subject is restored
+// once the access is granted.
+func (r *ProjectDriftReconciler) callReconcileForAccessRequest(ctx context.Context, obj client.Object) []reconcile.Request {
+	ar, ok := obj.(*api.AccessRequest)
+	if !ok || ar.Status.TargetProject == "" || ar.Status.RequestState != api.GrantedStatus {
+		return nil
+	}
+	return []reconcile.Request{{
+		NamespacedName: types.NamespacedName{
+			Name:      ar.Status.TargetProject,
+			Namespace: ar.GetNamespace(),
+		},
+	}}
+}
+
+// SetupWithManager sets up the controller with the Manager. It depends on
+// the AccessRequest project index created by the AccessRequestReconciler.
+func (r *ProjectDriftReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("appproject-drift").
+		For(&argocd.AppProject{}, builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
+		Watches(&api.AccessRequest{},
+			handler.EnqueueRequestsFromMapFunc(r.callReconcileForAccessRequest),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
+		Complete(r)
+}
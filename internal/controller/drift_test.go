@@ -0,0 +1,224 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	argocd "github.com/argoproj-labs/ephemeral-access/api/argoproj/v1alpha1"
+	api "github.com/argoproj-labs/ephemeral-access/api/ephemeral-access/v1alpha1"
+	"github.com/argoproj-labs/ephemeral-access/test/utils"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func newDriftAccessRequest(name, username, role string, status api.Status, expiresAt time.Time) *api.AccessRequest {
+	ar := utils.NewAccessRequestCreated(utils.WithName(name))
+	ar.SetNamespace("ephemeral")
+	ar.Spec.Subject.Username = username
+	ar.Status.RequestState = status
+	ar.Status.TargetProject = "drift-project"
+	ar.Status.RoleName = role
+	ar.Status.ExpiresAt = &metav1.Time{Time: expiresAt}
+	return ar
+}
+
+func TestCorrectDrift(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Minute)
+	t.Run("will remove unauthorized and restore granted subjects", func(t *testing.T) {
+		// Given
+		project := &argocd.AppProject{
+			ObjectMeta: metav1.ObjectMeta{Name: "drift-project", Namespace: "ephemeral"},
+			Spec: argocd.AppProjectSpec{
+				Roles: []argocd.ProjectRole{
+					{Name: "ephemeral-role", Groups: []string{"intruder", "granted", "pending", "expiring"}},
+					{Name: "ephemeral-other", Groups: []string{}},
+					{Name: "unmanaged", Groups: []string{"someone"}},
+				},
+			},
+		}
+		ars := []api.AccessRequest{
+			*newDriftAccessRequest("granted", "granted", "ephemeral-role", api.GrantedStatus, future),
+			*newDriftAccessRequest("pending", "pending", "ephemeral-role", api.RequestedStatus, future),
+			*newDriftAccessRequest("expiring", "expiring", "ephemeral-role", api.GrantedStatus, past),
+			*newDriftAccessRequest("missing", "missing", "ephemeral-other", api.GrantedStatus, future),
+			*newDriftAccessRequest("expired", "intruder", "ephemeral-role", api.ExpiredStatus, past),
+		}
+
+		// When
+		corrections := correctDrift(project, ars)
+
+		// Then
+		assert.ElementsMatch(t, []driftCorrection{
+			{role: "ephemeral-role", subject: "intruder", action: driftActionRemoved},
+			{role: "ephemeral-other", subject: "missing", action: driftActionRestored},
+		}, corrections)
+		assert.Equal(t, []string{"granted", "pending", "expiring"}, project.Spec.Roles[0].Groups)
+		assert.Equal(t, []string{"missing"}, project.Spec.Roles[1].Groups)
+		assert.Equal(t, []string{"someone"}, project.Spec.Roles[2].Groups)
+	})
+	t.Run("will not restore subjects of AccessRequests being revoked", func(t *testing.T) {
+		// Given
+		project := &argocd.AppProject{
+			ObjectMeta: metav1.ObjectMeta{Name: "drift-project", Namespace: "ephemeral"},
+			Spec: argocd.AppProjectSpec{
+				Roles: []argocd.ProjectRole{{Name: "ephemeral-role"}},
+			},
+		}
+		ar := newDriftAccessRequest("revoking", "revoking", "ephemeral-role", api.GrantedStatus, future)
+		ar.Spec.Revocation = &api.Revocation{Revoker: "admin"}
+
+		// When
+		corrections := correctDrift(project, []api.AccessRequest{*ar})
+
+		// Then
+		assert.Empty(t, corrections)
+	})
+	t.Run("will restore groups of group AccessRequests", func(t *testing.T) {
+		// Given
+		project := &argocd.AppProject{
+			ObjectMeta: metav1.ObjectMeta{Name: "drift-project", Namespace: "ephemeral"},
+			Spec: argocd.AppProjectSpec{
+				Roles: []argocd.ProjectRole{{Name: "ephemeral-role", Groups: []string{"requester"}}},
+			},
+		}
+		ar := newDriftAccessRequest("group", "requester", "ephemeral-role", api.GrantedStatus, future)
+		ar.Spec.Subject.Group = "on-call"
+
+		// When
+		corrections := correctDrift(project, []api.AccessRequest{*ar})
+
+		// Then
+		assert.ElementsMatch(t, []driftCorrection{
+			{role: "ephemeral-role", subject: "requester", action: driftActionRemoved},
+			{role: "ephemeral-role", subject: "on-call", action: driftActionRestored},
+		}, corrections)
+		assert.Equal(t, []string{"on-call"}, project.Spec.Roles[0].Groups)
+	})
+}
+
+func TestProjectDriftReconciler(t *testing.T) {
+	t.Run("will patch the AppProject and count corrections", func(t *testing.T) {
+		// Given
+		project := &argocd.AppProject{
+			ObjectMeta: metav1.ObjectMeta{Name: "drift-project", Namespace: "ephemeral"},
+			Spec: argocd.AppProjectSpec{
+				Roles: []argocd.ProjectRole{{Name: "ephemeral-role", Groups: []string{"intruder"}}},
+			},
+		}
+		ar := newDriftAccessRequest("granted", "granted", "ephemeral-role", api.GrantedStatus, time.Now().Add(time.Hour))
+		scheme := runtime.NewScheme()
+		require.NoError(t, api.AddToScheme(scheme))
+		require.NoError(t, argocd.AddToScheme(scheme))
+		c := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(project, ar).
+			WithIndex(&api.AccessRequest{}, projectField, func(obj client.Object) []string {
+				return []string{obj.(*api.AccessRequest).Status.TargetProject}
+			}).
+			Build()
+		recorder := record.NewFakeRecorder(10)
+		r := &ProjectDriftReconciler{Client: c, Recorder: recorder}
+		removed := driftCorrectionsTotal.WithLabelValues("drift-project", driftActionRemoved)
+		restored := driftCorrectionsTotal.WithLabelValues("drift-project", driftActionRestored)
+		removedBefore := testutil.ToFloat64(removed)
+		restoredBefore := testutil.ToFloat64(restored)
+
+		// When
+		_, err := r.Reconcile(context.Background(), ctrl.Request{
+			NamespacedName: types.NamespacedName{Name: "drift-project", Namespace: "ephemeral"},
+		})
+
+		// Then
+		require.NoError(t, err)
+		result := &argocd.AppProject{}
+		err = c.Get(context.Background(), client.ObjectKeyFromObject(project), result)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"granted"}, result.Spec.Roles[0].Groups)
+		assert.Equal(t, removedBefore+1, testutil.ToFloat64(removed))
+		assert.Equal(t, restoredBefore+1, testutil.ToFloat64(restored))
+		assert.Len(t, recorder.Events, 2)
+	})
+	t.Run("will not correct subjects while the access is being granted", func(t *testing.T) {
+		// Given
+		project := &argocd.AppProject{
+			ObjectMeta: metav1.ObjectMeta{Name: "drift-project", Namespace: "ephemeral"},
+			Spec: argocd.AppProjectSpec{
+				Roles: []argocd.ProjectRole{{Name: "ephemeral-role", Groups: []string{}}},
+			},
+		}
+		ar := newDriftAccessRequest("granting", "granting", "ephemeral-role", api.RequestedStatus, time.Now().Add(time.Hour))
+		scheme := runtime.NewScheme()
+		require.NoError(t, api.AddToScheme(scheme))
+		require.NoError(t, argocd.AddToScheme(scheme))
+		c := fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(project, ar).
+			WithStatusSubresource(ar).
+			WithIndex(&api.AccessRequest{}, projectField, func(obj client.Object) []string {
+				return []string{obj.(*api.AccessRequest).Status.TargetProject}
+			}).
+			Build()
+		recorder := record.NewFakeRecorder(10)
+		r := &ProjectDriftReconciler{Client: c, Recorder: recorder}
+		req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "drift-project", Namespace: "ephemeral"}}
+		removed := driftCorrectionsTotal.WithLabelValues("drift-project", driftActionRemoved)
+		restored := driftCorrectionsTotal.WithLabelValues("drift-project", driftActionRestored)
+		removedBefore := testutil.ToFloat64(removed)
+		restoredBefore := testutil.ToFloat64(restored)
+
+		// When
+		// the subject is added in the role before the status is updated
+		granting := project.DeepCopy()
+		granting.Spec.Roles[0].Groups = []string{"granting"}
+		err := c.Patch(context.Background(), granting, client.MergeFrom(project))
+		require.NoError(t, err)
+		_, err = r.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+		granted := ar.DeepCopy()
+		granted.Status.RequestState = api.GrantedStatus
+		err = c.Status().Update(context.Background(), granted)
+		require.NoError(t, err)
+		_, err = r.Reconcile(context.Background(), req)
+
+		// Then
+		require.NoError(t, err)
+		result := &argocd.AppProject{}
+		err = c.Get(context.Background(), client.ObjectKeyFromObject(project), result)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"granting"}, result.Spec.Roles[0].Groups)
+		assert.Equal(t, removedBefore, testutil.ToFloat64(removed))
+		assert.Equal(t, restoredBefore, testutil.ToFloat64(restored))
+		assert.Empty(t, recorder.Events)
+	})
+}
+
+func TestProjectDriftReconciler_callReconcileForAccessRequest(t *testing.T) {
+	r := &ProjectDriftReconciler{}
+	t.Run("will enqueue the target project of granted AccessRequests", func(t *testing.T) {
+		ar := newDriftAccessRequest("granted", "granted", "ephemeral-role", api.GrantedStatus, time.Now().Add(time.Hour))
+
+		requests := r.callReconcileForAccessRequest(context.Background(), ar)
+
+		assert.Equal(t, []reconcile.Request{{
+			NamespacedName: types.NamespacedName{Name: "drift-project", Namespace: "ephemeral"},
+		}}, requests)
+	})
+	t.Run("will not enqueue AccessRequests that are not granted", func(t *testing.T) {
+		ar := newDriftAccessRequest("pending", "pending", "ephemeral-role", api.RequestedStatus, time.Now().Add(time.Hour))
+
+		requests := r.callReconcileForAccessRequest(context.Background(), ar)
+
+		assert.Empty(t, requests)
+	})
+}
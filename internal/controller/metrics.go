@@ -0,0 +1,262 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	api "github.com/argoproj-labs/ephemeral-access/api/ephemeral-access/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	metricsNamespace = "ephemeral_access"
+
+	// grantedCollectorTimeout defines how long the granted accesses collector
+	// will wait when listing AccessRequests during a metrics scrape.
+	grantedCollectorTimeout = 5 * time.Second
+)
+
+var (
+	// transitionsTotal counts the AccessRequest status transitions by
+	// status, role template and project.
+	transitionsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "accessrequest_transitions_total",
+			Help:      "Total number of AccessRequest status transitions.",
+		},
+		[]string{"status", "role_template", "project"},
+	)
+
+	// grantDurationSeconds observes the time taken between an AccessRequest
+	// being requested and its access being granted.
+	grantDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "accessrequest_grant_duration_seconds",
+			Help:      "Time in seconds between an AccessRequest being requested and granted.",
+			// from 1 second to ~3 days to include AccessRequests
+			// pending manual approval
+			Buckets: prometheus.ExponentialBuckets(1, 4, 10),
+		},
+		[]string{"role_template", "project"},
+	)
+
+	// expirationDelaySeconds observes how late AccessRequests are expired
+	// relative to their Status.ExpiresAt.
+	expirationDelaySeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "accessrequest_expiration_delay_seconds",
+			Help:      "Time in seconds between the AccessRequest expiration time and its access being removed.",
+			Buckets:   prometheus.ExponentialBuckets(0.5, 2, 12),
+		},
+		[]string{"role_template", "project"},
+	)
+
+	// appProjectPatchConflictsTotal counts the conflicts returned when
+	// patching AppProjects. Every conflict causes the patch to be retried.
+	appProjectPatchConflictsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "appproject_patch_conflicts_total",
+			Help:      "Total number of conflicts returned when patching AppProjects.",
+		},
+		[]string{"project"},
+	)
+
+	// appProjectPatchRetriesExhaustedTotal counts the AppProject patches
+	// that failed after exhausting all conflict retries.
+	appProjectPatchRetriesExhaustedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "appproject_patch_retries_exhausted_total",
+			Help:      "Total number of AppProject patches that failed after exhausting conflict retries.",
+		},
+		[]string{"project"},
+	)
+
+	// sweeperRolesRemovedTotal counts the orphaned ephemeral roles removed
+	// from AppProjects by the role sweeper. When running in dry-run mode, the
+	// roles are counted but not removed.
+	sweeperRolesRemovedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "sweeper_roles_removed_total",
+			Help:      "Total number of orphaned ephemeral roles removed from AppProjects.",
+		},
+		[]string{"project", "dry_run"},
+	)
+
+	// driftCorrectionsTotal counts the subjects removed from or restored in
+	// ephemeral AppProject roles after being changed manually.
+	driftCorrectionsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "appproject_drift_corrections_total",
+			Help:      "Total number of corrections applied in ephemeral AppProject roles changed manually.",
+		},
+		[]string{"project", "action"},
+	)
+
+	// concludedDeletedTotal counts the concluded AccessRequests deleted
+	// after their retention TTL elapsed.
+	concludedDeletedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "accessrequests_deleted_total",
+			Help:      "Total number of concluded AccessRequests deleted after their retention TTL.",
+		},
+		[]string{"status"},
+	)
+
+	// archiveErrorsTotal counts the failures archiving concluded
+	// AccessRequests. AccessRequests are not deleted if archiving fails.
+	archiveErrorsTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "archive_errors_total",
+			Help:      "Total number of failures archiving concluded AccessRequests.",
+		},
+	)
+
+	// sweeperErrorsTotal counts the role sweeper executions that failed.
+	sweeperErrorsTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "sweeper_errors_total",
+			Help:      "Total number of role sweeper executions that failed.",
+		},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		transitionsTotal,
+		grantDurationSeconds,
+		expirationDelaySeconds,
+		appProjectPatchConflictsTotal,
+		appProjectPatchRetriesExhaustedTotal,
+		sweeperRolesRemovedTotal,
+		sweeperErrorsTotal,
+		driftCorrectionsTotal,
+		concludedDeletedTotal,
+		archiveErrorsTotal,
+	)
+}
+
+// recordTransitionMetrics will update the metrics associated with the
+// transition of the given ar to the given status. It must be invoked after
+// the status history is updated.
+func recordTransitionMetrics(ar *api.AccessRequest, status api.Status) {
+	roleTemplate := ar.Spec.Role.TemplateRef.Name
+	project := ar.Status.TargetProject
+	transitionsTotal.WithLabelValues(string(status), roleTemplate, project).Inc()
+
+	if status == api.GrantedStatus {
+		if requestedAt := requestedAt(ar); requestedAt != nil {
+			grantDurationSeconds.WithLabelValues(roleTemplate, project).
+				Observe(time.Since(requestedAt.Time).Seconds())
+		}
+	}
+}
+
+// recordExpirationMetrics will observe how late the given ar is being
+// expired relative to its Status.ExpiresAt.
+func recordExpirationMetrics(ar *api.AccessRequest) {
+	if ar.Status.ExpiresAt == nil {
+		return
+	}
+	delay := time.Since(ar.Status.ExpiresAt.Time)
+	if delay < 0 {
+		delay = 0
+	}
+	expirationDelaySeconds.WithLabelValues(ar.Spec.Role.TemplateRef.Name, ar.Status.TargetProject).
+		Observe(delay.Seconds())
+}
+
+// requestedAt returns the time the given ar transitioned to the requested
+// status. It will fallback to the creation timestamp if the history doesn't
+// have a requested entry.
+func requestedAt(ar *api.AccessRequest) *metav1.Time {
+	for _, h := range ar.Status.History {
+		if h.RequestState == api.RequestedStatus {
+			return h.TransitionTime.DeepCopy()
+		}
+	}
+	if ar.CreationTimestamp.IsZero() {
+		return nil
+	}
+	return ar.CreationTimestamp.DeepCopy()
+}
+
+// grantedCollector is a prometheus collector that reports the number of
+// currently granted AccessRequests by role template and project. The value
+// is calculated from the AccessRequests in the cache during every scrape
+// so it remains accurate across controller restarts.
+type grantedCollector struct {
+	reader client.Reader
+	desc   *prometheus.Desc
+}
+
+func newGrantedCollector(reader client.Reader) *grantedCollector {
+	return &grantedCollector{
+		reader: reader,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(metricsNamespace, "", "accessrequests_granted"),
+			"Number of AccessRequests currently granted.",
+			[]string{"role_template", "project"}, nil,
+		),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *grantedCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect implements prometheus.Collector
+func (c *grantedCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), grantedCollectorTimeout)
+	defer cancel()
+
+	list := &api.AccessRequestList{}
+	err := c.reader.List(ctx, list)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.desc, fmt.Errorf("error listing AccessRequests: %w", err))
+		return
+	}
+	type key struct {
+		roleTemplate string
+		project      string
+	}
+	granted := make(map[key]int)
+	for _, ar := range list.Items {
+		if ar.Status.RequestState != api.GrantedStatus {
+			continue
+		}
+		granted[key{ar.Spec.Role.TemplateRef.Name, ar.Status.TargetProject}]++
+	}
+	for k, count := range granted {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(count), k.roleTemplate, k.project)
+	}
+}
+
+// registerGrantedCollector will register the granted accesses collector
+// in the controller-runtime metrics registry using the given reader.
+func registerGrantedCollector(reader client.Reader) error {
+	err := metrics.Registry.Register(newGrantedCollector(reader))
+	if err != nil {
+		are := &prometheus.AlreadyRegisteredError{}
+		if errors.As(err, are) {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	api "github.com/argoproj-labs/ephemeral-access/api/ephemeral-access/v1alpha1"
+	"github.com/argoproj-labs/ephemeral-access/test/mocks"
+	"github.com/argoproj-labs/ephemeral-access/test/utils"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type listReader struct {
+	client.Reader
+	list *api.AccessRequestList
+	err  error
+}
+
+func (r *listReader) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.list.DeepCopyInto(list.(*api.AccessRequestList))
+	return nil
+}
+
+func TestRecordTransitionMetrics(t *testing.T) {
+	t.Run("will count transitions by status, role template and project", func(t *testing.T) {
+		// Given
+		ar := utils.NewAccessRequestRequested()
+		ar.Status.TargetProject = "transitions-project"
+		counter := transitionsTotal.WithLabelValues("requested", ar.Spec.Role.TemplateRef.Name, "transitions-project")
+		before := testutil.ToFloat64(counter)
+
+		// When
+		recordTransitionMetrics(ar, api.RequestedStatus)
+
+		// Then
+		assert.Equal(t, before+1, testutil.ToFloat64(counter))
+	})
+	t.Run("will observe the grant duration when granted", func(t *testing.T) {
+		// Given
+		ar := utils.NewAccessRequestRequested()
+		ar.Status.TargetProject = "grant-duration-project"
+		ar.Status.History = []api.AccessRequestHistory{
+			{
+				TransitionTime: metav1.NewTime(time.Now().Add(-time.Minute)),
+				RequestState:   api.RequestedStatus,
+			},
+		}
+
+		// When
+		recordTransitionMetrics(ar, api.GrantedStatus)
+
+		// Then
+		h := histogram(t, grantDurationSeconds, ar.Spec.Role.TemplateRef.Name, "grant-duration-project")
+		assert.Equal(t, uint64(1), h.GetSampleCount())
+		assert.InDelta(t, time.Minute.Seconds(), h.GetSampleSum(), 5)
+	})
+	t.Run("will not observe the grant duration for other transitions", func(t *testing.T) {
+		// Given
+		ar := utils.NewAccessRequestRequested()
+		ar.Status.TargetProject = "denied-project"
+
+		// When
+		recordTransitionMetrics(ar, api.DeniedStatus)
+
+		// Then
+		h := histogram(t, grantDurationSeconds, ar.Spec.Role.TemplateRef.Name, "denied-project")
+		assert.Equal(t, uint64(0), h.GetSampleCount())
+	})
+}
+
+func TestRecordExpirationMetrics(t *testing.T) {
+	t.Run("will observe how late the access was expired", func(t *testing.T) {
+		// Given
+		ar := utils.NewAccessRequestGranted()
+		ar.Status.TargetProject = "expiration-project"
+		ar.Status.ExpiresAt = &metav1.Time{Time: time.Now().Add(-3 * time.Second)}
+
+		// When
+		recordExpirationMetrics(ar)
+
+		// Then
+		h := histogram(t, expirationDelaySeconds, ar.Spec.Role.TemplateRef.Name, "expiration-project")
+		assert.Equal(t, uint64(1), h.GetSampleCount())
+		assert.InDelta(t, 3, h.GetSampleSum(), 1)
+	})
+}
+
+func TestGrantedCollector(t *testing.T) {
+	t.Run("will report granted AccessRequests by role template and project", func(t *testing.T) {
+		// Given
+		granted1 := utils.NewAccessRequestGranted()
+		granted1.Status.TargetProject = "project-a"
+		granted2 := utils.NewAccessRequestGranted()
+		granted2.Status.TargetProject = "project-a"
+		granted3 := utils.NewAccessRequestGranted()
+		granted3.Status.TargetProject = "project-b"
+		expired := utils.NewAccessRequestExpired()
+		expired.Status.TargetProject = "project-a"
+		reader := &listReader{
+			list: &api.AccessRequestList{
+				Items: []api.AccessRequest{*granted1, *granted2, *granted3, *expired},
+			},
+		}
+		collector := newGrantedCollector(reader)
+
+		// When
+		expected := `
+			# HELP ephemeral_access_accessrequests_granted Number of AccessRequests currently granted.
+			# TYPE ephemeral_access_accessrequests_granted gauge
+			ephemeral_access_accessrequests_granted{project="project-a",role_template="role-template-name"} 2
+			ephemeral_access_accessrequests_granted{project="project-b",role_template="role-template-name"} 1
+		`
+		err := testutil.CollectAndCompare(collector, strings.NewReader(expected))
+
+		// Then
+		assert.NoError(t, err)
+	})
+	t.Run("will report invalid metric if list fails", func(t *testing.T) {
+		// Given
+		reader := &listReader{err: errors.New("some error")}
+		collector := newGrantedCollector(reader)
+
+		// When
+		err := testutil.CollectAndCompare(collector, strings.NewReader(""))
+
+		// Then
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "some error")
+	})
+}
+
+func histogram(t *testing.T, vec *prometheus.HistogramVec, labels ...string) *dto.Histogram {
+	t.Helper()
+	m := &dto.Metric{}
+	err := vec.WithLabelValues(labels...).(prometheus.Metric).Write(m)
+	require.NoError(t, err)
+	return m.GetHistogram()
+}
+
+func TestAppProjectPatchMetrics(t *testing.T) {
+	t.Run("will count conflicts and exhausted retries when patching AppProject", func(t *testing.T) {
+		// Given
+		conflict := apierrors.NewConflict(schema.GroupResource{Resource: "appprojects"}, "conflict-project", errors.New("conflict"))
+		clientMock := mocks.NewMockK8sClient(t)
+		clientMock.EXPECT().
+			Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.AppProject")).
+			Return(nil)
+		clientMock.EXPECT().
+			Patch(mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			RunAndReturn(func(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				obj.SetName("conflict-project")
+				return conflict
+			})
+		svc := NewService(clientMock, nil, nil, record.NewFakeRecorder(10))
+		ar := utils.NewAccessRequestRequested()
+		ar.Status.TargetProject = "conflict-project"
+		rt := utils.NewRoleTemplate("role-template-name", "ephemeral", "role", nil)
+		conflicts := appProjectPatchConflictsTotal.WithLabelValues("conflict-project")
+		exhausted := appProjectPatchRetriesExhaustedTotal.WithLabelValues("conflict-project")
+		conflictsBefore := testutil.ToFloat64(conflicts)
+		exhaustedBefore := testutil.ToFloat64(exhausted)
+
+		// When
+		_, err := svc.grantArgoCDAccess(context.Background(), ar, rt)
+
+		// Then
+		assert.Error(t, err)
+		assert.Equal(t, conflictsBefore+float64(retry.DefaultRetry.Steps), testutil.ToFloat64(conflicts))
+		assert.Equal(t, exhaustedBefore+1, testutil.ToFloat64(exhausted))
+	})
+}
@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/fields"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	api "github.com/argoproj-labs/ephemeral-access/api/ephemeral-access/v1alpha1"
+)
+
+// validateQuotas will verify if the given ar is allowed by the quotas defined
+// in its RoleTemplate. The quotas are evaluated at the ar creation time so
+// requests created afterwards don't affect it. Returns an
+// AccessRequestConflictError if a quota is exceeded.
+func (r *AccessRequestReconciler) validateQuotas(ctx context.Context, ar *api.AccessRequest) error {
+	rt, err := r.getRoleTemplate(ctx, ar)
+	if err != nil {
+		// missing RoleTemplates are reported later in the reconciliation
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("error getting RoleTemplate: %w", err)
+	}
+	if rt.Spec.Quotas == nil {
+		return nil
+	}
+
+	list := &api.AccessRequestList{}
+	selector := fields.SelectorFromSet(
+		fields.Set{
+			roleTemplateNameField:      ar.Spec.Role.TemplateRef.Name,
+			roleTemplateNamespaceField: ar.Spec.Role.TemplateRef.Namespace,
+		})
+	err = r.List(ctx, list, &client.ListOptions{Namespace: ar.GetNamespace(), FieldSelector: selector})
+	if err != nil {
+		return fmt.Errorf("error listing AccessRequests for RoleTemplate: %w", err)
+	}
+
+	err = rt.Spec.Quotas.Check(ar, list.Items, ar.GetCreationTimestamp().Time)
+	if errors.Is(err, api.ErrQuotaExceeded) {
+		return NewAccessRequestConflictError(err.Error())
+	}
+	return err
+}
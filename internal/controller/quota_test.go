@@ -0,0 +1,92 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	api "github.com/argoproj-labs/ephemeral-access/api/ephemeral-access/v1alpha1"
+	"github.com/argoproj-labs/ephemeral-access/test/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newQuotaFixture(t *testing.T, objs ...client.Object) *AccessRequestReconciler {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, api.AddToScheme(scheme))
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithIndex(&api.AccessRequest{}, roleTemplateNameField, func(obj client.Object) []string {
+			return []string{obj.(*api.AccessRequest).Spec.Role.TemplateRef.Name}
+		}).
+		WithIndex(&api.AccessRequest{}, roleTemplateNamespaceField, func(obj client.Object) []string {
+			return []string{obj.(*api.AccessRequest).Spec.Role.TemplateRef.Namespace}
+		}).
+		Build()
+	return &AccessRequestReconciler{Client: c}
+}
+
+func TestValidateQuotas(t *testing.T) {
+	newQuotaAccessRequest := func(name string, status api.Status, createdAt time.Time) *api.AccessRequest {
+		ar := utils.NewAccessRequest(name, "ephemeral", "some-app", "argocd", "some-role", "ephemeral", "some-user")
+		ar.CreationTimestamp = metav1.NewTime(createdAt)
+		ar.Status.RequestState = status
+		return ar
+	}
+	newQuotaRoleTemplate := func(quotas *api.Quotas) *api.RoleTemplate {
+		rt := utils.NewRoleTemplate("some-role", "ephemeral", "some-role", nil)
+		rt.Spec.Quotas = quotas
+		return rt
+	}
+	t.Run("will return conflict error if the user quota is exceeded", func(t *testing.T) {
+		// Given
+		now := time.Now().Truncate(time.Second)
+		granted := newQuotaAccessRequest("granted", api.GrantedStatus, now.Add(-time.Hour))
+		granted.Spec.Application.Name = "other-app"
+		ar := newQuotaAccessRequest("new", "", now)
+		rt := newQuotaRoleTemplate(&api.Quotas{User: &api.Quota{MaxConcurrent: ptr.To(int32(1))}})
+		r := newQuotaFixture(t, granted, ar, rt)
+
+		// When
+		err := r.validateQuotas(context.Background(), ar)
+
+		// Then
+		require.Error(t, err)
+		assert.IsType(t, &AccessRequestConflictError{}, err)
+		assert.Contains(t, err.Error(), "user quota allows at most 1 concurrent requests")
+	})
+	t.Run("will ignore requests created afterwards", func(t *testing.T) {
+		// Given
+		now := time.Now().Truncate(time.Second)
+		ar := newQuotaAccessRequest("first", "", now.Add(-time.Minute))
+		newer := newQuotaAccessRequest("second", "", now)
+		newer.Spec.Application.Name = "other-app"
+		period := &metav1.Duration{Duration: time.Hour}
+		rt := newQuotaRoleTemplate(&api.Quotas{User: &api.Quota{MaxRequests: ptr.To(int32(1)), Period: period}})
+		r := newQuotaFixture(t, ar, newer, rt)
+
+		// When
+		err := r.validateQuotas(context.Background(), ar)
+
+		// Then
+		assert.NoError(t, err)
+	})
+	t.Run("will skip quotas if the RoleTemplate is not found", func(t *testing.T) {
+		// Given
+		ar := newQuotaAccessRequest("new", "", time.Now())
+		r := newQuotaFixture(t, ar)
+
+		// When
+		err := r.validateQuotas(context.Background(), ar)
+
+		// Then
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	api "github.com/argoproj-labs/ephemeral-access/api/ephemeral-access/v1alpha1"
+	"github.com/argoproj-labs/ephemeral-access/pkg/log"
+)
+
+const (
+	// EventReasonInvalidRoleTemplate is used when the RoleTemplate templates
+	// can not be rendered or produce invalid Argo CD policies
+	EventReasonInvalidRoleTemplate = "InvalidRoleTemplate"
+
+	// roleTemplateSyncedMessage is the status message used when the
+	// RoleTemplate is valid
+	roleTemplateSyncedMessage = "RoleTemplate is valid"
+)
+
+// RoleTemplateReconciler reconciles a RoleTemplate object
+type RoleTemplateReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// Reconcile will validate the RoleTemplate on every spec change and update
+// its status accordingly. It will:
+//  1. Parse and test-render the description and policies templates
+//  2. Validate each rendered policy as an Argo CD AppProject role policy
+//  3. Update the status with the validation result and the RoleTemplate hash
+func (r *RoleTemplateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	logger := log.FromContext(ctx)
+	logger.Debug("RoleTemplate reconciliation started")
+
+	rt := &api.RoleTemplate{}
+	if err := r.Get(ctx, req.NamespacedName, rt); err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Debug("Object deleted")
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("error retrieving RoleTemplate from k8s: %w", err)
+	}
+
+	status := api.RoleTemplateStatus{
+		Synced:   true,
+		Message:  roleTemplateSyncedMessage,
+		SyncHash: RoleTemplateHash(rt),
+	}
+	err := rt.Validate()
+	if err != nil {
+		logger.Info("RoleTemplate validation error", "error", err.Error())
+		status.Synced = false
+		status.Message = err.Error()
+	}
+
+	if rt.Status == status {
+		logger.Debug("RoleTemplate status is up to date")
+		return ctrl.Result{}, nil
+	}
+	rt.Status = status
+	err = r.Status().Update(ctx, rt)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("error updating RoleTemplate status: %w", err)
+	}
+	if !status.Synced {
+		r.Recorder.Event(rt, corev1.EventTypeWarning, EventReasonInvalidRoleTemplate, status.Message)
+	}
+	logger.Info("RoleTemplate reconciliation concluded", "synced", status.Synced)
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *RoleTemplateReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&api.RoleTemplate{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Complete(r)
+}
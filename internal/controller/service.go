@@ -3,22 +3,79 @@ package controller
 import (
 	"context"
 	"crypto/sha1"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	argocd "github.com/argoproj-labs/ephemeral-access/api/argoproj/v1alpha1"
 	api "github.com/argoproj-labs/ephemeral-access/api/ephemeral-access/v1alpha1"
 	"github.com/argoproj-labs/ephemeral-access/internal/controller/config"
 	"github.com/argoproj-labs/ephemeral-access/pkg/log"
+	"github.com/argoproj-labs/ephemeral-access/pkg/plugin"
 	"github.com/cnf/structhash"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
 	FieldOwnerEphemeralAccess = "ephemeral-access-controller"
+
+	// appProjectCRDName is the name of the Argo CD AppProject CRD
+	appProjectCRDName = "appprojects.argoproj.io"
+
+	// EventReasonSubjectAdded is used when a subject is added in an AppProject role
+	EventReasonSubjectAdded = "SubjectAdded"
+	// EventReasonSubjectRemoved is used when a subject is removed from an AppProject role
+	EventReasonSubjectRemoved = "SubjectRemoved"
+	// EventReasonExtended is used when a granted access is extended
+	EventReasonExtended = "Extended"
+	// EventReasonExtensionRejected is used when an extension can not be applied
+	EventReasonExtensionRejected = "ExtensionRejected"
+	// EventReasonBreakGlass is used when a break-glass access is granted
+	EventReasonBreakGlass = "BreakGlass"
+
+	// ConditionReasonPatchFailed is used in the ProjectPatched condition when
+	// the AppProject can not be patched
+	ConditionReasonPatchFailed = "PatchFailed"
 )
 
+// statusEventReasons defines the event reason recorded when an AccessRequest
+// transitions to the status used as key.
+var statusEventReasons = map[api.Status]string{
+	api.RequestedStatus: "Requested",
+	api.ScheduledStatus: "Scheduled",
+	api.GrantedStatus:   "Granted",
+	api.DeniedStatus:    "Denied",
+	api.ExpiredStatus:   "Expired",
+	api.InvalidStatus:   "Invalid",
+	api.RevokedStatus:   "Revoked",
+}
+
+// recordStatusEvent will record an event in the given ar describing its
+// transition to the given status and update the transition metrics. Denied
+// and Invalid transitions are recorded as warnings.
+func recordStatusEvent(recorder record.EventRecorder, ar *api.AccessRequest, status api.Status, details string) {
+	recordTransitionMetrics(ar, status)
+	eventType := corev1.EventTypeNormal
+	if status == api.DeniedStatus || status == api.InvalidStatus {
+		eventType = corev1.EventTypeWarning
+	}
+	msg := fmt.Sprintf("AccessRequest %s", status)
+	if details != "" {
+		msg = fmt.Sprintf("%s: %s", msg, details)
+	}
+	recorder.Event(ar, eventType, statusEventReasons[status], msg)
+}
+
 type K8sClient interface {
 	// Patch patches the given obj in the Kubernetes cluster. obj must be a
 	// struct pointer so that obj can be updated with the content returned by the Server.
@@ -29,6 +86,9 @@ type K8sClient interface {
 	// returned by the Server.
 	Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error
 
+	// List retrieves list of objects for a given namespace and list options.
+	List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error
+
 	// Status knows how to create a client which can update status subresource
 	// for kubernetes objects.
 	Status() client.SubResourceWriter
@@ -37,12 +97,20 @@ type K8sClient interface {
 type Service struct {
 	k8sClient K8sClient
 	Config    config.ControllerConfigurer
+	plugin    plugin.AccessRequester
+	recorder  record.EventRecorder
 }
 
-func NewService(c K8sClient, cfg config.ControllerConfigurer) *Service {
+// NewService will return a new Service instance. The given p is optional
+// and when nil, all AccessRequests will be allowed without invoking any
+// AccessRequester plugin. The given r is used to record events in the
+// AccessRequests and AppProjects handled by this service.
+func NewService(c K8sClient, cfg config.ControllerConfigurer, p plugin.AccessRequester, r record.EventRecorder) *Service {
 	return &Service{
 		k8sClient: c,
 		Config:    cfg,
+		plugin:    p,
+		recorder:  r,
 	}
 }
 
@@ -50,10 +118,24 @@ func NewService(c K8sClient, cfg config.ControllerConfigurer) *Service {
 // or removing Argo CD access for the subject listed in the AccessRequest.
 // The following validations will be executed:
 //  1. Check if the given ar is expired. If so, the subject will be removed from
-//     the Argo CD role.
-//  2. Check if the subject is allowed to be assigned in the given AccessRequest
-//     target role. If so, it will proceed with grating Argo CD access. Otherwise
-//     it will return DeniedStatus.
+//     the Argo CD role and the plugin will be invoked to revoke the access.
+//  2. Check if the AccessRequest signature is valid when required by the
+//     controller configuration and if its duration is allowed by the given
+//     rt. If not, it will return InvalidStatus.
+//  3. Check if an AccessBinding authorizes the subject to request the role
+//     with the group claims recorded in the AccessRequest. If not, it will
+//     return DeniedStatus.
+//  4. Check if the AccessRequest was approved if the given rt requires manual
+//     approval. Until approved, it will remain in RequestedStatus.
+//  5. Check if the AccessRequest start time is in the future. If so, it will
+//     remain in ScheduledStatus until the start time.
+//  6. Check if the subject is allowed to be assigned in the given AccessRequest
+//     target role by invoking the configured plugin. If so, it will proceed with
+//     grating Argo CD access. If the plugin returns pending, the AccessRequest
+//     will remain in RequestedStatus. Otherwise it will return DeniedStatus.
+//
+// Break-glass AccessRequests skip the approval and the plugin checks when
+// allowed by the given rt. A Warning event is recorded when they are granted.
 //
 // It will update the AccessRequest status accordingly with the situation.
 func (s *Service) HandlePermission(ctx context.Context, ar *api.AccessRequest, app *argocd.Application, rt *api.RoleTemplate) (api.Status, error) {
@@ -61,75 +143,450 @@ func (s *Service) HandlePermission(ctx context.Context, ar *api.AccessRequest, a
 
 	if ar.IsExpiring() {
 		logger.Info("AccessRequest is expired")
-		err := s.handleAccessExpired(ctx, ar, rt)
+		status, err := s.handleAccessExpired(ctx, ar, app, rt)
 		if err != nil {
 			return "", fmt.Errorf("error handling access expired: %w", err)
 		}
-		return api.ExpiredStatus, nil
+		return status, nil
 	}
 
-	resp, err := Allowed(ctx, ar, app)
-	if err != nil {
-		return "", fmt.Errorf("error verifying if subject is allowed: %w", err)
+	if ar.Status.RequestState == api.GrantedStatus {
+		err := s.handleExtensions(ctx, ar, rt)
+		if err != nil {
+			return "", fmt.Errorf("error handling extensions: %w", err)
+		}
 	}
-	if !resp.Allowed {
-		rtHash := RoleTemplateHash(rt)
-		err = s.updateStatus(ctx, ar, api.DeniedStatus, resp.Message, rtHash)
+
+	details := ""
+	actor := ""
+	var allowedUntil *time.Time
+	// approval and plugin are only verified if the access isn't granted yet
+	if ar.Status.RequestState != api.GrantedStatus {
+		err := s.verifySignature(ar)
+		if err == nil {
+			err = rt.ValidateDuration(ar.Spec.Duration.Duration)
+		}
+		if err == nil {
+			err = rt.ValidateJustification(ar.Spec.Justification, ar.Spec.TicketRef)
+		}
+		if err == nil && ar.Spec.BreakGlass {
+			err = rt.ValidateBreakGlass(ar.Spec.Duration.Duration)
+		}
 		if err != nil {
-			return "", fmt.Errorf("error updating access request status to denied: %w", err)
+			err = s.updateStatus(ctx, ar, api.InvalidStatus, err.Error(), RoleTemplateHash(rt))
+			if err != nil {
+				return "", fmt.Errorf("error updating access request status to invalid: %w", err)
+			}
+			return api.InvalidStatus, nil
+		}
+
+		authorized, err := s.isAuthorized(ctx, ar)
+		if err != nil {
+			return "", fmt.Errorf("error verifying access bindings: %w", err)
+		}
+		if !authorized {
+			details := fmt.Sprintf("No AccessBinding authorizes %s to request role %s", ar.Spec.Subject.Principal(), ar.Spec.Role.TemplateRef.Name)
+			err = s.updateStatus(ctx, ar, api.DeniedStatus, details, RoleTemplateHash(rt))
+			if err != nil {
+				return "", fmt.Errorf("error updating access request status to denied: %w", err)
+			}
+			return api.DeniedStatus, nil
+		}
+
+		if rt.RequiresApproval() && !ar.Spec.BreakGlass {
+			status, err := s.handleApproval(ctx, ar, rt)
+			if err != nil || status != "" {
+				return status, err
+			}
+			actor = ar.Spec.Approval.Approver
+			details = approvalDetails(ar.Spec.Approval)
+		}
+
+		if ar.IsScheduled() {
+			return s.handleScheduled(ctx, ar, rt, details, actor)
+		}
+
+		if rt.Spec.AccessWindows != nil {
+			status, until, err := s.handleAccessWindows(ctx, ar, rt)
+			if err != nil || status != "" {
+				return status, err
+			}
+			allowedUntil = until
+		}
+
+		if ar.Spec.BreakGlass {
+			details = "Break-glass access granted without approval and pending review"
+		} else {
+			status, pluginDetails, err := s.handlePlugin(ctx, ar, app, rt)
+			if err != nil || status != "" {
+				return status, err
+			}
+			if pluginDetails != "" {
+				details = pluginDetails
+			}
 		}
-		return api.DeniedStatus, nil
 	}
 
-	details := resp.Message
 	status, err := s.grantArgoCDAccess(ctx, ar, rt)
 	if err != nil {
 		details = fmt.Sprintf("Error granting Argo CD Access: %s", err)
 	}
 	// only update status if the current state is different
 	if ar.Status.RequestState != status {
+		if status == api.GrantedStatus && allowedUntil != nil {
+			ar.Status.ExpiresAt = &metav1.Time{Time: *allowedUntil}
+			truncated := fmt.Sprintf("Access truncated to %s by the role access windows", allowedUntil.Format(time.RFC3339))
+			if details != "" {
+				truncated = fmt.Sprintf("%s; %s", details, truncated)
+			}
+			details = truncated
+		}
+		if status == api.GrantedStatus && ar.Spec.BreakGlass {
+			err = s.labelBreakGlass(ctx, ar)
+			if err != nil {
+				return "", fmt.Errorf("error labeling break-glass access request: %w", err)
+			}
+		}
 		rtHash := RoleTemplateHash(rt)
-		err = s.updateStatus(ctx, ar, status, details, rtHash)
+		err = s.updateStatusWithActor(ctx, ar, status, details, actor, rtHash)
 		if err != nil {
 			return "", fmt.Errorf("error updating access request status to granted: %w", err)
 		}
+		if status == api.GrantedStatus && ar.Spec.BreakGlass {
+			s.recorder.Eventf(ar, corev1.EventTypeWarning, EventReasonBreakGlass,
+				"Break-glass access granted to %s in role %s: review required", ar.Spec.Subject.Principal(), ar.Spec.Role.TemplateRef.Name)
+		}
 	}
 	return status, nil
 }
 
-// handleAccessExpired will remove the Argo CD access for the subject and
-// update the AccessRequest status field.
-func (s *Service) handleAccessExpired(ctx context.Context, ar *api.AccessRequest, rt *api.RoleTemplate) error {
+// labelBreakGlass will make sure that the given break-glass ar has the
+// break-glass label and, if not reviewed yet, the pending review label.
+func (s *Service) labelBreakGlass(ctx context.Context, ar *api.AccessRequest) error {
+	labels := ar.GetLabels()
+	pendingReview := isPendingReview(s.Config, ar)
+	if labels[api.BreakGlassLabel] == "true" &&
+		(!pendingReview || labels[api.PendingReviewLabel] == "true") {
+		return nil
+	}
+	patch := client.MergeFrom(ar.DeepCopy())
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[api.BreakGlassLabel] = "true"
+	if pendingReview {
+		labels[api.PendingReviewLabel] = "true"
+	}
+	ar.SetLabels(labels)
+	return s.k8sClient.Patch(ctx, ar, patch)
+}
+
+// handleScheduled will move the given ar to ScheduledStatus if it isn't
+// already. The given details and actor are recorded in the history entry so
+// approvals given before the start time are preserved.
+func (s *Service) handleScheduled(ctx context.Context, ar *api.AccessRequest, rt *api.RoleTemplate, details, actor string) (api.Status, error) {
+	log.FromContext(ctx).Info("Access scheduled", "startsAt", ar.Spec.StartsAt.Format(time.RFC3339))
+	if ar.Status.RequestState == api.ScheduledStatus {
+		return api.ScheduledStatus, nil
+	}
+	if details == "" {
+		details = fmt.Sprintf("Access scheduled to start at %s", ar.Spec.StartsAt.Format(time.RFC3339))
+	}
+	err := s.updateStatusWithActor(ctx, ar, api.ScheduledStatus, details, actor, RoleTemplateHash(rt))
+	if err != nil {
+		return "", fmt.Errorf("error updating access request status to scheduled: %w", err)
+	}
+	return api.ScheduledStatus, nil
+}
+
+// handleAccessWindows will verify if the access can be granted now based on
+// the access windows defined in the given rt. It returns DeniedStatus if the
+// access is not allowed at this time. If the access has to end before the
+// requested duration, the time it must expire is returned.
+func (s *Service) handleAccessWindows(ctx context.Context, ar *api.AccessRequest, rt *api.RoleTemplate) (api.Status, *time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(ar.Spec.Duration.Duration)
+	until, err := rt.Spec.AccessWindows.AllowedUntil(now, expiresAt)
+	if err != nil {
+		status := api.InvalidStatus
+		if errors.Is(err, api.ErrOutsideAccessWindow) {
+			status = api.DeniedStatus
+		}
+		log.FromContext(ctx).Info("Access not allowed by the role access windows", "reason", err.Error())
+		err = s.updateStatus(ctx, ar, status, err.Error(), RoleTemplateHash(rt))
+		if err != nil {
+			return "", nil, fmt.Errorf("error updating access request status to %s: %w", status, err)
+		}
+		return status, nil, nil
+	}
+	if until.Before(expiresAt) {
+		return "", &until, nil
+	}
+	return "", nil, nil
+}
+
+// verifySignature will verify the signature added by the backend to the
+// given ar if required by the controller configuration.
+func (s *Service) verifySignature(ar *api.AccessRequest) error {
+	if s.Config == nil || !s.Config.ControllerRequireSignature() {
+		return nil
+	}
+	return ar.VerifySignature(s.Config.ControllerSigningKey())
+}
+
+// isPendingReview returns true if the given ar is a break-glass
+// AccessRequest that wasn't reviewed yet. If signatures are required by the
+// given cfg, reviews without a valid signature are not accounted for.
+func isPendingReview(cfg config.ControllerConfigurer, ar *api.AccessRequest) bool {
+	if ar.IsPendingReview() {
+		return true
+	}
+	if !ar.Spec.BreakGlass || cfg == nil || !cfg.ControllerRequireSignature() {
+		return false
+	}
+	return ar.VerifyReviewSignature(cfg.ControllerSigningKey()) != nil
+}
+
+// handlePlugin will invoke the configured plugin to verify if the access can
+// be granted. It returns RequestedStatus if the plugin response is pending
+// and DeniedStatus if the plugin denies the access. An empty status is
+// returned with the plugin message if the access can be granted.
+func (s *Service) handlePlugin(ctx context.Context, ar *api.AccessRequest, app *argocd.Application, rt *api.RoleTemplate) (api.Status, string, error) {
+	resp, err := s.PluginGrantAccess(ctx, ar, app)
+	if err != nil {
+		return "", "", fmt.Errorf("error verifying if subject is allowed: %w", err)
+	}
+	switch resp.Status {
+	case plugin.Granted:
+		return "", resp.Message, nil
+	case plugin.GrantPending:
+		log.FromContext(ctx).Info("Grant access pending", "message", resp.Message)
+		return api.RequestedStatus, "", nil
+	case plugin.Denied:
+		rtHash := RoleTemplateHash(rt)
+		err = s.updateStatus(ctx, ar, api.DeniedStatus, resp.Message, rtHash)
+		if err != nil {
+			return "", "", fmt.Errorf("error updating access request status to denied: %w", err)
+		}
+		return api.DeniedStatus, "", nil
+	default:
+		return "", "", fmt.Errorf("unsupported plugin grant status: %q", resp.Status)
+	}
+}
+
+// handleApproval will verify the approval decision in the given ar. It
+// returns RequestedStatus if the AccessRequest is still waiting for an
+// approval, InvalidStatus if signatures are required and the approval
+// wasn't signed by the backend and DeniedStatus if it was denied or
+// self-approved. An empty status is returned if the AccessRequest is
+// approved.
+func (s *Service) handleApproval(ctx context.Context, ar *api.AccessRequest, rt *api.RoleTemplate) (api.Status, error) {
+	logger := log.FromContext(ctx)
+	approval := ar.Spec.Approval
+	if approval == nil {
+		logger.Info("Waiting for approval")
+		return api.RequestedStatus, nil
+	}
+
+	rtHash := RoleTemplateHash(rt)
+	if s.Config != nil && s.Config.ControllerRequireSignature() {
+		err := ar.VerifyApprovalSignature(s.Config.ControllerSigningKey())
+		if err != nil {
+			err = s.updateStatus(ctx, ar, api.InvalidStatus, err.Error(), rtHash)
+			if err != nil {
+				return "", fmt.Errorf("error updating access request status to invalid: %w", err)
+			}
+			return api.InvalidStatus, nil
+		}
+	}
+	details := ""
+	switch {
+	case approval.Approver == ar.Spec.Subject.Username:
+		details = "Self-approval is not allowed"
+	case ar.Spec.Subject.Group != "" && slices.Contains(approval.ApproverGroups, ar.Spec.Subject.Group):
+		details = "Members of the elevated group are not allowed to approve"
+	}
+	if details != "" {
+		err := s.updateStatusWithActor(ctx, ar, api.DeniedStatus, details, approval.Approver, rtHash)
+		if err != nil {
+			return "", fmt.Errorf("error updating access request status to denied: %w", err)
+		}
+		return api.DeniedStatus, nil
+	}
+
+	if approval.Decision == api.DeniedDecision {
+		details := approvalDetails(approval)
+		err := s.updateStatusWithActor(ctx, ar, api.DeniedStatus, details, approval.Approver, rtHash)
+		if err != nil {
+			return "", fmt.Errorf("error updating access request status to denied: %w", err)
+		}
+		return api.DeniedStatus, nil
+	}
+	logger.Info("AccessRequest approved", "approver", approval.Approver)
+	return "", nil
+}
+
+// handleExtensions will move the given ar ExpiresAt forward for every
+// extension that wasn't applied yet. Extensions exceeding the maximum
+// duration or the access windows defined in the given rt are rejected, as
+// well as extensions not signed by the backend if signatures are required.
+// A history entry is recorded for each processed extension.
+func (s *Service) handleExtensions(ctx context.Context, ar *api.AccessRequest, rt *api.RoleTemplate) error {
+	if ar.Status.AppliedExtensions >= len(ar.Spec.Extensions) {
+		return nil
+	}
+	type extensionEvent struct {
+		eventType, reason, message string
+	}
+	logger := log.FromContext(ctx)
+	var signatureErr error
+	if s.Config != nil && s.Config.ControllerRequireSignature() {
+		signatureErr = ar.VerifyExtensionsSignature(s.Config.ControllerSigningKey())
+	}
+	events := []extensionEvent{}
+	for i := ar.Status.AppliedExtensions; i < len(ar.Spec.Extensions); i++ {
+		ext := ar.Spec.Extensions[i]
+		newExpiresAt := ar.Status.ExpiresAt.Add(ext.Duration.Duration)
+		event := extensionEvent{eventType: corev1.EventTypeWarning, reason: EventReasonExtensionRejected}
+		switch {
+		case signatureErr != nil:
+			event.message = fmt.Sprintf("Extension requested by %s rejected: %s", ext.Requester, signatureErr)
+		case ar.Spec.BreakGlass:
+			event.message = fmt.Sprintf("Extension requested by %s rejected: break-glass access can not be extended", ext.Requester)
+		case !rt.AllowsExtensions():
+			event.message = fmt.Sprintf("Extension requested by %s rejected: role does not allow extensions", ext.Requester)
+		case ar.GrantedDuration()+ext.Duration.Duration > rt.Spec.MaxExtendedDuration.Duration:
+			event.message = fmt.Sprintf("Extension requested by %s rejected: maximum extended duration of %s exceeded", ext.Requester, rt.Spec.MaxExtendedDuration.Duration)
+		case !allowedByAccessWindows(rt, newExpiresAt):
+			event.message = fmt.Sprintf("Extension requested by %s rejected: access would extend past the role access windows", ext.Requester)
+		default:
+			ar.Status.ExpiresAt = &metav1.Time{Time: newExpiresAt}
+			extended := ext.Duration.Duration
+			if ar.Status.ExtendedDuration != nil {
+				extended += ar.Status.ExtendedDuration.Duration
+			}
+			ar.Status.ExtendedDuration = &metav1.Duration{Duration: extended}
+			event = extensionEvent{
+				eventType: corev1.EventTypeNormal,
+				reason:    EventReasonExtended,
+				message:   fmt.Sprintf("Access extended by %s until %s", ext.Duration.Duration, newExpiresAt.Format(time.RFC3339)),
+			}
+		}
+		logger.Info("Processing extension", "details", event.message)
+		ar.UpdateStatusHistoryWithActor(api.GrantedStatus, event.message, ext.Requester)
+		ar.Status.AppliedExtensions++
+		events = append(events, event)
+	}
+	err := s.k8sClient.Status().Update(ctx, ar)
+	if err != nil {
+		return fmt.Errorf("error updating access request status: %w", err)
+	}
+	for _, e := range events {
+		s.recorder.Event(ar, e.eventType, e.reason, e.message)
+	}
+	return nil
+}
+
+// allowedByAccessWindows returns true if the access windows defined in the
+// given rt allow the access from now until the given expiresAt.
+func allowedByAccessWindows(rt *api.RoleTemplate, expiresAt time.Time) bool {
+	if rt.Spec.AccessWindows == nil {
+		return true
+	}
+	until, err := rt.Spec.AccessWindows.AllowedUntil(time.Now(), expiresAt)
+	return err == nil && !until.Before(expiresAt)
+}
+
+// approvalDetails will build the history details for the given approval.
+func approvalDetails(approval *api.Approval) string {
+	details := fmt.Sprintf("Access %s by %s", approval.Decision, approval.Approver)
+	if approval.Reason != "" {
+		details = fmt.Sprintf("%s: %s", details, approval.Reason)
+	}
+	return details
+}
+
+// handleAccessExpired will remove the Argo CD access for the subject,
+// invoke the plugin to revoke the access and update the AccessRequest
+// status field. If the plugin returns that revoking the access is still
+// pending, the AccessRequest status will not be changed so it can be
+// retried in the next reconciliation.
+func (s *Service) handleAccessExpired(ctx context.Context, ar *api.AccessRequest, app *argocd.Application, rt *api.RoleTemplate) (api.Status, error) {
 	err := s.RemoveArgoCDAccess(ctx, ar, rt)
 	if err != nil {
-		return fmt.Errorf("error removing access for expired request: %w", err)
+		return "", fmt.Errorf("error removing access for expired request: %w", err)
+	}
+	resp, err := s.PluginRevokeAccess(ctx, ar, app)
+	if err != nil {
+		return "", fmt.Errorf("error revoking access for expired request: %w", err)
+	}
+	if resp.Status == plugin.RevokePending {
+		log.FromContext(ctx).Info("Revoke access pending", "message", resp.Message)
+		return ar.Status.RequestState, nil
 	}
 	hash := RoleTemplateHash(rt)
-	err = s.updateStatus(ctx, ar, api.ExpiredStatus, "", hash)
+	err = s.updateStatus(ctx, ar, api.ExpiredStatus, resp.Message, hash)
 	if err != nil {
-		return fmt.Errorf("error updating access request status to expired: %w", err)
+		return "", fmt.Errorf("error updating access request status to expired: %w", err)
 	}
-	return nil
+	recordExpirationMetrics(ar)
+	return api.ExpiredStatus, nil
+}
+
+// HandleRevocation will remove the Argo CD access associated with the given
+// ar if it was granted and update its status to RevokedStatus. If the plugin
+// didn't conclude revoking the access yet, the current status is returned.
+func (s *Service) HandleRevocation(ctx context.Context, ar *api.AccessRequest, app *argocd.Application, rt *api.RoleTemplate) (api.Status, error) {
+	logger := log.FromContext(ctx)
+	revocation := ar.Spec.Revocation
+	logger.Info("AccessRequest revoked", "revoker", revocation.Revoker)
+	details := fmt.Sprintf("Access revoked by %s", revocation.Revoker)
+	if revocation.Reason != "" {
+		details = fmt.Sprintf("%s: %s", details, revocation.Reason)
+	}
+
+	// access is only removed if it was granted
+	if ar.Status.RequestState == api.GrantedStatus {
+		err := s.RemoveArgoCDAccess(ctx, ar, rt)
+		if err != nil {
+			return "", fmt.Errorf("error removing access for revoked request: %w", err)
+		}
+		resp, err := s.PluginRevokeAccess(ctx, ar, app)
+		if err != nil {
+			return "", fmt.Errorf("error revoking access for revoked request: %w", err)
+		}
+		if resp.Status == plugin.RevokePending {
+			logger.Info("Revoke access pending", "message", resp.Message)
+			return ar.Status.RequestState, nil
+		}
+	}
+
+	hash := RoleTemplateHash(rt)
+	err := s.updateStatusWithActor(ctx, ar, api.RevokedStatus, details, revocation.Revoker, hash)
+	if err != nil {
+		return "", fmt.Errorf("error updating access request status to revoked: %w", err)
+	}
+	return api.RevokedStatus, nil
 }
 
 // removeArgoCDAccess will remove the subject in the given AccessRequest from
 // the given ar.TargetRoleName from the Argo CD project referenced in the
 // ar.Spec.AppProject. The AppProject update will be executed via a patch with
-// optimistic lock enabled. It will retry in case of AppProject conflict is
-// identied.
+// optimistic lock enabled or server-side apply if configured. It will retry in
+// case of AppProject conflict is identied.
 func (s *Service) RemoveArgoCDAccess(ctx context.Context, ar *api.AccessRequest, rt *api.RoleTemplate) error {
 	logger := log.FromContext(ctx)
 	logger.Info("Removing Argo CD Access")
 	projName := ar.Status.TargetProject
 	projNamespace := ar.GetNamespace()
 
-	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		project, err := s.getProject(ctx, projName, projNamespace)
 		if err != nil {
 			e := fmt.Errorf("error getting Argo CD Project %s/%s: %w", projNamespace, projName, err)
 			return client.IgnoreNotFound(e)
 		}
-		patch := client.MergeFromWithOptions(project.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		original := project.DeepCopy()
 
 		logger.Debug("Removing subject from role")
 		removeSubjectFromRole(project, ar, rt)
@@ -139,20 +596,28 @@ func (s *Service) RemoveArgoCDAccess(ctx context.Context, ar *api.AccessRequest,
 		updateProjectPolicies(project, ar, rt)
 
 		logger.Debug("Patching AppProject")
-		opts := []client.PatchOption{client.FieldOwner(FieldOwnerEphemeralAccess)}
-		err = s.k8sClient.Patch(ctx, project, patch, opts...)
+		err = s.patchProject(ctx, ar, original, project)
 		if err != nil {
 			return fmt.Errorf("error patching Argo CD Project %s/%s: %w", projNamespace, projName, err)
 		}
+		ar.SetCondition(api.ConditionProjectPatched, metav1.ConditionTrue, EventReasonSubjectRemoved,
+			fmt.Sprintf("Subject removed from role %s in AppProject %s", ar.Status.RoleName, projName))
+		s.recorder.Eventf(project, corev1.EventTypeNormal, EventReasonSubjectRemoved,
+			"Subject %s removed from role %s by AccessRequest %s/%s",
+			ar.Spec.Subject.Principal(), ar.Status.RoleName, ar.GetNamespace(), ar.GetName())
 		return nil
 	})
+	if apierrors.IsConflict(err) {
+		appProjectPatchRetriesExhaustedTotal.WithLabelValues(projName).Inc()
+	}
+	return err
 }
 
 // grantArgoCDAccess will associate the given AccessRequest subject in the
 // Argo CD AppProject specified in the ar.Spec.AppProject in the role defined
 // in ar.TargetRoleName. The AppProject update will be executed via a patch with
-// optimistic lock enabled. It Will retry in case of AppProject conflict is
-// identied.
+// optimistic lock enabled or server-side apply if configured. It Will retry in
+// case of AppProject conflict is identied.
 func (s *Service) grantArgoCDAccess(ctx context.Context, ar *api.AccessRequest, rt *api.RoleTemplate) (api.Status, error) {
 	logger := log.FromContext(ctx)
 	logger.Info("Granting Argo CD Access")
@@ -165,7 +630,7 @@ func (s *Service) grantArgoCDAccess(ctx context.Context, ar *api.AccessRequest,
 		if err != nil {
 			return fmt.Errorf("error getting Argo CD Project %s/%s: %w", projNamespace, projName, err)
 		}
-		patch := client.MergeFromWithOptions(project.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		original := project.DeepCopy()
 
 		logger.Debug("Adding subject in role")
 		addSubjectInRole(project, ar, rt)
@@ -175,20 +640,139 @@ func (s *Service) grantArgoCDAccess(ctx context.Context, ar *api.AccessRequest,
 		updateProjectPolicies(project, ar, rt)
 
 		logger.Debug("Patching AppProject")
-		opts := []client.PatchOption{client.FieldOwner("ephemeral-access-controller")}
-		err = s.k8sClient.Patch(ctx, project, patch, opts...)
+		err = s.patchProject(ctx, ar, original, project)
 		if err != nil {
 			return fmt.Errorf("error patching Argo CD Project %s/%s: %w", projNamespace, projName, err)
 		}
-
+		ar.SetCondition(api.ConditionProjectPatched, metav1.ConditionTrue, EventReasonSubjectAdded,
+			fmt.Sprintf("Subject added in role %s in AppProject %s", ar.Status.RoleName, projName))
+		// only record the event when the access is first granted as this
+		// function is invoked in every reconciliation to keep the role in sync
+		if ar.Status.RequestState != api.GrantedStatus {
+			s.recorder.Eventf(project, corev1.EventTypeNormal, EventReasonSubjectAdded,
+				"Subject %s added to role %s by AccessRequest %s/%s",
+				ar.Spec.Subject.Principal(), ar.Status.RoleName, ar.GetNamespace(), ar.GetName())
+		}
 		return nil
 	})
 	if err != nil {
+		if apierrors.IsConflict(err) {
+			appProjectPatchRetriesExhaustedTotal.WithLabelValues(projName).Inc()
+		}
 		return api.DeniedStatus, err
 	}
 	return api.GrantedStatus, nil
 }
 
+// patchProject will persist the changes made in the given project. The
+// ProjectPatched condition is set as false in the given ar if the patch
+// fails.
+func (s *Service) patchProject(ctx context.Context, ar *api.AccessRequest, original, project *argocd.AppProject) error {
+	serverSideApply := s.Config != nil && s.Config.ControllerServerSideApply()
+	err := patchProject(ctx, s.k8sClient, serverSideApply, original, project)
+	if err != nil {
+		ar.SetCondition(api.ConditionProjectPatched, metav1.ConditionFalse, ConditionReasonPatchFailed, err.Error())
+	}
+	return err
+}
+
+// patchProject will persist the changes made in the given project and
+// increment the conflict metric if the patch is rejected due to a conflict.
+// All AppProject updates done by the controller must use this function. If
+// serverSideApply is true, only the ephemeral roles are sent to the API
+// server. Otherwise a merge patch with optimistic lock is created from the
+// given original project.
+func patchProject(ctx context.Context, c K8sClient, serverSideApply bool, original, project *argocd.AppProject) error {
+	var err error
+	if serverSideApply {
+		var applyConfig *unstructured.Unstructured
+		applyConfig, err = projectApplyConfiguration(project)
+		if err != nil {
+			return fmt.Errorf("error building AppProject apply configuration: %w", err)
+		}
+		err = c.Patch(ctx, applyConfig, client.Apply, client.FieldOwner(FieldOwnerEphemeralAccess), client.ForceOwnership)
+	} else {
+		patch := client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{})
+		err = c.Patch(ctx, project, patch, client.FieldOwner(FieldOwnerEphemeralAccess))
+	}
+	if apierrors.IsConflict(err) {
+		appProjectPatchConflictsTotal.WithLabelValues(project.GetName()).Inc()
+	}
+	return err
+}
+
+// VerifyServerSideApply will verify if the AppProject CRD installed in the
+// cluster declares spec.roles as a list map keyed by name. Server-side apply
+// must not be enabled otherwise as applying the ephemeral roles would remove
+// all other roles from the AppProjects.
+func VerifyServerSideApply(ctx context.Context, c client.Reader) error {
+	crd := &unstructured.Unstructured{}
+	crd.SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   "apiextensions.k8s.io",
+		Version: "v1",
+		Kind:    "CustomResourceDefinition",
+	})
+	err := c.Get(ctx, client.ObjectKey{Name: appProjectCRDName}, crd)
+	if err != nil {
+		return fmt.Errorf("error getting CRD %s: %w", appProjectCRDName, err)
+	}
+	versions, _, err := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	if err != nil {
+		return fmt.Errorf("error reading CRD %s versions: %w", appProjectCRDName, err)
+	}
+	for _, v := range versions {
+		version, ok := v.(map[string]interface{})
+		if !ok || version["name"] != argocd.GroupVersion.Version {
+			continue
+		}
+		rolesPath := []string{"schema", "openAPIV3Schema", "properties", "spec", "properties", "roles"}
+		listType, _, _ := unstructured.NestedString(version, append(rolesPath, "x-kubernetes-list-type")...)
+		keys, _, _ := unstructured.NestedStringSlice(version, append(rolesPath, "x-kubernetes-list-map-keys")...)
+		if listType != "map" || !slices.Contains(keys, "name") {
+			if listType == "" {
+				listType = "atomic"
+			}
+			return fmt.Errorf("CRD %s declares spec.roles with list type %s: server-side apply requires list type map keyed by name", appProjectCRDName, listType)
+		}
+		return nil
+	}
+	return fmt.Errorf("CRD %s does not define version %s", appProjectCRDName, argocd.GroupVersion.Version)
+}
+
+// projectApplyConfiguration builds the server-side apply configuration for
+// the given project. It only contains the ephemeral roles so the controller
+// doesn't claim ownership of roles managed by Argo CD or GitOps tools. The
+// groups and jwtTokens fields are always sent, even if empty, so subjects and
+// tokens removed from the role are also removed in the AppProject.
+func projectApplyConfiguration(project *argocd.AppProject) (*unstructured.Unstructured, error) {
+	roles := []interface{}{}
+	for _, role := range project.Spec.Roles {
+		if !strings.HasPrefix(role.Name, api.AppProjectRolePrefix) {
+			continue
+		}
+		r, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&role)
+		if err != nil {
+			return nil, fmt.Errorf("error converting role %s: %w", role.Name, err)
+		}
+		groups := []interface{}{}
+		for _, group := range role.Groups {
+			groups = append(groups, group)
+		}
+		r["groups"] = groups
+		r["jwtTokens"] = []interface{}{}
+		roles = append(roles, r)
+	}
+	applyConfig := &unstructured.Unstructured{}
+	applyConfig.SetGroupVersionKind(argocd.GroupVersion.WithKind("AppProject"))
+	applyConfig.SetName(project.GetName())
+	applyConfig.SetNamespace(project.GetNamespace())
+	err := unstructured.SetNestedSlice(applyConfig.Object, roles, "spec", "roles")
+	if err != nil {
+		return nil, err
+	}
+	return applyConfig, nil
+}
+
 // RoleTemplateHash will generate a hash for the given role template
 // based only on the necessary fields to require an update in the AppProject
 // role
@@ -239,30 +823,50 @@ func (s *Service) updateStatusWithRetry(ctx context.Context, ar *api.AccessReque
 // updateStatus will update the given AccessRequest status field with the
 // given status and details.
 func (s *Service) updateStatus(ctx context.Context, ar *api.AccessRequest, status api.Status, details string, rtHash string) error {
+	return s.updateStatusWithActor(ctx, ar, status, details, "", rtHash)
+}
+
+// updateStatusWithActor will update the given AccessRequest status field with
+// the given status, details and the actor responsible for the transition.
+func (s *Service) updateStatusWithActor(ctx context.Context, ar *api.AccessRequest, status api.Status, details, actor string, rtHash string) error {
 	// if it is already updated skip
 	if ar.Status.RequestState == status && ar.Status.RoleTemplateHash == rtHash {
 		return nil
 	}
-	ar.UpdateStatusHistory(status, details)
+	statusChanged := ar.Status.RequestState != status
+	ar.UpdateStatusHistoryWithActor(status, details, actor)
 	ar.Status.RoleTemplateHash = rtHash
-	return s.k8sClient.Status().Update(ctx, ar)
+	err := s.k8sClient.Status().Update(ctx, ar)
+	if err != nil {
+		return err
+	}
+	if statusChanged {
+		recordStatusEvent(s.recorder, ar, status, details)
+	}
+	return nil
+}
+
+// appProjectRoleName returns the name of the AppProject role managed for
+// the given ar. Project-scoped AccessRequests share a single role per
+// RoleTemplate in the AppProject.
+func appProjectRoleName(ar *api.AccessRequest, rt *api.RoleTemplate) string {
+	if ar.IsProjectScoped() {
+		return rt.ProjectRoleName()
+	}
+	return rt.AppProjectRoleName(ar.Spec.Application.Name, ar.Spec.Application.Namespace)
 }
 
 // removeSubjectFromRole will iterate over the roles in the given project and
-// remove the subject from the given AccessRequest from the role specified in
-// the ar.TargetRoleName.
+// remove the subject principal (username or group) from the given
+// AccessRequest from the role specified in the ar.TargetRoleName.
 func removeSubjectFromRole(project *argocd.AppProject, ar *api.AccessRequest, rt *api.RoleTemplate) {
-	roleName := rt.AppProjectRoleName(ar.Spec.Application.Name, ar.Spec.Application.Namespace)
+	roleName := appProjectRoleName(ar, rt)
+	principal := ar.Spec.Subject.Principal()
 	for idx, role := range project.Spec.Roles {
 		if role.Name == roleName {
 			groups := []string{}
 			for _, group := range role.Groups {
-				remove := false
-				if group == ar.Spec.Subject.Username {
-					remove = true
-					break
-				}
-				if !remove {
+				if group != principal {
 					groups = append(groups, group)
 				}
 			}
@@ -279,7 +883,7 @@ func updateProjectPolicies(project *argocd.AppProject, ar *api.AccessRequest, rt
 	if rt == nil {
 		return
 	}
-	roleName := rt.AppProjectRoleName(ar.Spec.Application.Name, ar.Spec.Application.Namespace)
+	roleName := appProjectRoleName(ar, rt)
 	for idx, role := range project.Spec.Roles {
 		if role.Name == roleName {
 			project.Spec.Roles[idx].Description = rt.Spec.Description
@@ -289,23 +893,24 @@ func updateProjectPolicies(project *argocd.AppProject, ar *api.AccessRequest, rt
 	}
 }
 
-// addSubjectInRole will associate the given AccessRequest subject in the
-// specific role in the given project.
+// addSubjectInRole will associate the given AccessRequest subject principal
+// (username or group) in the specific role in the given project.
 func addSubjectInRole(project *argocd.AppProject, ar *api.AccessRequest, rt *api.RoleTemplate) {
 	roleFound := false
-	roleName := rt.AppProjectRoleName(ar.Spec.Application.Name, ar.Spec.Application.Namespace)
+	roleName := appProjectRoleName(ar, rt)
+	principal := ar.Spec.Subject.Principal()
 	for idx, role := range project.Spec.Roles {
 		if role.Name == roleName {
 			roleFound = true
 			hasAccess := false
 			for _, group := range role.Groups {
-				if group == ar.Spec.Subject.Username {
+				if group == principal {
 					hasAccess = true
 					break
 				}
 			}
 			if !hasAccess {
-				project.Spec.Roles[idx].Groups = append(project.Spec.Roles[idx].Groups, ar.Spec.Subject.Username)
+				project.Spec.Roles[idx].Groups = append(project.Spec.Roles[idx].Groups, principal)
 			}
 		}
 	}
@@ -317,9 +922,9 @@ func addSubjectInRole(project *argocd.AppProject, ar *api.AccessRequest, rt *api
 // addRoleInProject will initialize the role owned by the ephemeral-access
 // controller and associate it in the given project.
 func addRoleInProject(project *argocd.AppProject, ar *api.AccessRequest, rt *api.RoleTemplate) {
-	groups := []string{ar.Spec.Subject.Username}
+	groups := []string{ar.Spec.Subject.Principal()}
 	role := argocd.ProjectRole{
-		Name:        rt.AppProjectRoleName(ar.Spec.Application.Name, ar.Spec.Application.Namespace),
+		Name:        appProjectRoleName(ar, rt),
 		Description: rt.Spec.Description,
 		Policies:    rt.Spec.Policies,
 		Groups:      groups,
@@ -327,17 +932,38 @@ func addRoleInProject(project *argocd.AppProject, ar *api.AccessRequest, rt *api
 	project.Spec.Roles = append(project.Spec.Roles, role)
 }
 
-// AllowedResponse defines the response that will be returned by permission
-// verifier plugins.
-type AllowedResponse struct {
-	Allowed bool
-	Message string
+// PluginGrantAccess will invoke the configured AccessRequester plugin to
+// verify if the subject in the given ar is allowed to be granted access.
+// If no plugin is configured, the access is always granted.
+func (s *Service) PluginGrantAccess(ctx context.Context, ar *api.AccessRequest, app *argocd.Application) (*plugin.GrantResponse, error) {
+	if s.plugin == nil {
+		return &plugin.GrantResponse{Status: plugin.Granted}, nil
+	}
+	log.FromContext(ctx).Debug("Invoking plugin to grant access")
+	resp, err := s.plugin.GrantAccess(ar, app)
+	if err != nil {
+		return nil, fmt.Errorf("plugin GrantAccess error: %w", err)
+	}
+	if resp == nil {
+		return nil, fmt.Errorf("plugin GrantAccess error: nil response")
+	}
+	return resp, nil
 }
 
-// TODO
-// 0. implement the plugin system
-// 1. verify if user is sudoer
-// 2. verify if CR is approved
-func Allowed(ctx context.Context, ar *api.AccessRequest, app *argocd.Application) (AllowedResponse, error) {
-	return AllowedResponse{Allowed: true, Message: ""}, nil
+// PluginRevokeAccess will invoke the configured AccessRequester plugin to
+// revoke the access of the subject in the given ar. If no plugin is
+// configured, the access is always revoked.
+func (s *Service) PluginRevokeAccess(ctx context.Context, ar *api.AccessRequest, app *argocd.Application) (*plugin.RevokeResponse, error) {
+	if s.plugin == nil {
+		return &plugin.RevokeResponse{Status: plugin.Revoked}, nil
+	}
+	log.FromContext(ctx).Debug("Invoking plugin to revoke access")
+	resp, err := s.plugin.RevokeAccess(ar, app)
+	if err != nil {
+		return nil, fmt.Errorf("plugin RevokeAccess error: %w", err)
+	}
+	if resp == nil {
+		return nil, fmt.Errorf("plugin RevokeAccess error: nil response")
+	}
+	return resp, nil
 }
@@ -9,12 +9,16 @@ import (
 	argocd "github.com/argoproj-labs/ephemeral-access/api/argoproj/v1alpha1"
 	api "github.com/argoproj-labs/ephemeral-access/api/ephemeral-access/v1alpha1"
 	"github.com/argoproj-labs/ephemeral-access/internal/controller"
+	"github.com/argoproj-labs/ephemeral-access/pkg/plugin"
 	"github.com/argoproj-labs/ephemeral-access/test/mocks"
 	"github.com/argoproj-labs/ephemeral-access/test/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -27,7 +31,7 @@ func TestHandlePermission(t *testing.T) {
 			clientMock.EXPECT().
 				Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.AppProject")).
 				Return(expectedError)
-			svc := controller.NewService(clientMock, nil)
+			svc := controller.NewService(clientMock, nil, nil, record.NewFakeRecorder(10))
 			ar := utils.NewAccessRequest("test", "default", "someApp", "someAppNs", "someRole", "someRoleNs", "")
 			past := &metav1.Time{
 				Time: time.Now().Add(time.Minute * -1),
@@ -60,7 +64,7 @@ func TestHandlePermission(t *testing.T) {
 				Patch(mock.Anything, mock.AnythingOfType("*v1alpha1.AppProject"), mock.Anything, mock.Anything).
 				Return(expectedError).
 				Once()
-			svc := controller.NewService(clientMock, nil)
+			svc := controller.NewService(clientMock, nil, nil, record.NewFakeRecorder(10))
 			ar := utils.NewAccessRequest("test", "default", "someApp", "someAppNs", "someRole", "someRoleNs", "")
 			past := &metav1.Time{
 				Time: time.Now().Add(time.Minute * -1),
@@ -86,4 +90,1679 @@ func TestHandlePermission(t *testing.T) {
 			assert.Equal(t, "", string(status))
 		})
 	})
+	t.Run("will handle plugin responses", func(t *testing.T) {
+		newRoleTemplate := func() *api.RoleTemplate {
+			return &api.RoleTemplate{
+				Spec: api.RoleTemplateSpec{
+					Name:        "some-role",
+					Description: "some-role-description",
+					Policies:    []string{"some-policy"},
+				},
+			}
+		}
+		newAccessRequest := func() *api.AccessRequest {
+			ar := utils.NewAccessRequest("test", "default", "someApp", "someAppNs", "someRole", "someRoleNs", "some-user")
+			ar.Spec.Duration = metav1.Duration{Duration: time.Minute}
+			ar.Status.TargetProject = "someProject"
+			ar.UpdateStatusHistory(api.RequestedStatus, "")
+			return ar
+		}
+		t.Run("will grant access if plugin returns granted", func(t *testing.T) {
+			// Given
+			clientMock := mocks.NewMockK8sClient(t)
+			statusMock := mocks.NewMockSubResourceWriter(t)
+			pluginMock := mocks.NewMockAccessRequester(t)
+			ar := newAccessRequest()
+			app := &argocd.Application{}
+			pluginMock.EXPECT().GrantAccess(ar, app).
+				Return(&plugin.GrantResponse{Status: plugin.Granted, Message: "approved"}, nil).
+				Once()
+			clientMock.EXPECT().
+				Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.AppProject")).
+				Return(nil).
+				Once()
+			clientMock.EXPECT().
+				Patch(mock.Anything, mock.AnythingOfType("*v1alpha1.AppProject"), mock.Anything, mock.Anything).
+				Return(nil).
+				Once()
+			clientMock.EXPECT().Status().Return(statusMock).Once()
+			statusMock.EXPECT().Update(mock.Anything, ar).Return(nil).Once()
+			recorder := record.NewFakeRecorder(10)
+			expectAuthorized(clientMock, ar)
+			svc := controller.NewService(clientMock, nil, pluginMock, recorder)
+
+			// When
+			status, err := svc.HandlePermission(context.Background(), ar, app, newRoleTemplate())
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, api.GrantedStatus, status)
+			assert.Equal(t, api.GrantedStatus, ar.Status.RequestState)
+			assert.NotNil(t, ar.Status.ExpiresAt)
+			assert.Equal(t, "approved", *ar.Status.History[len(ar.Status.History)-1].Details)
+			require.Len(t, recorder.Events, 2)
+			assert.Contains(t, <-recorder.Events, "Normal SubjectAdded")
+			assert.Equal(t, "Normal Granted AccessRequest granted: approved", <-recorder.Events)
+		})
+		t.Run("will keep the request pending if plugin returns grant pending", func(t *testing.T) {
+			// Given
+			clientMock := mocks.NewMockK8sClient(t)
+			pluginMock := mocks.NewMockAccessRequester(t)
+			ar := newAccessRequest()
+			app := &argocd.Application{}
+			pluginMock.EXPECT().GrantAccess(ar, app).
+				Return(&plugin.GrantResponse{Status: plugin.GrantPending, Message: "waiting"}, nil).
+				Once()
+			expectAuthorized(clientMock, ar)
+			svc := controller.NewService(clientMock, nil, pluginMock, record.NewFakeRecorder(10))
+
+			// When
+			status, err := svc.HandlePermission(context.Background(), ar, app, newRoleTemplate())
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, api.RequestedStatus, status)
+			assert.Equal(t, api.RequestedStatus, ar.Status.RequestState)
+			assert.Nil(t, ar.Status.ExpiresAt)
+		})
+		t.Run("will deny the request if plugin returns denied", func(t *testing.T) {
+			// Given
+			clientMock := mocks.NewMockK8sClient(t)
+			statusMock := mocks.NewMockSubResourceWriter(t)
+			pluginMock := mocks.NewMockAccessRequester(t)
+			ar := newAccessRequest()
+			app := &argocd.Application{}
+			pluginMock.EXPECT().GrantAccess(ar, app).
+				Return(&plugin.GrantResponse{Status: plugin.Denied, Message: "not allowed"}, nil).
+				Once()
+			clientMock.EXPECT().Status().Return(statusMock).Once()
+			statusMock.EXPECT().Update(mock.Anything, ar).Return(nil).Once()
+			recorder := record.NewFakeRecorder(10)
+			expectAuthorized(clientMock, ar)
+			svc := controller.NewService(clientMock, nil, pluginMock, recorder)
+
+			// When
+			status, err := svc.HandlePermission(context.Background(), ar, app, newRoleTemplate())
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, api.DeniedStatus, status)
+			assert.Equal(t, api.DeniedStatus, ar.Status.RequestState)
+			assert.Equal(t, "not allowed", *ar.Status.History[len(ar.Status.History)-1].Details)
+			require.Len(t, recorder.Events, 1)
+			assert.Equal(t, "Warning Denied AccessRequest denied: not allowed", <-recorder.Events)
+		})
+		t.Run("will return error if plugin returns error", func(t *testing.T) {
+			// Given
+			clientMock := mocks.NewMockK8sClient(t)
+			pluginMock := mocks.NewMockAccessRequester(t)
+			ar := newAccessRequest()
+			app := &argocd.Application{}
+			pluginMock.EXPECT().GrantAccess(ar, app).
+				Return(nil, errors.New("plugin error")).
+				Once()
+			expectAuthorized(clientMock, ar)
+			svc := controller.NewService(clientMock, nil, pluginMock, record.NewFakeRecorder(10))
+
+			// When
+			status, err := svc.HandlePermission(context.Background(), ar, app, newRoleTemplate())
+
+			// Then
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "plugin error")
+			assert.Equal(t, "", string(status))
+		})
+		t.Run("will not invoke the plugin if access is already granted", func(t *testing.T) {
+			// Given
+			clientMock := mocks.NewMockK8sClient(t)
+			pluginMock := mocks.NewMockAccessRequester(t)
+			ar := newAccessRequest()
+			ar.UpdateStatusHistory(api.GrantedStatus, "")
+			app := &argocd.Application{}
+			clientMock.EXPECT().
+				Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.AppProject")).
+				Return(nil).
+				Once()
+			clientMock.EXPECT().
+				Patch(mock.Anything, mock.AnythingOfType("*v1alpha1.AppProject"), mock.Anything, mock.Anything).
+				Return(nil).
+				Once()
+			svc := controller.NewService(clientMock, nil, pluginMock, record.NewFakeRecorder(10))
+
+			// When
+			status, err := svc.HandlePermission(context.Background(), ar, app, newRoleTemplate())
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, api.GrantedStatus, status)
+		})
+		t.Run("will keep the request status if plugin returns revoke pending", func(t *testing.T) {
+			// Given
+			clientMock := mocks.NewMockK8sClient(t)
+			pluginMock := mocks.NewMockAccessRequester(t)
+			ar := newAccessRequest()
+			ar.UpdateStatusHistory(api.GrantedStatus, "")
+			ar.Status.ExpiresAt = &metav1.Time{Time: time.Now().Add(time.Minute * -1)}
+			app := &argocd.Application{}
+			clientMock.EXPECT().
+				Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.AppProject")).
+				Return(nil).
+				Once()
+			clientMock.EXPECT().
+				Patch(mock.Anything, mock.AnythingOfType("*v1alpha1.AppProject"), mock.Anything, mock.Anything).
+				Return(nil).
+				Once()
+			pluginMock.EXPECT().RevokeAccess(ar, app).
+				Return(&plugin.RevokeResponse{Status: plugin.RevokePending}, nil).
+				Once()
+			svc := controller.NewService(clientMock, nil, pluginMock, record.NewFakeRecorder(10))
+
+			// When
+			status, err := svc.HandlePermission(context.Background(), ar, app, newRoleTemplate())
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, api.GrantedStatus, status)
+			assert.Equal(t, api.GrantedStatus, ar.Status.RequestState)
+		})
+	})
+	t.Run("will handle manual approval", func(t *testing.T) {
+		newRoleTemplate := func() *api.RoleTemplate {
+			return &api.RoleTemplate{
+				Spec: api.RoleTemplateSpec{
+					Name:        "some-role",
+					Description: "some-role-description",
+					Policies:    []string{"some-policy"},
+					Approval: &api.ApprovalSpec{
+						Approvers: []string{"some-approver"},
+					},
+				},
+			}
+		}
+		newAccessRequest := func(approval *api.Approval) *api.AccessRequest {
+			ar := utils.NewAccessRequest("test", "default", "someApp", "someAppNs", "someRole", "someRoleNs", "some-user")
+			ar.Spec.Duration = metav1.Duration{Duration: time.Minute}
+			ar.Spec.Approval = approval
+			ar.Status.TargetProject = "someProject"
+			ar.UpdateStatusHistory(api.RequestedStatus, "")
+			return ar
+		}
+		t.Run("will keep the request pending if not approved yet", func(t *testing.T) {
+			// Given
+			clientMock := mocks.NewMockK8sClient(t)
+			pluginMock := mocks.NewMockAccessRequester(t)
+			ar := newAccessRequest(nil)
+			app := &argocd.Application{}
+			expectAuthorized(clientMock, ar)
+			svc := controller.NewService(clientMock, nil, pluginMock, record.NewFakeRecorder(10))
+
+			// When
+			status, err := svc.HandlePermission(context.Background(), ar, app, newRoleTemplate())
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, api.RequestedStatus, status)
+			assert.Equal(t, api.RequestedStatus, ar.Status.RequestState)
+			assert.Nil(t, ar.Status.ExpiresAt)
+		})
+		t.Run("will grant access and record the approver if approved", func(t *testing.T) {
+			// Given
+			clientMock := mocks.NewMockK8sClient(t)
+			statusMock := mocks.NewMockSubResourceWriter(t)
+			ar := newAccessRequest(&api.Approval{
+				Decision:  api.ApprovedDecision,
+				Approver:  "some-approver",
+				DecidedAt: metav1.Now(),
+			})
+			app := &argocd.Application{}
+			clientMock.EXPECT().
+				Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.AppProject")).
+				Return(nil).
+				Once()
+			clientMock.EXPECT().
+				Patch(mock.Anything, mock.AnythingOfType("*v1alpha1.AppProject"), mock.Anything, mock.Anything).
+				Return(nil).
+				Once()
+			clientMock.EXPECT().Status().Return(statusMock).Once()
+			statusMock.EXPECT().Update(mock.Anything, ar).Return(nil).Once()
+			expectAuthorized(clientMock, ar)
+			svc := controller.NewService(clientMock, nil, nil, record.NewFakeRecorder(10))
+
+			// When
+			status, err := svc.HandlePermission(context.Background(), ar, app, newRoleTemplate())
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, api.GrantedStatus, status)
+			history := ar.Status.History[len(ar.Status.History)-1]
+			assert.Equal(t, api.GrantedStatus, history.RequestState)
+			assert.Equal(t, "some-approver", *history.Actor)
+			assert.Equal(t, "Access approved by some-approver", *history.Details)
+		})
+		t.Run("will deny the request if approval decision is denied", func(t *testing.T) {
+			// Given
+			clientMock := mocks.NewMockK8sClient(t)
+			statusMock := mocks.NewMockSubResourceWriter(t)
+			pluginMock := mocks.NewMockAccessRequester(t)
+			ar := newAccessRequest(&api.Approval{
+				Decision:  api.DeniedDecision,
+				Approver:  "some-approver",
+				Reason:    "not needed",
+				DecidedAt: metav1.Now(),
+			})
+			app := &argocd.Application{}
+			clientMock.EXPECT().Status().Return(statusMock).Once()
+			statusMock.EXPECT().Update(mock.Anything, ar).Return(nil).Once()
+			expectAuthorized(clientMock, ar)
+			svc := controller.NewService(clientMock, nil, pluginMock, record.NewFakeRecorder(10))
+
+			// When
+			status, err := svc.HandlePermission(context.Background(), ar, app, newRoleTemplate())
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, api.DeniedStatus, status)
+			history := ar.Status.History[len(ar.Status.History)-1]
+			assert.Equal(t, api.DeniedStatus, history.RequestState)
+			assert.Equal(t, "some-approver", *history.Actor)
+			assert.Equal(t, "Access denied by some-approver: not needed", *history.Details)
+		})
+		t.Run("will deny the request if self-approved", func(t *testing.T) {
+			// Given
+			clientMock := mocks.NewMockK8sClient(t)
+			statusMock := mocks.NewMockSubResourceWriter(t)
+			pluginMock := mocks.NewMockAccessRequester(t)
+			ar := newAccessRequest(&api.Approval{
+				Decision:  api.ApprovedDecision,
+				Approver:  "some-user",
+				DecidedAt: metav1.Now(),
+			})
+			app := &argocd.Application{}
+			clientMock.EXPECT().Status().Return(statusMock).Once()
+			statusMock.EXPECT().Update(mock.Anything, ar).Return(nil).Once()
+			expectAuthorized(clientMock, ar)
+			svc := controller.NewService(clientMock, nil, pluginMock, record.NewFakeRecorder(10))
+
+			// When
+			status, err := svc.HandlePermission(context.Background(), ar, app, newRoleTemplate())
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, api.DeniedStatus, status)
+			assert.Equal(t, "Self-approval is not allowed", *ar.Status.History[len(ar.Status.History)-1].Details)
+		})
+		t.Run("will deny the request if approved by a member of the elevated group", func(t *testing.T) {
+			// Given
+			clientMock := mocks.NewMockK8sClient(t)
+			statusMock := mocks.NewMockSubResourceWriter(t)
+			pluginMock := mocks.NewMockAccessRequester(t)
+			ar := newAccessRequest(&api.Approval{
+				Decision:       api.ApprovedDecision,
+				Approver:       "another-user",
+				ApproverGroups: []string{"managers", "on-call"},
+				DecidedAt:      metav1.Now(),
+			})
+			ar.Spec.Subject.Group = "on-call"
+			app := &argocd.Application{}
+			clientMock.EXPECT().Status().Return(statusMock).Once()
+			statusMock.EXPECT().Update(mock.Anything, ar).Return(nil).Once()
+			expectAuthorized(clientMock, ar)
+			svc := controller.NewService(clientMock, nil, pluginMock, record.NewFakeRecorder(10))
+
+			// When
+			status, err := svc.HandlePermission(context.Background(), ar, app, newRoleTemplate())
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, api.DeniedStatus, status)
+			assert.Equal(t, "Members of the elevated group are not allowed to approve", *ar.Status.History[len(ar.Status.History)-1].Details)
+		})
+	})
+	t.Run("will verify signatures", func(t *testing.T) {
+		signingKey := []byte("some-secret")
+		rt := &api.RoleTemplate{
+			Spec: api.RoleTemplateSpec{
+				Name:        "some-role",
+				Description: "some-role-description",
+				Policies:    []string{"some-policy"},
+			},
+		}
+		newAccessRequest := func() *api.AccessRequest {
+			ar := utils.NewAccessRequest("test", "default", "someApp", "someAppNs", "someRole", "someRoleNs", "some-user")
+			ar.Spec.Duration = metav1.Duration{Duration: time.Minute}
+			ar.Spec.Subject.Groups = []string{"some-group"}
+			ar.Status.TargetProject = "someProject"
+			ar.UpdateStatusHistory(api.RequestedStatus, "")
+			return ar
+		}
+		newConfigMock := func(t *testing.T) *mocks.MockConfigurer {
+			configMock := mocks.NewMockConfigurer(t)
+			configMock.EXPECT().ControllerRequireSignature().Return(true)
+			configMock.EXPECT().ControllerSigningKey().Return(signingKey)
+			return configMock
+		}
+		t.Run("will invalidate the request if the signature is missing", func(t *testing.T) {
+			// Given
+			clientMock := mocks.NewMockK8sClient(t)
+			statusMock := mocks.NewMockSubResourceWriter(t)
+			ar := newAccessRequest()
+			clientMock.EXPECT().Status().Return(statusMock).Once()
+			statusMock.EXPECT().Update(mock.Anything, ar).Return(nil).Once()
+			svc := controller.NewService(clientMock, newConfigMock(t), nil, record.NewFakeRecorder(10))
+
+			// When
+			status, err := svc.HandlePermission(context.Background(), ar, &argocd.Application{}, rt)
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, api.InvalidStatus, status)
+			assert.Contains(t, *ar.Status.History[len(ar.Status.History)-1].Details, "missing")
+		})
+		t.Run("will invalidate the request if the spec was modified after signed", func(t *testing.T) {
+			// Given
+			clientMock := mocks.NewMockK8sClient(t)
+			statusMock := mocks.NewMockSubResourceWriter(t)
+			ar := newAccessRequest()
+			require.NoError(t, ar.Sign(signingKey))
+			ar.Spec.Subject.Groups = []string{"admins"}
+			clientMock.EXPECT().Status().Return(statusMock).Once()
+			statusMock.EXPECT().Update(mock.Anything, ar).Return(nil).Once()
+			svc := controller.NewService(clientMock, newConfigMock(t), nil, record.NewFakeRecorder(10))
+
+			// When
+			status, err := svc.HandlePermission(context.Background(), ar, &argocd.Application{}, rt)
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, api.InvalidStatus, status)
+			assert.Contains(t, *ar.Status.History[len(ar.Status.History)-1].Details, "signature does not match")
+		})
+		newApprovalRoleTemplate := func() *api.RoleTemplate {
+			approvalRT := rt.DeepCopy()
+			approvalRT.Spec.Approval = &api.ApprovalSpec{Approvers: []string{"some-approver"}}
+			return approvalRT
+		}
+		newApproval := func() *api.Approval {
+			return &api.Approval{
+				Decision:  api.ApprovedDecision,
+				Approver:  "some-approver",
+				DecidedAt: metav1.Now(),
+			}
+		}
+		t.Run("will invalidate the request if the approval is forged", func(t *testing.T) {
+			// Given
+			clientMock := mocks.NewMockK8sClient(t)
+			statusMock := mocks.NewMockSubResourceWriter(t)
+			ar := newAccessRequest()
+			expectAuthorized(clientMock, ar)
+			require.NoError(t, ar.Sign(signingKey))
+			ar.Spec.Approval = newApproval()
+			clientMock.EXPECT().Status().Return(statusMock).Once()
+			statusMock.EXPECT().Update(mock.Anything, ar).Return(nil).Once()
+			svc := controller.NewService(clientMock, newConfigMock(t), nil, record.NewFakeRecorder(10))
+
+			// When
+			status, err := svc.HandlePermission(context.Background(), ar, &argocd.Application{}, newApprovalRoleTemplate())
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, api.InvalidStatus, status)
+			assert.Contains(t, *ar.Status.History[len(ar.Status.History)-1].Details, "missing")
+		})
+		t.Run("will invalidate the request if the approval was modified after signed", func(t *testing.T) {
+			// Given
+			clientMock := mocks.NewMockK8sClient(t)
+			statusMock := mocks.NewMockSubResourceWriter(t)
+			ar := newAccessRequest()
+			expectAuthorized(clientMock, ar)
+			require.NoError(t, ar.Sign(signingKey))
+			ar.Spec.Approval = newApproval()
+			ar.Spec.Approval.Decision = api.DeniedDecision
+			require.NoError(t, ar.SignApproval(signingKey))
+			ar.Spec.Approval.Decision = api.ApprovedDecision
+			clientMock.EXPECT().Status().Return(statusMock).Once()
+			statusMock.EXPECT().Update(mock.Anything, ar).Return(nil).Once()
+			svc := controller.NewService(clientMock, newConfigMock(t), nil, record.NewFakeRecorder(10))
+
+			// When
+			status, err := svc.HandlePermission(context.Background(), ar, &argocd.Application{}, newApprovalRoleTemplate())
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, api.InvalidStatus, status)
+			assert.Contains(t, *ar.Status.History[len(ar.Status.History)-1].Details, "signature does not match the approval")
+		})
+		t.Run("will invalidate the request if the approval is replayed in a recreated request", func(t *testing.T) {
+			// Given
+			clientMock := mocks.NewMockK8sClient(t)
+			statusMock := mocks.NewMockSubResourceWriter(t)
+			ar := newAccessRequest()
+			ar.SetUID("deleted-uid")
+			expectAuthorized(clientMock, ar)
+			require.NoError(t, ar.Sign(signingKey))
+			ar.Spec.Approval = newApproval()
+			require.NoError(t, ar.SignApproval(signingKey))
+			ar.SetUID("recreated-uid")
+			clientMock.EXPECT().Status().Return(statusMock).Once()
+			statusMock.EXPECT().Update(mock.Anything, ar).Return(nil).Once()
+			svc := controller.NewService(clientMock, newConfigMock(t), nil, record.NewFakeRecorder(10))
+
+			// When
+			status, err := svc.HandlePermission(context.Background(), ar, &argocd.Application{}, newApprovalRoleTemplate())
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, api.InvalidStatus, status)
+			assert.Contains(t, *ar.Status.History[len(ar.Status.History)-1].Details, "signature does not match the approval")
+		})
+		t.Run("will invalidate the request if the spec is replayed under another name", func(t *testing.T) {
+			// Given
+			clientMock := mocks.NewMockK8sClient(t)
+			statusMock := mocks.NewMockSubResourceWriter(t)
+			ar := newAccessRequest()
+			require.NoError(t, ar.Sign(signingKey))
+			ar.SetName("replayed")
+			clientMock.EXPECT().Status().Return(statusMock).Once()
+			statusMock.EXPECT().Update(mock.Anything, ar).Return(nil).Once()
+			svc := controller.NewService(clientMock, newConfigMock(t), nil, record.NewFakeRecorder(10))
+
+			// When
+			status, err := svc.HandlePermission(context.Background(), ar, &argocd.Application{}, rt)
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, api.InvalidStatus, status)
+			assert.Contains(t, *ar.Status.History[len(ar.Status.History)-1].Details, "signature does not match")
+		})
+		t.Run("will grant access if the approval signature is valid", func(t *testing.T) {
+			// Given
+			clientMock := mocks.NewMockK8sClient(t)
+			statusMock := mocks.NewMockSubResourceWriter(t)
+			ar := newAccessRequest()
+			expectAuthorized(clientMock, ar)
+			require.NoError(t, ar.Sign(signingKey))
+			ar.Spec.Approval = newApproval()
+			require.NoError(t, ar.SignApproval(signingKey))
+			clientMock.EXPECT().
+				Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.AppProject")).
+				Return(nil).
+				Once()
+			clientMock.EXPECT().
+				Patch(mock.Anything, mock.AnythingOfType("*v1alpha1.AppProject"), mock.Anything, mock.Anything).
+				Return(nil).
+				Once()
+			clientMock.EXPECT().Status().Return(statusMock).Once()
+			statusMock.EXPECT().Update(mock.Anything, ar).Return(nil).Once()
+			configMock := newConfigMock(t)
+			configMock.EXPECT().ControllerServerSideApply().Return(false)
+			svc := controller.NewService(clientMock, configMock, nil, record.NewFakeRecorder(10))
+
+			// When
+			status, err := svc.HandlePermission(context.Background(), ar, &argocd.Application{}, newApprovalRoleTemplate())
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, api.GrantedStatus, status)
+		})
+		t.Run("will proceed evaluating the request if the signature is valid", func(t *testing.T) {
+			// Given
+			clientMock := mocks.NewMockK8sClient(t)
+			statusMock := mocks.NewMockSubResourceWriter(t)
+			ar := newAccessRequest()
+			require.NoError(t, ar.Sign(signingKey))
+			clientMock.EXPECT().
+				List(mock.Anything, mock.AnythingOfType("*v1alpha1.AccessBindingList"), client.InNamespace("someRoleNs")).
+				Return(nil).
+				Once()
+			clientMock.EXPECT().Status().Return(statusMock).Once()
+			statusMock.EXPECT().Update(mock.Anything, ar).Return(nil).Once()
+			svc := controller.NewService(clientMock, newConfigMock(t), nil, record.NewFakeRecorder(10))
+
+			// When
+			status, err := svc.HandlePermission(context.Background(), ar, &argocd.Application{}, rt)
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, api.DeniedStatus, status)
+			assert.Contains(t, *ar.Status.History[len(ar.Status.History)-1].Details, "No AccessBinding authorizes")
+		})
+	})
+	t.Run("will verify access bindings", func(t *testing.T) {
+		newRoleTemplate := func() *api.RoleTemplate {
+			return &api.RoleTemplate{
+				Spec: api.RoleTemplateSpec{
+					Name:        "some-role",
+					Description: "some-role-description",
+					Policies:    []string{"some-policy"},
+				},
+			}
+		}
+		newAccessRequest := func() *api.AccessRequest {
+			ar := utils.NewAccessRequest("test", "default", "someApp", "someAppNs", "someRole", "someRoleNs", "some-user")
+			ar.Spec.Duration = metav1.Duration{Duration: time.Minute}
+			ar.Spec.Subject.Groups = []string{"some-group"}
+			ar.Status.TargetProject = "someProject"
+			ar.UpdateStatusHistory(api.RequestedStatus, "")
+			return ar
+		}
+		expectBindings := func(clientMock *mocks.MockK8sClient, bindings ...api.AccessBinding) {
+			clientMock.EXPECT().
+				List(mock.Anything, mock.AnythingOfType("*v1alpha1.AccessBindingList"), client.InNamespace("someRoleNs")).
+				RunAndReturn(func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+					list.(*api.AccessBindingList).Items = bindings
+					return nil
+				}).
+				Once()
+		}
+		newBinding := func(role string, subjects ...string) api.AccessBinding {
+			return api.AccessBinding{
+				Spec: api.AccessBindingSpec{
+					RoleTemplateRef: api.RoleTemplateReference{Name: role},
+					Subjects:        subjects,
+				},
+			}
+		}
+		t.Run("will deny the request if no binding matches the recorded groups", func(t *testing.T) {
+			// Given
+			clientMock := mocks.NewMockK8sClient(t)
+			statusMock := mocks.NewMockSubResourceWriter(t)
+			pluginMock := mocks.NewMockAccessRequester(t)
+			ar := newAccessRequest()
+			expectBindings(clientMock, newBinding("someRole", "another-group"))
+			clientMock.EXPECT().
+				Get(mock.Anything, client.ObjectKey{Namespace: "someAppNs", Name: "someApp"}, mock.AnythingOfType("*unstructured.Unstructured")).
+				Return(nil).
+				Once()
+			clientMock.EXPECT().
+				Get(mock.Anything, client.ObjectKey{Namespace: "default", Name: "someProject"}, mock.AnythingOfType("*unstructured.Unstructured")).
+				Return(nil).
+				Once()
+			clientMock.EXPECT().Status().Return(statusMock).Once()
+			statusMock.EXPECT().Update(mock.Anything, ar).Return(nil).Once()
+			svc := controller.NewService(clientMock, nil, pluginMock, record.NewFakeRecorder(10))
+
+			// When
+			status, err := svc.HandlePermission(context.Background(), ar, &argocd.Application{}, newRoleTemplate())
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, api.DeniedStatus, status)
+			assert.Equal(t, "No AccessBinding authorizes some-user to request role someRole", *ar.Status.History[len(ar.Status.History)-1].Details)
+		})
+		t.Run("will invalidate forged group claims if signatures are required", func(t *testing.T) {
+			// Given
+			signingKey := []byte("some-secret")
+			clientMock := mocks.NewMockK8sClient(t)
+			statusMock := mocks.NewMockSubResourceWriter(t)
+			configMock := mocks.NewMockConfigurer(t)
+			configMock.EXPECT().ControllerRequireSignature().Return(true)
+			configMock.EXPECT().ControllerSigningKey().Return(signingKey)
+			ar := newAccessRequest()
+			require.NoError(t, ar.Sign(signingKey))
+			// group claim forged after the AccessRequest was signed by the backend
+			ar.Spec.Subject.Groups = []string{"some-group", "admins"}
+			clientMock.EXPECT().Status().Return(statusMock).Once()
+			statusMock.EXPECT().Update(mock.Anything, ar).Return(nil).Once()
+			svc := controller.NewService(clientMock, configMock, nil, record.NewFakeRecorder(10))
+
+			// When
+			status, err := svc.HandlePermission(context.Background(), ar, &argocd.Application{}, newRoleTemplate())
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, api.InvalidStatus, status)
+			assert.Contains(t, *ar.Status.History[len(ar.Status.History)-1].Details, "signature does not match")
+			clientMock.AssertNotCalled(t, "List", mock.Anything, mock.AnythingOfType("*v1alpha1.AccessBindingList"), mock.Anything)
+		})
+		t.Run("will deny the request if no binding references the role", func(t *testing.T) {
+			// Given
+			clientMock := mocks.NewMockK8sClient(t)
+			statusMock := mocks.NewMockSubResourceWriter(t)
+			ar := newAccessRequest()
+			expectBindings(clientMock, newBinding("anotherRole", "some-group"))
+			clientMock.EXPECT().Status().Return(statusMock).Once()
+			statusMock.EXPECT().Update(mock.Anything, ar).Return(nil).Once()
+			svc := controller.NewService(clientMock, nil, nil, record.NewFakeRecorder(10))
+
+			// When
+			status, err := svc.HandlePermission(context.Background(), ar, &argocd.Application{}, newRoleTemplate())
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, api.DeniedStatus, status)
+		})
+		t.Run("will not authorize a break-glass request with a regular binding", func(t *testing.T) {
+			// Given
+			clientMock := mocks.NewMockK8sClient(t)
+			statusMock := mocks.NewMockSubResourceWriter(t)
+			ar := newAccessRequest()
+			ar.Spec.BreakGlass = true
+			rt := newRoleTemplate()
+			rt.Spec.BreakGlass = &api.BreakGlassSpec{MaxDuration: metav1.Duration{Duration: time.Hour}, Reviewers: []string{"some-auditor"}}
+			expectBindings(clientMock, newBinding("someRole", "some-group"))
+			clientMock.EXPECT().
+				Get(mock.Anything, mock.Anything, mock.AnythingOfType("*unstructured.Unstructured")).
+				Return(nil)
+			clientMock.EXPECT().Status().Return(statusMock).Once()
+			statusMock.EXPECT().Update(mock.Anything, ar).Return(nil).Once()
+			svc := controller.NewService(clientMock, nil, nil, record.NewFakeRecorder(10))
+
+			// When
+			status, err := svc.HandlePermission(context.Background(), ar, &argocd.Application{}, rt)
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, api.DeniedStatus, status)
+		})
+		t.Run("will return error if fails to list bindings", func(t *testing.T) {
+			// Given
+			clientMock := mocks.NewMockK8sClient(t)
+			ar := newAccessRequest()
+			clientMock.EXPECT().
+				List(mock.Anything, mock.AnythingOfType("*v1alpha1.AccessBindingList"), mock.Anything).
+				Return(errors.New("some-error")).
+				Once()
+			svc := controller.NewService(clientMock, nil, nil, record.NewFakeRecorder(10))
+
+			// When
+			status, err := svc.HandlePermission(context.Background(), ar, &argocd.Application{}, newRoleTemplate())
+
+			// Then
+			assert.ErrorContains(t, err, "error verifying access bindings")
+			assert.Empty(t, status)
+		})
+	})
+	t.Run("will handle break-glass access", func(t *testing.T) {
+		newRoleTemplate := func() *api.RoleTemplate {
+			return &api.RoleTemplate{
+				Spec: api.RoleTemplateSpec{
+					Name:        "some-role",
+					Description: "some-role-description",
+					Policies:    []string{"some-policy"},
+					Approval: &api.ApprovalSpec{
+						Approvers: []string{"some-approver"},
+					},
+					BreakGlass: &api.BreakGlassSpec{
+						MaxDuration: metav1.Duration{Duration: time.Hour},
+						Reviewers:   []string{"some-auditor"},
+					},
+				},
+			}
+		}
+		newAccessRequest := func(duration time.Duration) *api.AccessRequest {
+			ar := utils.NewAccessRequest("test", "default", "someApp", "someAppNs", "someRole", "someRoleNs", "some-user")
+			ar.Spec.Duration = metav1.Duration{Duration: duration}
+			ar.Spec.BreakGlass = true
+			ar.Status.TargetProject = "someProject"
+			ar.UpdateStatusHistory(api.RequestedStatus, "")
+			return ar
+		}
+		t.Run("will grant access without approval and plugin", func(t *testing.T) {
+			// Given
+			clientMock := mocks.NewMockK8sClient(t)
+			statusMock := mocks.NewMockSubResourceWriter(t)
+			pluginMock := mocks.NewMockAccessRequester(t)
+			ar := newAccessRequest(time.Minute)
+			clientMock.EXPECT().
+				Patch(mock.Anything, ar, mock.Anything).
+				Return(nil).
+				Once()
+			clientMock.EXPECT().
+				Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.AppProject")).
+				Return(nil).
+				Once()
+			clientMock.EXPECT().
+				Patch(mock.Anything, mock.AnythingOfType("*v1alpha1.AppProject"), mock.Anything, mock.Anything).
+				Return(nil).
+				Once()
+			clientMock.EXPECT().Status().Return(statusMock).Once()
+			statusMock.EXPECT().Update(mock.Anything, ar).Return(nil).Once()
+			recorder := record.NewFakeRecorder(10)
+			expectAuthorized(clientMock, ar)
+			svc := controller.NewService(clientMock, nil, pluginMock, recorder)
+
+			// When
+			status, err := svc.HandlePermission(context.Background(), ar, &argocd.Application{}, newRoleTemplate())
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, api.GrantedStatus, status)
+			assert.Equal(t, "true", ar.GetLabels()[api.BreakGlassLabel])
+			assert.Equal(t, "true", ar.GetLabels()[api.PendingReviewLabel])
+			assert.Contains(t, *ar.Status.History[len(ar.Status.History)-1].Details, "Break-glass access granted")
+			close(recorder.Events)
+			events := []string{}
+			for e := range recorder.Events {
+				events = append(events, e)
+			}
+			require.NotEmpty(t, events)
+			assert.Contains(t, events[len(events)-1], "Warning BreakGlass Break-glass access granted to some-user")
+		})
+		t.Run("will invalidate the request if duration exceeds the break-glass maximum", func(t *testing.T) {
+			// Given
+			clientMock := mocks.NewMockK8sClient(t)
+			statusMock := mocks.NewMockSubResourceWriter(t)
+			ar := newAccessRequest(2 * time.Hour)
+			clientMock.EXPECT().Status().Return(statusMock).Once()
+			statusMock.EXPECT().Update(mock.Anything, ar).Return(nil).Once()
+			svc := controller.NewService(clientMock, nil, nil, record.NewFakeRecorder(10))
+
+			// When
+			status, err := svc.HandlePermission(context.Background(), ar, &argocd.Application{}, newRoleTemplate())
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, api.InvalidStatus, status)
+			assert.Contains(t, *ar.Status.History[len(ar.Status.History)-1].Details, "exceeds the break-glass maximum duration of 1h0m0s")
+		})
+		t.Run("will invalidate the request if the role does not allow break-glass", func(t *testing.T) {
+			// Given
+			clientMock := mocks.NewMockK8sClient(t)
+			statusMock := mocks.NewMockSubResourceWriter(t)
+			ar := newAccessRequest(time.Minute)
+			rt := newRoleTemplate()
+			rt.Spec.BreakGlass = nil
+			clientMock.EXPECT().Status().Return(statusMock).Once()
+			statusMock.EXPECT().Update(mock.Anything, ar).Return(nil).Once()
+			svc := controller.NewService(clientMock, nil, nil, record.NewFakeRecorder(10))
+
+			// When
+			status, err := svc.HandlePermission(context.Background(), ar, &argocd.Application{}, rt)
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, api.InvalidStatus, status)
+			assert.Contains(t, *ar.Status.History[len(ar.Status.History)-1].Details, "does not allow break-glass access")
+		})
+	})
+	t.Run("will handle scheduled access", func(t *testing.T) {
+		newRoleTemplate := func() *api.RoleTemplate {
+			return &api.RoleTemplate{
+				Spec: api.RoleTemplateSpec{
+					Name:        "some-role",
+					Description: "some-role-description",
+					Policies:    []string{"some-policy"},
+					MaxDuration: &metav1.Duration{Duration: time.Hour},
+				},
+			}
+		}
+		newAccessRequest := func(startsAt time.Time) *api.AccessRequest {
+			ar := utils.NewAccessRequest("test", "default", "someApp", "someAppNs", "someRole", "someRoleNs", "some-user")
+			ar.Spec.Duration = metav1.Duration{Duration: time.Minute}
+			ar.Spec.StartsAt = &metav1.Time{Time: startsAt}
+			ar.Status.TargetProject = "someProject"
+			ar.UpdateStatusHistory(api.RequestedStatus, "")
+			return ar
+		}
+		t.Run("will schedule the request if start time is in the future", func(t *testing.T) {
+			// Given
+			clientMock := mocks.NewMockK8sClient(t)
+			statusMock := mocks.NewMockSubResourceWriter(t)
+			pluginMock := mocks.NewMockAccessRequester(t)
+			ar := newAccessRequest(time.Now().Add(time.Hour))
+			clientMock.EXPECT().Status().Return(statusMock).Once()
+			statusMock.EXPECT().Update(mock.Anything, ar).Return(nil).Once()
+			recorder := record.NewFakeRecorder(10)
+			expectAuthorized(clientMock, ar)
+			svc := controller.NewService(clientMock, nil, pluginMock, recorder)
+
+			// When
+			status, err := svc.HandlePermission(context.Background(), ar, &argocd.Application{}, newRoleTemplate())
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, api.ScheduledStatus, status)
+			assert.Equal(t, api.ScheduledStatus, ar.Status.RequestState)
+			assert.Nil(t, ar.Status.ExpiresAt)
+			assert.Contains(t, <-recorder.Events, "Normal Scheduled AccessRequest scheduled")
+		})
+		t.Run("will not update the status if already scheduled", func(t *testing.T) {
+			// Given
+			clientMock := mocks.NewMockK8sClient(t)
+			pluginMock := mocks.NewMockAccessRequester(t)
+			ar := newAccessRequest(time.Now().Add(time.Hour))
+			ar.UpdateStatusHistory(api.ScheduledStatus, "")
+			expectAuthorized(clientMock, ar)
+			svc := controller.NewService(clientMock, nil, pluginMock, record.NewFakeRecorder(10))
+
+			// When
+			status, err := svc.HandlePermission(context.Background(), ar, &argocd.Application{}, newRoleTemplate())
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, api.ScheduledStatus, status)
+		})
+		t.Run("will grant access once the start time is reached", func(t *testing.T) {
+			// Given
+			clientMock := mocks.NewMockK8sClient(t)
+			statusMock := mocks.NewMockSubResourceWriter(t)
+			pluginMock := mocks.NewMockAccessRequester(t)
+			ar := newAccessRequest(time.Now().Add(-time.Second))
+			ar.UpdateStatusHistory(api.ScheduledStatus, "")
+			app := &argocd.Application{}
+			pluginMock.EXPECT().GrantAccess(ar, app).
+				Return(&plugin.GrantResponse{Status: plugin.Granted}, nil).
+				Once()
+			clientMock.EXPECT().
+				Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.AppProject")).
+				Return(nil).
+				Once()
+			clientMock.EXPECT().
+				Patch(mock.Anything, mock.AnythingOfType("*v1alpha1.AppProject"), mock.Anything, mock.Anything).
+				Return(nil).
+				Once()
+			clientMock.EXPECT().Status().Return(statusMock).Once()
+			statusMock.EXPECT().Update(mock.Anything, ar).Return(nil).Once()
+			expectAuthorized(clientMock, ar)
+			svc := controller.NewService(clientMock, nil, pluginMock, record.NewFakeRecorder(10))
+
+			// When
+			status, err := svc.HandlePermission(context.Background(), ar, app, newRoleTemplate())
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, api.GrantedStatus, status)
+			require.NotNil(t, ar.Status.ExpiresAt)
+			assert.WithinDuration(t, time.Now().Add(time.Minute), ar.Status.ExpiresAt.Time, 5*time.Second)
+		})
+		t.Run("will invalidate the request if duration exceeds the role maximum", func(t *testing.T) {
+			// Given
+			clientMock := mocks.NewMockK8sClient(t)
+			statusMock := mocks.NewMockSubResourceWriter(t)
+			pluginMock := mocks.NewMockAccessRequester(t)
+			ar := newAccessRequest(time.Now().Add(time.Hour))
+			ar.Spec.Duration = metav1.Duration{Duration: 2 * time.Hour}
+			clientMock.EXPECT().Status().Return(statusMock).Once()
+			statusMock.EXPECT().Update(mock.Anything, ar).Return(nil).Once()
+			svc := controller.NewService(clientMock, nil, pluginMock, record.NewFakeRecorder(10))
+
+			// When
+			status, err := svc.HandlePermission(context.Background(), ar, &argocd.Application{}, newRoleTemplate())
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, api.InvalidStatus, status)
+			assert.Contains(t, *ar.Status.History[len(ar.Status.History)-1].Details, "exceeds the maximum duration of 1h0m0s")
+		})
+		t.Run("will invalidate the request if the required justification is missing", func(t *testing.T) {
+			// Given
+			clientMock := mocks.NewMockK8sClient(t)
+			statusMock := mocks.NewMockSubResourceWriter(t)
+			pluginMock := mocks.NewMockAccessRequester(t)
+			ar := newAccessRequest(time.Now().Add(time.Hour))
+			ar.Spec.Justification = "incident response"
+			rt := newRoleTemplate()
+			rt.Spec.Justification = &api.JustificationSpec{Required: true}
+			clientMock.EXPECT().Status().Return(statusMock).Once()
+			statusMock.EXPECT().Update(mock.Anything, ar).Return(nil).Once()
+			svc := controller.NewService(clientMock, nil, pluginMock, record.NewFakeRecorder(10))
+
+			// When
+			status, err := svc.HandlePermission(context.Background(), ar, &argocd.Application{}, rt)
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, api.InvalidStatus, status)
+			assert.Contains(t, *ar.Status.History[len(ar.Status.History)-1].Details, "ticket reference is required")
+		})
+	})
+	t.Run("will grant project-scoped access in the project role", func(t *testing.T) {
+		// Given
+		clientMock := mocks.NewMockK8sClient(t)
+		statusMock := mocks.NewMockSubResourceWriter(t)
+		pluginMock := mocks.NewMockAccessRequester(t)
+		ar := utils.NewAccessRequest("test", "default", "", "", "someRole", "someRoleNs", "some-user")
+		ar.Spec.Project = &api.TargetAppProject{Name: "someProject"}
+		ar.Spec.Duration = metav1.Duration{Duration: time.Minute}
+		ar.Status.TargetProject = "someProject"
+		ar.UpdateStatusHistory(api.RequestedStatus, "")
+		rt := &api.RoleTemplate{
+			Spec: api.RoleTemplateSpec{
+				Name:        "some-role",
+				Description: "some-role-description",
+				Policies:    []string{"some-policy"},
+			},
+		}
+		pluginMock.EXPECT().GrantAccess(ar, (*argocd.Application)(nil)).
+			Return(&plugin.GrantResponse{Status: plugin.Granted}, nil).
+			Once()
+		clientMock.EXPECT().
+			Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.AppProject")).
+			Return(nil).
+			Once()
+		var patched *argocd.AppProject
+		clientMock.EXPECT().
+			Patch(mock.Anything, mock.AnythingOfType("*v1alpha1.AppProject"), mock.Anything, mock.Anything).
+			RunAndReturn(func(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				patched = obj.(*argocd.AppProject)
+				return nil
+			}).
+			Once()
+		clientMock.EXPECT().Status().Return(statusMock).Once()
+		statusMock.EXPECT().Update(mock.Anything, ar).Return(nil).Once()
+		expectAuthorized(clientMock, ar)
+		svc := controller.NewService(clientMock, nil, pluginMock, record.NewFakeRecorder(10))
+
+		// When
+		status, err := svc.HandlePermission(context.Background(), ar, nil, rt)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, api.GrantedStatus, status)
+		require.NotNil(t, patched)
+		require.Len(t, patched.Spec.Roles, 1)
+		assert.Equal(t, "ephemeral-project_some-role", patched.Spec.Roles[0].Name)
+		assert.Equal(t, []string{"some-user"}, patched.Spec.Roles[0].Groups)
+	})
+	t.Run("will handle access windows", func(t *testing.T) {
+		newRoleTemplate := func(blackoutStart time.Time) *api.RoleTemplate {
+			return &api.RoleTemplate{
+				Spec: api.RoleTemplateSpec{
+					Name:        "some-role",
+					Description: "some-role-description",
+					Policies:    []string{"some-policy"},
+					AccessWindows: &api.AccessWindows{
+						Blackouts: []api.BlackoutWindow{{
+							Start:  metav1.NewTime(blackoutStart),
+							End:    metav1.NewTime(blackoutStart.Add(time.Hour)),
+							Reason: "release freeze",
+						}},
+					},
+				},
+			}
+		}
+		newAccessRequest := func() *api.AccessRequest {
+			ar := utils.NewAccessRequest("test", "default", "someApp", "someAppNs", "someRole", "someRoleNs", "some-user")
+			ar.Spec.Duration = metav1.Duration{Duration: time.Hour}
+			ar.Status.TargetProject = "someProject"
+			ar.UpdateStatusHistory(api.RequestedStatus, "")
+			return ar
+		}
+		t.Run("will deny the request during a blackout", func(t *testing.T) {
+			// Given
+			clientMock := mocks.NewMockK8sClient(t)
+			statusMock := mocks.NewMockSubResourceWriter(t)
+			pluginMock := mocks.NewMockAccessRequester(t)
+			ar := newAccessRequest()
+			clientMock.EXPECT().Status().Return(statusMock).Once()
+			statusMock.EXPECT().Update(mock.Anything, ar).Return(nil).Once()
+			expectAuthorized(clientMock, ar)
+			svc := controller.NewService(clientMock, nil, pluginMock, record.NewFakeRecorder(10))
+
+			// When
+			status, err := svc.HandlePermission(context.Background(), ar, &argocd.Application{}, newRoleTemplate(time.Now().Add(-time.Minute)))
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, api.DeniedStatus, status)
+			assert.Nil(t, ar.Status.ExpiresAt)
+			assert.Contains(t, *ar.Status.History[len(ar.Status.History)-1].Details, "release freeze")
+		})
+		t.Run("will truncate the access at the end of the window", func(t *testing.T) {
+			// Given
+			clientMock := mocks.NewMockK8sClient(t)
+			statusMock := mocks.NewMockSubResourceWriter(t)
+			pluginMock := mocks.NewMockAccessRequester(t)
+			ar := newAccessRequest()
+			app := &argocd.Application{}
+			pluginMock.EXPECT().GrantAccess(ar, app).
+				Return(&plugin.GrantResponse{Status: plugin.Granted}, nil).
+				Once()
+			clientMock.EXPECT().
+				Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.AppProject")).
+				Return(nil).
+				Once()
+			clientMock.EXPECT().
+				Patch(mock.Anything, mock.AnythingOfType("*v1alpha1.AppProject"), mock.Anything, mock.Anything).
+				Return(nil).
+				Once()
+			clientMock.EXPECT().Status().Return(statusMock).Once()
+			statusMock.EXPECT().Update(mock.Anything, ar).Return(nil).Once()
+			expectAuthorized(clientMock, ar)
+			svc := controller.NewService(clientMock, nil, pluginMock, record.NewFakeRecorder(10))
+			blackoutStart := time.Now().Add(30 * time.Minute)
+
+			// When
+			status, err := svc.HandlePermission(context.Background(), ar, app, newRoleTemplate(blackoutStart))
+
+			// Then
+			assert.NoError(t, err)
+			assert.Equal(t, api.GrantedStatus, status)
+			require.NotNil(t, ar.Status.ExpiresAt)
+			assert.True(t, blackoutStart.Equal(ar.Status.ExpiresAt.Time))
+			assert.Contains(t, *ar.Status.History[len(ar.Status.History)-1].Details, "Access truncated to")
+		})
+	})
+}
+
+func TestHandleRevocation(t *testing.T) {
+	newRoleTemplate := func() *api.RoleTemplate {
+		return &api.RoleTemplate{
+			Spec: api.RoleTemplateSpec{
+				Name:        "some-role",
+				Description: "some-role-description",
+				Policies:    []string{"some-policy"},
+			},
+		}
+	}
+	newAccessRequest := func(status api.Status) *api.AccessRequest {
+		ar := utils.NewAccessRequest("test", "default", "someApp", "someAppNs", "someRole", "someRoleNs", "some-user")
+		ar.Spec.Duration = metav1.Duration{Duration: time.Minute}
+		ar.Spec.Revocation = &api.Revocation{
+			Revoker:   "some-admin",
+			Reason:    "incident closed",
+			RevokedAt: metav1.Now(),
+		}
+		ar.Status.TargetProject = "someProject"
+		ar.UpdateStatusHistory(api.RequestedStatus, "")
+		if status == api.GrantedStatus {
+			ar.UpdateStatusHistory(api.GrantedStatus, "")
+		}
+		return ar
+	}
+	t.Run("will remove access and update status if granted", func(t *testing.T) {
+		// Given
+		clientMock := mocks.NewMockK8sClient(t)
+		statusMock := mocks.NewMockSubResourceWriter(t)
+		pluginMock := mocks.NewMockAccessRequester(t)
+		ar := newAccessRequest(api.GrantedStatus)
+		app := &argocd.Application{}
+		clientMock.EXPECT().
+			Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.AppProject")).
+			Return(nil).
+			Once()
+		clientMock.EXPECT().
+			Patch(mock.Anything, mock.AnythingOfType("*v1alpha1.AppProject"), mock.Anything, mock.Anything).
+			Return(nil).
+			Once()
+		pluginMock.EXPECT().RevokeAccess(ar, app).
+			Return(&plugin.RevokeResponse{Status: plugin.Revoked}, nil).
+			Once()
+		clientMock.EXPECT().Status().Return(statusMock).Once()
+		statusMock.EXPECT().Update(mock.Anything, ar).Return(nil).Once()
+		svc := controller.NewService(clientMock, nil, pluginMock, record.NewFakeRecorder(10))
+
+		// When
+		status, err := svc.HandleRevocation(context.Background(), ar, app, newRoleTemplate())
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, api.RevokedStatus, status)
+		assert.Equal(t, api.RevokedStatus, ar.Status.RequestState)
+		history := ar.Status.History[len(ar.Status.History)-1]
+		assert.Equal(t, api.RevokedStatus, history.RequestState)
+		assert.Equal(t, "some-admin", *history.Actor)
+		assert.Equal(t, "Access revoked by some-admin: incident closed", *history.Details)
+	})
+	t.Run("will only update status if access is not granted yet", func(t *testing.T) {
+		// Given
+		clientMock := mocks.NewMockK8sClient(t)
+		statusMock := mocks.NewMockSubResourceWriter(t)
+		pluginMock := mocks.NewMockAccessRequester(t)
+		ar := newAccessRequest(api.RequestedStatus)
+		app := &argocd.Application{}
+		clientMock.EXPECT().Status().Return(statusMock).Once()
+		statusMock.EXPECT().Update(mock.Anything, ar).Return(nil).Once()
+		svc := controller.NewService(clientMock, nil, pluginMock, record.NewFakeRecorder(10))
+
+		// When
+		status, err := svc.HandleRevocation(context.Background(), ar, app, newRoleTemplate())
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, api.RevokedStatus, status)
+		assert.Equal(t, api.RevokedStatus, ar.Status.RequestState)
+	})
+	t.Run("will keep the request status if plugin returns revoke pending", func(t *testing.T) {
+		// Given
+		clientMock := mocks.NewMockK8sClient(t)
+		pluginMock := mocks.NewMockAccessRequester(t)
+		ar := newAccessRequest(api.GrantedStatus)
+		app := &argocd.Application{}
+		clientMock.EXPECT().
+			Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.AppProject")).
+			Return(nil).
+			Once()
+		clientMock.EXPECT().
+			Patch(mock.Anything, mock.AnythingOfType("*v1alpha1.AppProject"), mock.Anything, mock.Anything).
+			Return(nil).
+			Once()
+		pluginMock.EXPECT().RevokeAccess(ar, app).
+			Return(&plugin.RevokeResponse{Status: plugin.RevokePending}, nil).
+			Once()
+		svc := controller.NewService(clientMock, nil, pluginMock, record.NewFakeRecorder(10))
+
+		// When
+		status, err := svc.HandleRevocation(context.Background(), ar, app, newRoleTemplate())
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, api.GrantedStatus, status)
+		assert.Equal(t, api.GrantedStatus, ar.Status.RequestState)
+	})
+	t.Run("will return error if fails to remove argocd access", func(t *testing.T) {
+		// Given
+		clientMock := mocks.NewMockK8sClient(t)
+		pluginMock := mocks.NewMockAccessRequester(t)
+		ar := newAccessRequest(api.GrantedStatus)
+		app := &argocd.Application{}
+		clientMock.EXPECT().
+			Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.AppProject")).
+			Return(nil).
+			Once()
+		clientMock.EXPECT().
+			Patch(mock.Anything, mock.AnythingOfType("*v1alpha1.AppProject"), mock.Anything, mock.Anything).
+			Return(errors.New("patch error")).
+			Once()
+		svc := controller.NewService(clientMock, nil, pluginMock, record.NewFakeRecorder(10))
+
+		// When
+		status, err := svc.HandleRevocation(context.Background(), ar, app, newRoleTemplate())
+
+		// Then
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "patch error")
+		assert.Equal(t, "", string(status))
+		assert.Equal(t, api.GrantedStatus, ar.Status.RequestState)
+	})
+}
+
+func TestHandleExtensions(t *testing.T) {
+	newRoleTemplate := func(maxDuration *time.Duration) *api.RoleTemplate {
+		rt := &api.RoleTemplate{
+			Spec: api.RoleTemplateSpec{
+				Name:        "some-role",
+				Description: "some-role-description",
+				Policies:    []string{"some-policy"},
+			},
+		}
+		if maxDuration != nil {
+			rt.Spec.MaxExtendedDuration = &metav1.Duration{Duration: *maxDuration}
+		}
+		return rt
+	}
+	newAccessRequest := func(extensions ...time.Duration) *api.AccessRequest {
+		ar := utils.NewAccessRequest("test", "default", "someApp", "someAppNs", "someRole", "someRoleNs", "some-user")
+		ar.Spec.Duration = metav1.Duration{Duration: time.Hour}
+		ar.Status.TargetProject = "someProject"
+		ar.UpdateStatusHistory(api.RequestedStatus, "")
+		ar.UpdateStatusHistory(api.GrantedStatus, "")
+		for _, d := range extensions {
+			ar.Spec.Extensions = append(ar.Spec.Extensions, api.Extension{
+				Duration:    metav1.Duration{Duration: d},
+				Requester:   "some-user",
+				RequestedAt: metav1.Now(),
+			})
+		}
+		return ar
+	}
+	setupMocks := func(t *testing.T, ar *api.AccessRequest) *mocks.MockK8sClient {
+		t.Helper()
+		clientMock := mocks.NewMockK8sClient(t)
+		statusMock := mocks.NewMockSubResourceWriter(t)
+		clientMock.EXPECT().Status().Return(statusMock).Once()
+		statusMock.EXPECT().Update(mock.Anything, ar).Return(nil).Once()
+		clientMock.EXPECT().
+			Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.AppProject")).
+			Return(nil).
+			Once()
+		clientMock.EXPECT().
+			Patch(mock.Anything, mock.AnythingOfType("*v1alpha1.AppProject"), mock.Anything, mock.Anything).
+			Return(nil).
+			Once()
+		return clientMock
+	}
+	t.Run("will move expiresAt forward for each extension", func(t *testing.T) {
+		// Given
+		ar := newAccessRequest(time.Minute*30, time.Minute*15)
+		expiresAt := ar.Status.ExpiresAt.Time
+		clientMock := setupMocks(t, ar)
+		maxDuration := time.Hour * 4
+		svc := controller.NewService(clientMock, nil, nil, record.NewFakeRecorder(10))
+
+		// When
+		status, err := svc.HandlePermission(context.Background(), ar, &argocd.Application{}, newRoleTemplate(&maxDuration))
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, api.GrantedStatus, status)
+		assert.Equal(t, 2, ar.Status.AppliedExtensions)
+		assert.Equal(t, expiresAt.Add(time.Minute*45), ar.Status.ExpiresAt.Time)
+		assert.Equal(t, time.Minute*45, ar.Status.ExtendedDuration.Duration)
+		history := ar.Status.History[len(ar.Status.History)-1]
+		assert.Equal(t, api.GrantedStatus, history.RequestState)
+		assert.Equal(t, "some-user", *history.Actor)
+		assert.Contains(t, *history.Details, "Access extended by 15m0s")
+	})
+	t.Run("will reject extension exceeding the maximum duration", func(t *testing.T) {
+		// Given
+		ar := newAccessRequest(time.Hour * 2)
+		expiresAt := ar.Status.ExpiresAt.Time
+		clientMock := setupMocks(t, ar)
+		maxDuration := time.Hour * 2
+		svc := controller.NewService(clientMock, nil, nil, record.NewFakeRecorder(10))
+
+		// When
+		status, err := svc.HandlePermission(context.Background(), ar, &argocd.Application{}, newRoleTemplate(&maxDuration))
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, api.GrantedStatus, status)
+		assert.Equal(t, 1, ar.Status.AppliedExtensions)
+		assert.Equal(t, expiresAt, ar.Status.ExpiresAt.Time)
+		assert.Contains(t, *ar.Status.History[len(ar.Status.History)-1].Details, "maximum extended duration of 2h0m0s exceeded")
+	})
+	t.Run("will not account for rejected extensions in the maximum duration", func(t *testing.T) {
+		// Given
+		ar := newAccessRequest(time.Hour*2, time.Minute*30)
+		expiresAt := ar.Status.ExpiresAt.Time
+		clientMock := setupMocks(t, ar)
+		maxDuration := time.Hour * 2
+		svc := controller.NewService(clientMock, nil, nil, record.NewFakeRecorder(10))
+
+		// When
+		status, err := svc.HandlePermission(context.Background(), ar, &argocd.Application{}, newRoleTemplate(&maxDuration))
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, api.GrantedStatus, status)
+		assert.Equal(t, 2, ar.Status.AppliedExtensions)
+		assert.Equal(t, expiresAt.Add(time.Minute*30), ar.Status.ExpiresAt.Time)
+		assert.Equal(t, time.Minute*30, ar.Status.ExtendedDuration.Duration)
+		assert.Contains(t, *ar.Status.History[len(ar.Status.History)-2].Details, "maximum extended duration of 2h0m0s exceeded")
+		assert.Contains(t, *ar.Status.History[len(ar.Status.History)-1].Details, "Access extended by 30m0s")
+	})
+	t.Run("will account for previously granted extensions in the maximum duration", func(t *testing.T) {
+		// Given
+		ar := newAccessRequest(time.Minute * 45)
+		ar.Status.ExtendedDuration = &metav1.Duration{Duration: time.Minute * 30}
+		expiresAt := ar.Status.ExpiresAt.Time
+		clientMock := setupMocks(t, ar)
+		maxDuration := time.Hour * 2
+		svc := controller.NewService(clientMock, nil, nil, record.NewFakeRecorder(10))
+
+		// When
+		status, err := svc.HandlePermission(context.Background(), ar, &argocd.Application{}, newRoleTemplate(&maxDuration))
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, api.GrantedStatus, status)
+		assert.Equal(t, expiresAt, ar.Status.ExpiresAt.Time)
+		assert.Equal(t, time.Minute*30, ar.Status.ExtendedDuration.Duration)
+		assert.Contains(t, *ar.Status.History[len(ar.Status.History)-1].Details, "maximum extended duration of 2h0m0s exceeded")
+	})
+	t.Run("will reject extension if role does not define maximum extended duration", func(t *testing.T) {
+		// Given
+		ar := newAccessRequest(time.Minute)
+		expiresAt := ar.Status.ExpiresAt.Time
+		clientMock := setupMocks(t, ar)
+		svc := controller.NewService(clientMock, nil, nil, record.NewFakeRecorder(10))
+
+		// When
+		status, err := svc.HandlePermission(context.Background(), ar, &argocd.Application{}, newRoleTemplate(nil))
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, api.GrantedStatus, status)
+		assert.Equal(t, 1, ar.Status.AppliedExtensions)
+		assert.Equal(t, expiresAt, ar.Status.ExpiresAt.Time)
+		assert.Contains(t, *ar.Status.History[len(ar.Status.History)-1].Details, "role does not allow extensions")
+	})
+	t.Run("will reject extension if role only defines maximum duration", func(t *testing.T) {
+		// Given
+		ar := newAccessRequest(time.Minute)
+		expiresAt := ar.Status.ExpiresAt.Time
+		clientMock := setupMocks(t, ar)
+		rt := newRoleTemplate(nil)
+		rt.Spec.MaxDuration = &metav1.Duration{Duration: time.Hour * 4}
+		svc := controller.NewService(clientMock, nil, nil, record.NewFakeRecorder(10))
+
+		// When
+		status, err := svc.HandlePermission(context.Background(), ar, &argocd.Application{}, rt)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, api.GrantedStatus, status)
+		assert.Equal(t, 1, ar.Status.AppliedExtensions)
+		assert.Equal(t, expiresAt, ar.Status.ExpiresAt.Time)
+		assert.Contains(t, *ar.Status.History[len(ar.Status.History)-1].Details, "role does not allow extensions")
+	})
+	t.Run("will reject extension past the role access windows", func(t *testing.T) {
+		// Given
+		ar := newAccessRequest(time.Hour)
+		expiresAt := ar.Status.ExpiresAt.Time
+		clientMock := setupMocks(t, ar)
+		maxDuration := time.Hour * 4
+		rt := newRoleTemplate(&maxDuration)
+		rt.Spec.AccessWindows = &api.AccessWindows{
+			Blackouts: []api.BlackoutWindow{{
+				Start: metav1.NewTime(expiresAt.Add(time.Minute)),
+				End:   metav1.NewTime(expiresAt.Add(time.Hour)),
+			}},
+		}
+		svc := controller.NewService(clientMock, nil, nil, record.NewFakeRecorder(10))
+
+		// When
+		status, err := svc.HandlePermission(context.Background(), ar, &argocd.Application{}, rt)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, api.GrantedStatus, status)
+		assert.Equal(t, 1, ar.Status.AppliedExtensions)
+		assert.Equal(t, expiresAt, ar.Status.ExpiresAt.Time)
+		assert.Contains(t, *ar.Status.History[len(ar.Status.History)-1].Details, "access would extend past the role access windows")
+	})
+	t.Run("will reject extensions not signed by the backend if signatures are required", func(t *testing.T) {
+		// Given
+		signingKey := []byte("some-secret")
+		ar := newAccessRequest(time.Minute)
+		require.NoError(t, ar.Sign(signingKey))
+		require.NoError(t, ar.SignExtensions(signingKey))
+		ar.Spec.Extensions[0].Requester = "another-user"
+		expiresAt := ar.Status.ExpiresAt.Time
+		clientMock := setupMocks(t, ar)
+		configMock := mocks.NewMockConfigurer(t)
+		configMock.EXPECT().ControllerRequireSignature().Return(true)
+		configMock.EXPECT().ControllerSigningKey().Return(signingKey)
+		configMock.EXPECT().ControllerServerSideApply().Return(false)
+		maxDuration := time.Hour * 4
+		svc := controller.NewService(clientMock, configMock, nil, record.NewFakeRecorder(10))
+
+		// When
+		status, err := svc.HandlePermission(context.Background(), ar, &argocd.Application{}, newRoleTemplate(&maxDuration))
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, api.GrantedStatus, status)
+		assert.Equal(t, 1, ar.Status.AppliedExtensions)
+		assert.Equal(t, expiresAt, ar.Status.ExpiresAt.Time)
+		assert.Contains(t, *ar.Status.History[len(ar.Status.History)-1].Details, "signature does not match the extensions")
+	})
+	t.Run("will apply extensions signed by the backend if signatures are required", func(t *testing.T) {
+		// Given
+		signingKey := []byte("some-secret")
+		ar := newAccessRequest(time.Minute)
+		require.NoError(t, ar.Sign(signingKey))
+		require.NoError(t, ar.SignExtensions(signingKey))
+		expiresAt := ar.Status.ExpiresAt.Time
+		clientMock := setupMocks(t, ar)
+		configMock := mocks.NewMockConfigurer(t)
+		configMock.EXPECT().ControllerRequireSignature().Return(true)
+		configMock.EXPECT().ControllerSigningKey().Return(signingKey)
+		configMock.EXPECT().ControllerServerSideApply().Return(false)
+		maxDuration := time.Hour * 4
+		svc := controller.NewService(clientMock, configMock, nil, record.NewFakeRecorder(10))
+
+		// When
+		status, err := svc.HandlePermission(context.Background(), ar, &argocd.Application{}, newRoleTemplate(&maxDuration))
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, api.GrantedStatus, status)
+		assert.Equal(t, expiresAt.Add(time.Minute), ar.Status.ExpiresAt.Time)
+	})
+	t.Run("will reject extension of break-glass access", func(t *testing.T) {
+		// Given
+		ar := newAccessRequest(time.Minute)
+		ar.Spec.BreakGlass = true
+		ar.SetLabels(map[string]string{api.BreakGlassLabel: "true", api.PendingReviewLabel: "true"})
+		expiresAt := ar.Status.ExpiresAt.Time
+		clientMock := setupMocks(t, ar)
+		maxDuration := time.Hour * 4
+		rt := newRoleTemplate(&maxDuration)
+		rt.Spec.BreakGlass = &api.BreakGlassSpec{MaxDuration: metav1.Duration{Duration: time.Hour}, Reviewers: []string{"some-auditor"}}
+		svc := controller.NewService(clientMock, nil, nil, record.NewFakeRecorder(10))
+
+		// When
+		status, err := svc.HandlePermission(context.Background(), ar, &argocd.Application{}, rt)
+
+		// Then
+		assert.NoError(t, err)
+		assert.Equal(t, api.GrantedStatus, status)
+		assert.Equal(t, 1, ar.Status.AppliedExtensions)
+		assert.Equal(t, expiresAt, ar.Status.ExpiresAt.Time)
+		assert.Contains(t, *ar.Status.History[len(ar.Status.History)-1].Details, "break-glass access can not be extended")
+	})
+}
+
+func TestRemoveArgoCDAccess(t *testing.T) {
+	newProject := func() *argocd.AppProject {
+		return &argocd.AppProject{
+			ObjectMeta: metav1.ObjectMeta{Name: "someProject", Namespace: "default"},
+			Spec: argocd.AppProjectSpec{
+				Roles: []argocd.ProjectRole{
+					{Name: "gitops-role", Groups: []string{"some-group"}},
+					{Name: "ephemeral-some-role-someAppNs-someApp", Groups: []string{"some-user", "other-user"}},
+					{Name: "ephemeral-other-role", JWTTokens: []argocd.JWTToken{{IssuedAt: 1}}},
+				},
+			},
+		}
+	}
+	rt := &api.RoleTemplate{
+		Spec: api.RoleTemplateSpec{
+			Name:        "some-role",
+			Description: "some-role-description",
+			Policies:    []string{"some-policy"},
+		},
+	}
+	newAccessRequest := func() *api.AccessRequest {
+		ar := utils.NewAccessRequest("test", "default", "someApp", "someAppNs", "someRole", "someRoleNs", "some-user")
+		ar.Status.TargetProject = "someProject"
+		ar.Status.RoleName = rt.AppProjectRoleName("someApp", "someAppNs")
+		return ar
+	}
+	t.Run("will apply only ephemeral roles if server-side apply is enabled", func(t *testing.T) {
+		// Given
+		clientMock := mocks.NewMockK8sClient(t)
+		configMock := mocks.NewMockConfigurer(t)
+		configMock.EXPECT().ControllerServerSideApply().Return(true)
+		clientMock.EXPECT().
+			Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.AppProject")).
+			RunAndReturn(func(ctx context.Context, key types.NamespacedName, obj client.Object, opts ...client.GetOption) error {
+				newProject().DeepCopyInto(obj.(*argocd.AppProject))
+				return nil
+			})
+		var applied *unstructured.Unstructured
+		var patchOpts []client.PatchOption
+		clientMock.EXPECT().
+			Patch(mock.Anything, mock.AnythingOfType("*unstructured.Unstructured"), client.Apply, mock.Anything, mock.Anything).
+			RunAndReturn(func(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				applied = obj.(*unstructured.Unstructured)
+				patchOpts = opts
+				return nil
+			})
+		svc := controller.NewService(clientMock, configMock, nil, record.NewFakeRecorder(10))
+
+		// When
+		err := svc.RemoveArgoCDAccess(context.Background(), newAccessRequest(), rt)
+
+		// Then
+		require.NoError(t, err)
+		require.NotNil(t, applied)
+		assert.Equal(t, "AppProject", applied.GetKind())
+		assert.Equal(t, "argoproj.io/v1alpha1", applied.GetAPIVersion())
+		assert.Equal(t, "someProject", applied.GetName())
+		assert.Equal(t, "default", applied.GetNamespace())
+		assert.Empty(t, applied.GetResourceVersion())
+		assert.Contains(t, patchOpts, client.ForceOwnership)
+		assert.Contains(t, patchOpts, client.FieldOwner(controller.FieldOwnerEphemeralAccess))
+		roles, found, err := unstructured.NestedSlice(applied.Object, "spec", "roles")
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Len(t, roles, 2)
+		role := roles[0].(map[string]interface{})
+		assert.Equal(t, "ephemeral-some-role-someAppNs-someApp", role["name"])
+		assert.Equal(t, []interface{}{"other-user"}, role["groups"])
+		assert.Equal(t, []interface{}{"some-policy"}, role["policies"])
+		assert.Equal(t, []interface{}{}, role["jwtTokens"])
+		role = roles[1].(map[string]interface{})
+		assert.Equal(t, "ephemeral-other-role", role["name"])
+		assert.Equal(t, []interface{}{}, role["groups"])
+		assert.Equal(t, []interface{}{}, role["jwtTokens"])
+	})
+	t.Run("will use merge patch if server-side apply is disabled", func(t *testing.T) {
+		// Given
+		clientMock := mocks.NewMockK8sClient(t)
+		configMock := mocks.NewMockConfigurer(t)
+		configMock.EXPECT().ControllerServerSideApply().Return(false)
+		clientMock.EXPECT().
+			Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.AppProject")).
+			RunAndReturn(func(ctx context.Context, key types.NamespacedName, obj client.Object, opts ...client.GetOption) error {
+				newProject().DeepCopyInto(obj.(*argocd.AppProject))
+				return nil
+			})
+		var patchType types.PatchType
+		clientMock.EXPECT().
+			Patch(mock.Anything, mock.AnythingOfType("*v1alpha1.AppProject"), mock.Anything, mock.Anything).
+			RunAndReturn(func(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				patchType = patch.Type()
+				return nil
+			})
+		svc := controller.NewService(clientMock, configMock, nil, record.NewFakeRecorder(10))
+
+		// When
+		err := svc.RemoveArgoCDAccess(context.Background(), newAccessRequest(), rt)
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, types.MergePatchType, patchType)
+	})
+	t.Run("will remove the group from the role if subject targets a group", func(t *testing.T) {
+		// Given
+		clientMock := mocks.NewMockK8sClient(t)
+		clientMock.EXPECT().
+			Get(mock.Anything, mock.Anything, mock.AnythingOfType("*v1alpha1.AppProject")).
+			RunAndReturn(func(ctx context.Context, key types.NamespacedName, obj client.Object, opts ...client.GetOption) error {
+				project := newProject()
+				project.Spec.Roles[1].Groups = append(project.Spec.Roles[1].Groups, "on-call")
+				project.DeepCopyInto(obj.(*argocd.AppProject))
+				return nil
+			})
+		var patched *argocd.AppProject
+		clientMock.EXPECT().
+			Patch(mock.Anything, mock.AnythingOfType("*v1alpha1.AppProject"), mock.Anything, mock.Anything).
+			RunAndReturn(func(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				patched = obj.(*argocd.AppProject)
+				return nil
+			})
+		svc := controller.NewService(clientMock, nil, nil, record.NewFakeRecorder(10))
+		ar := newAccessRequest()
+		ar.Spec.Subject.Group = "on-call"
+
+		// When
+		err := svc.RemoveArgoCDAccess(context.Background(), ar, rt)
+
+		// Then
+		require.NoError(t, err)
+		require.NotNil(t, patched)
+		assert.Equal(t, []string{"some-user", "other-user"}, patched.Spec.Roles[1].Groups)
+	})
+}
+
+// expectAuthorized configures the given clientMock to return an AccessBinding
+// authorizing the given ar with the group claims recorded in it.
+func expectAuthorized(clientMock *mocks.MockK8sClient, ar *api.AccessRequest) {
+	ar.Spec.Subject.Groups = []string{"some-group"}
+	clientMock.EXPECT().
+		List(mock.Anything, mock.AnythingOfType("*v1alpha1.AccessBindingList"), mock.Anything).
+		RunAndReturn(func(_ context.Context, list client.ObjectList, _ ...client.ListOption) error {
+			list.(*api.AccessBindingList).Items = []api.AccessBinding{{
+				Spec: api.AccessBindingSpec{
+					RoleTemplateRef: api.RoleTemplateReference{Name: ar.Spec.Role.TemplateRef.Name},
+					Subjects:        []string{"some-group"},
+					GroupTargets:    []string{"*"},
+					BreakGlass:      ar.Spec.BreakGlass,
+				},
+			}}
+			return nil
+		})
+	clientMock.EXPECT().
+		Get(mock.Anything, mock.Anything, mock.AnythingOfType("*unstructured.Unstructured")).
+		Return(nil)
+}
+
+func TestVerifyServerSideApply(t *testing.T) {
+	newCRD := func(roles map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"versions": []interface{}{
+					map[string]interface{}{
+						"name": "v1alpha1",
+						"schema": map[string]interface{}{
+							"openAPIV3Schema": map[string]interface{}{
+								"properties": map[string]interface{}{
+									"spec": map[string]interface{}{
+										"properties": map[string]interface{}{
+											"roles": roles,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}}
+	}
+	setupMock := func(t *testing.T, crd *unstructured.Unstructured) *mocks.MockK8sClient {
+		clientMock := mocks.NewMockK8sClient(t)
+		clientMock.EXPECT().
+			Get(mock.Anything, client.ObjectKey{Name: "appprojects.argoproj.io"}, mock.AnythingOfType("*unstructured.Unstructured")).
+			RunAndReturn(func(ctx context.Context, key types.NamespacedName, obj client.Object, opts ...client.GetOption) error {
+				obj.(*unstructured.Unstructured).Object = crd.Object
+				return nil
+			})
+		return clientMock
+	}
+	t.Run("will succeed if roles is a list map keyed by name", func(t *testing.T) {
+		// Given
+		clientMock := setupMock(t, newCRD(map[string]interface{}{
+			"type":                       "array",
+			"x-kubernetes-list-type":     "map",
+			"x-kubernetes-list-map-keys": []interface{}{"name"},
+		}))
+
+		// When
+		err := controller.VerifyServerSideApply(context.Background(), clientMock)
+
+		// Then
+		assert.NoError(t, err)
+	})
+	t.Run("will return error if roles is an atomic list", func(t *testing.T) {
+		// Given
+		clientMock := setupMock(t, newCRD(map[string]interface{}{"type": "array"}))
+
+		// When
+		err := controller.VerifyServerSideApply(context.Background(), clientMock)
+
+		// Then
+		assert.ErrorContains(t, err, "list type atomic")
+	})
+	t.Run("will return error if roles is not keyed by name", func(t *testing.T) {
+		// Given
+		clientMock := setupMock(t, newCRD(map[string]interface{}{
+			"type":                       "array",
+			"x-kubernetes-list-type":     "map",
+			"x-kubernetes-list-map-keys": []interface{}{"other"},
+		}))
+
+		// When
+		err := controller.VerifyServerSideApply(context.Background(), clientMock)
+
+		// Then
+		assert.ErrorContains(t, err, "server-side apply requires list type map keyed by name")
+	})
+	t.Run("will return error if the CRD can not be retrieved", func(t *testing.T) {
+		// Given
+		clientMock := mocks.NewMockK8sClient(t)
+		clientMock.EXPECT().
+			Get(mock.Anything, mock.Anything, mock.AnythingOfType("*unstructured.Unstructured")).
+			Return(errors.New("forbidden"))
+
+		// When
+		err := controller.VerifyServerSideApply(context.Background(), clientMock)
+
+		// Then
+		assert.ErrorContains(t, err, "forbidden")
+	})
 }
@@ -105,15 +105,30 @@ var _ = BeforeSuite(func() {
 	config, err := config.ReadEnvConfigs()
 	Expect(err).ToNot(HaveOccurred())
 
-	service := NewService(k8sManager.GetClient(), config)
+	recorder := k8sManager.GetEventRecorderFor("ephemeral-access-controller")
+	service := NewService(k8sManager.GetClient(), config, nil, recorder)
 	arReconciler := &AccessRequestReconciler{
-		Client:  k8sManager.GetClient(),
-		Scheme:  k8sManager.GetScheme(),
-		Service: service,
-		Config:  config,
+		Client:   k8sManager.GetClient(),
+		Scheme:   k8sManager.GetScheme(),
+		Service:  service,
+		Config:   config,
+		Recorder: recorder,
 	}
 	err = arReconciler.SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
+	rtReconciler := &RoleTemplateReconciler{
+		Client:   k8sManager.GetClient(),
+		Scheme:   k8sManager.GetScheme(),
+		Recorder: recorder,
+	}
+	err = rtReconciler.SetupWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
+	driftReconciler := &ProjectDriftReconciler{
+		Client:   k8sManager.GetClient(),
+		Recorder: recorder,
+	}
+	err = driftReconciler.SetupWithManager(k8sManager)
+	Expect(err).ToNot(HaveOccurred())
 
 	dynClient, err = dynamic.NewForConfig(restConfig)
 	Expect(err).NotTo(HaveOccurred())
@@ -0,0 +1,182 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	argocd "github.com/argoproj-labs/ephemeral-access/api/argoproj/v1alpha1"
+	api "github.com/argoproj-labs/ephemeral-access/api/ephemeral-access/v1alpha1"
+	"github.com/argoproj-labs/ephemeral-access/pkg/log"
+)
+
+// EventReasonRoleRemoved is used when an orphaned ephemeral role is removed
+// from an AppProject
+const EventReasonRoleRemoved = "RoleRemoved"
+
+// RoleSweeper periodically removes orphaned ephemeral roles from AppProjects.
+// A role is considered orphaned when its name has the ephemeral prefix, it
+// has no groups and no JWT tokens and there is no live AccessRequest
+// referencing it. It implements the controller-runtime manager.Runnable
+// interface.
+type RoleSweeper struct {
+	client          client.Client
+	recorder        record.EventRecorder
+	interval        time.Duration
+	dryRun          bool
+	serverSideApply bool
+}
+
+// NewRoleSweeper will return a new RoleSweeper instance that runs every
+// interval. If dryRun is true, orphaned roles are only logged and counted
+// without being removed. If serverSideApply is true, the AppProjects are
+// updated with server-side apply instead of merge patches.
+func NewRoleSweeper(c client.Client, r record.EventRecorder, interval time.Duration, dryRun, serverSideApply bool) *RoleSweeper {
+	return &RoleSweeper{
+		client:          c,
+		recorder:        r,
+		interval:        interval,
+		dryRun:          dryRun,
+		serverSideApply: serverSideApply,
+	}
+}
+
+// Start implements manager.Runnable. It blocks until the given ctx is done.
+func (s *RoleSweeper) Start(ctx context.Context) error {
+	logger := log.FromContext(ctx, "component", "role-sweeper")
+	logger.Info("Starting role sweeper", "interval", s.interval.String(), "dryRun", s.dryRun)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("Shutting down role sweeper")
+			return nil
+		case <-ticker.C:
+			removed, err := s.Sweep(ctx)
+			if err != nil {
+				sweeperErrorsTotal.Inc()
+				logger.Error(err, "Role sweeper error")
+				continue
+			}
+			logger.Info("Role sweeper concluded", "orphanedRoles", removed)
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable to ensure
+// that only the leader instance removes roles.
+func (s *RoleSweeper) NeedLeaderElection() bool {
+	return true
+}
+
+// Sweep will remove all orphaned ephemeral roles from all AppProjects and
+// return the number of roles found. Projects updated concurrently are
+// skipped and verified again in the next execution.
+func (s *RoleSweeper) Sweep(ctx context.Context) (int, error) {
+	logger := log.FromContext(ctx, "component", "role-sweeper")
+	referenced, err := s.referencedRoles(ctx)
+	if err != nil {
+		return 0, err
+	}
+	projects := &argocd.AppProjectList{}
+	err = s.client.List(ctx, projects)
+	if err != nil {
+		return 0, fmt.Errorf("error listing AppProjects: %w", err)
+	}
+
+	total := 0
+	for _, project := range projects.Items {
+		orphaned := orphanedRoles(&project, referenced)
+		if len(orphaned) == 0 {
+			continue
+		}
+		total += len(orphaned)
+		dryRun := fmt.Sprintf("%t", s.dryRun)
+		if s.dryRun {
+			for _, role := range orphaned {
+				logger.Info("Orphaned role found (dry-run)", "project", project.GetName(), "namespace", project.GetNamespace(), "role", role)
+			}
+			sweeperRolesRemovedTotal.WithLabelValues(project.GetName(), dryRun).Add(float64(len(orphaned)))
+			continue
+		}
+		err := s.removeRoles(ctx, &project, orphaned)
+		if err != nil {
+			if apierrors.IsConflict(err) {
+				logger.Info("AppProject changed while removing orphaned roles: skipping", "project", project.GetName(), "namespace", project.GetNamespace())
+				total -= len(orphaned)
+				continue
+			}
+			return total, fmt.Errorf("error removing orphaned roles from AppProject %s/%s: %w", project.GetNamespace(), project.GetName(), err)
+		}
+		sweeperRolesRemovedTotal.WithLabelValues(project.GetName(), dryRun).Add(float64(len(orphaned)))
+	}
+	return total, nil
+}
+
+// referencedRoles returns the set of AppProject roles referenced by live
+// AccessRequests. The key format is <namespace>/<project>/<role>.
+func (s *RoleSweeper) referencedRoles(ctx context.Context) (map[string]bool, error) {
+	list := &api.AccessRequestList{}
+	err := s.client.List(ctx, list)
+	if err != nil {
+		return nil, fmt.Errorf("error listing AccessRequests: %w", err)
+	}
+	referenced := make(map[string]bool)
+	for _, ar := range list.Items {
+		if isConcluded(&ar) || ar.Status.RoleName == "" {
+			continue
+		}
+		referenced[roleKey(ar.GetNamespace(), ar.Status.TargetProject, ar.Status.RoleName)] = true
+	}
+	return referenced, nil
+}
+
+// removeRoles will remove the given roles from the project.
+func (s *RoleSweeper) removeRoles(ctx context.Context, project *argocd.AppProject, roles []string) error {
+	original := project.DeepCopy()
+	remaining := []argocd.ProjectRole{}
+	for _, role := range project.Spec.Roles {
+		if !slices.Contains(roles, role.Name) {
+			remaining = append(remaining, role)
+		}
+	}
+	project.Spec.Roles = remaining
+	err := patchProject(ctx, s.client, s.serverSideApply, original, project)
+	if err != nil {
+		return err
+	}
+	for _, role := range roles {
+		log.FromContext(ctx, "component", "role-sweeper").Info("Orphaned role removed", "project", project.GetName(), "namespace", project.GetNamespace(), "role", role)
+		s.recorder.Eventf(project, corev1.EventTypeNormal, EventReasonRoleRemoved, "Orphaned role %s removed", role)
+	}
+	return nil
+}
+
+// orphanedRoles returns the names of the ephemeral roles in the given
+// project without groups, JWT tokens and not referenced by live
+// AccessRequests.
+func orphanedRoles(project *argocd.AppProject, referenced map[string]bool) []string {
+	orphaned := []string{}
+	for _, role := range project.Spec.Roles {
+		if !strings.HasPrefix(role.Name, api.AppProjectRolePrefix) ||
+			len(role.Groups) > 0 ||
+			len(role.JWTTokens) > 0 ||
+			referenced[roleKey(project.GetNamespace(), project.GetName(), role.Name)] {
+			continue
+		}
+		orphaned = append(orphaned, role.Name)
+	}
+	return orphaned
+}
+
+func roleKey(namespace, project, role string) string {
+	return fmt.Sprintf("%s/%s/%s", namespace, project, role)
+}
@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	argocd "github.com/argoproj-labs/ephemeral-access/api/argoproj/v1alpha1"
+	api "github.com/argoproj-labs/ephemeral-access/api/ephemeral-access/v1alpha1"
+	"github.com/argoproj-labs/ephemeral-access/test/utils"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newSweeperFixture(t *testing.T, dryRun bool, objs ...client.Object) (*RoleSweeper, client.Client) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, api.AddToScheme(scheme))
+	require.NoError(t, argocd.AddToScheme(scheme))
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	return NewRoleSweeper(c, record.NewFakeRecorder(10), 0, dryRun, false), c
+}
+
+func newSweeperProject(name string, roles ...argocd.ProjectRole) *argocd.AppProject {
+	return &argocd.AppProject{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "ephemeral"},
+		Spec:       argocd.AppProjectSpec{Roles: roles},
+	}
+}
+
+func TestRoleSweeper(t *testing.T) {
+	t.Run("will remove orphaned ephemeral roles", func(t *testing.T) {
+		// Given
+		project := newSweeperProject("sweep-project",
+			argocd.ProjectRole{Name: "ephemeral-orphaned"},
+			argocd.ProjectRole{Name: "ephemeral-with-groups", Groups: []string{"user"}},
+			argocd.ProjectRole{Name: "ephemeral-referenced"},
+			argocd.ProjectRole{Name: "ephemeral-expired"},
+			argocd.ProjectRole{Name: "not-managed"},
+		)
+		requested := utils.NewAccessRequestRequested(utils.WithName("requested"))
+		requested.SetNamespace("ephemeral")
+		requested.Status.TargetProject = "sweep-project"
+		requested.Status.RoleName = "ephemeral-referenced"
+		expired := utils.NewAccessRequestExpired(utils.WithName("expired"))
+		expired.SetNamespace("ephemeral")
+		expired.Status.TargetProject = "sweep-project"
+		expired.Status.RoleName = "ephemeral-expired"
+		sweeper, c := newSweeperFixture(t, false, project, requested, expired)
+		counter := sweeperRolesRemovedTotal.WithLabelValues("sweep-project", "false")
+		before := testutil.ToFloat64(counter)
+
+		// When
+		removed, err := sweeper.Sweep(context.Background())
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, 2, removed)
+		assert.Equal(t, before+2, testutil.ToFloat64(counter))
+		result := &argocd.AppProject{}
+		err = c.Get(context.Background(), client.ObjectKeyFromObject(project), result)
+		require.NoError(t, err)
+		names := []string{}
+		for _, role := range result.Spec.Roles {
+			names = append(names, role.Name)
+		}
+		assert.ElementsMatch(t, []string{"ephemeral-with-groups", "ephemeral-referenced", "not-managed"}, names)
+	})
+	t.Run("will not remove roles in dry-run mode", func(t *testing.T) {
+		// Given
+		project := newSweeperProject("dry-run-project",
+			argocd.ProjectRole{Name: "ephemeral-orphaned"},
+		)
+		sweeper, c := newSweeperFixture(t, true, project)
+		counter := sweeperRolesRemovedTotal.WithLabelValues("dry-run-project", "true")
+		before := testutil.ToFloat64(counter)
+
+		// When
+		removed, err := sweeper.Sweep(context.Background())
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, 1, removed)
+		assert.Equal(t, before+1, testutil.ToFloat64(counter))
+		result := &argocd.AppProject{}
+		err = c.Get(context.Background(), client.ObjectKeyFromObject(project), result)
+		require.NoError(t, err)
+		assert.Len(t, result.Spec.Roles, 1)
+	})
+	t.Run("will not patch projects without orphaned roles", func(t *testing.T) {
+		// Given
+		project := newSweeperProject("clean-project",
+			argocd.ProjectRole{Name: "ephemeral-with-groups", Groups: []string{"user"}},
+		)
+		sweeper, c := newSweeperFixture(t, false, project)
+		initial := &argocd.AppProject{}
+		err := c.Get(context.Background(), client.ObjectKeyFromObject(project), initial)
+		require.NoError(t, err)
+
+		// When
+		removed, err := sweeper.Sweep(context.Background())
+
+		// Then
+		require.NoError(t, err)
+		assert.Equal(t, 0, removed)
+		result := &argocd.AppProject{}
+		err = c.Get(context.Background(), client.ObjectKeyFromObject(project), result)
+		require.NoError(t, err)
+		assert.Equal(t, initial.GetResourceVersion(), result.GetResourceVersion())
+	})
+}
@@ -0,0 +1,65 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/expr-lang/expr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	api "github.com/argoproj-labs/ephemeral-access/api/ephemeral-access/v1alpha1"
+)
+
+// +kubebuilder:webhook:path=/validate-ephemeral-access-argoproj-labs-io-v1alpha1-accessbinding,mutating=false,failurePolicy=fail,sideEffects=None,groups=ephemeral-access.argoproj-labs.io,resources=accessbindings,verbs=create;update,versions=v1alpha1,name=vaccessbinding.ephemeral-access.argoproj-labs.io,admissionReviewVersions=v1
+
+// AccessBindingValidator rejects AccessBindings without subjects or with
+// If conditions that can not be compiled.
+type AccessBindingValidator struct{}
+
+var _ admission.CustomValidator = &AccessBindingValidator{}
+
+// ValidateCreate implements admission.CustomValidator
+func (v *AccessBindingValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, v.validate(obj)
+}
+
+// ValidateUpdate implements admission.CustomValidator
+func (v *AccessBindingValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	return nil, v.validate(newObj)
+}
+
+// ValidateDelete implements admission.CustomValidator
+func (v *AccessBindingValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *AccessBindingValidator) validate(obj runtime.Object) error {
+	ab, ok := obj.(*api.AccessBinding)
+	if !ok {
+		return fmt.Errorf("expected an AccessBinding but got %T", obj)
+	}
+	errs := field.ErrorList{}
+	subjectsPath := field.NewPath("spec", "subjects")
+	if len(ab.Spec.Subjects) == 0 {
+		errs = append(errs, field.Required(subjectsPath, "at least one subject must be provided"))
+	}
+	for idx, subject := range ab.Spec.Subjects {
+		if strings.TrimSpace(subject) == "" {
+			errs = append(errs, field.Invalid(subjectsPath.Index(idx), subject, "subject must not be empty"))
+		}
+	}
+	if ab.Spec.If != nil {
+		_, err := expr.Compile(*ab.Spec.If, expr.AsBool())
+		if err != nil {
+			errs = append(errs, field.Invalid(field.NewPath("spec", "if"), *ab.Spec.If, fmt.Sprintf("error compiling condition: %s", err)))
+		}
+	}
+	if len(errs) > 0 {
+		return apierrors.NewInvalid(api.GroupVersion.WithKind("AccessBinding").GroupKind(), ab.GetName(), errs)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	api "github.com/argoproj-labs/ephemeral-access/api/ephemeral-access/v1alpha1"
+)
+
+// +kubebuilder:webhook:path=/validate-ephemeral-access-argoproj-labs-io-v1alpha1-accessrequest,mutating=false,failurePolicy=fail,sideEffects=None,groups=ephemeral-access.argoproj-labs.io,resources=accessrequests,verbs=create;update,versions=v1alpha1,name=vaccessrequest.ephemeral-access.argoproj-labs.io,admissionReviewVersions=v1
+
+// AccessRequestValidator rejects AccessRequests without subject, with
+// invalid durations or duplicating another in-flight AccessRequest for the
+// same user, application and role.
+type AccessRequestValidator struct {
+	reader      client.Reader
+	maxDuration time.Duration
+}
+
+var _ admission.CustomValidator = &AccessRequestValidator{}
+
+// NewAccessRequestValidator will return a new AccessRequestValidator using
+// the given reader to find duplicated AccessRequests. AccessRequests with
+// durations longer than the given maxDuration are rejected. If maxDuration
+// is zero, the duration will not be limited.
+func NewAccessRequestValidator(reader client.Reader, maxDuration time.Duration) *AccessRequestValidator {
+	return &AccessRequestValidator{
+		reader:      reader,
+		maxDuration: maxDuration,
+	}
+}
+
+// ValidateCreate implements admission.CustomValidator
+func (v *AccessRequestValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	ar, ok := obj.(*api.AccessRequest)
+	if !ok {
+		return nil, fmt.Errorf("expected an AccessRequest but got %T", obj)
+	}
+	errs := v.validateSpec(ar)
+	dupErr, err := v.validateDuplicates(ctx, ar)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	if dupErr != nil {
+		errs = append(errs, dupErr)
+	}
+	return nil, toInvalidError(ar, errs)
+}
+
+// ValidateUpdate implements admission.CustomValidator. The subject and the
+// duration are only validated if changed to allow updating existing
+// AccessRequests (e.g. revocation and extensions).
+func (v *AccessRequestValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldAr, ok := oldObj.(*api.AccessRequest)
+	if !ok {
+		return nil, fmt.Errorf("expected an AccessRequest but got %T", oldObj)
+	}
+	ar, ok := newObj.(*api.AccessRequest)
+	if !ok {
+		return nil, fmt.Errorf("expected an AccessRequest but got %T", newObj)
+	}
+	if equality.Semantic.DeepEqual(oldAr.Spec.Subject, ar.Spec.Subject) &&
+		oldAr.Spec.Duration == ar.Spec.Duration {
+		return nil, nil
+	}
+	return nil, toInvalidError(ar, v.validateSpec(ar))
+}
+
+// ValidateDelete implements admission.CustomValidator
+func (v *AccessRequestValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *AccessRequestValidator) validateSpec(ar *api.AccessRequest) field.ErrorList {
+	errs := field.ErrorList{}
+	if ar.Spec.Subject.Username == "" {
+		errs = append(errs, field.Required(field.NewPath("spec", "subject", "username"), "subject username must be provided"))
+	}
+	hasApp := ar.Spec.Application.Name != ""
+	switch {
+	case ar.IsProjectScoped() && hasApp:
+		errs = append(errs, field.Invalid(field.NewPath("spec", "project"), ar.Spec.Project.Name, "project can not be provided together with application"))
+	case ar.IsProjectScoped() && ar.Spec.Project.Name == "":
+		errs = append(errs, field.Required(field.NewPath("spec", "project", "name"), "project name must be provided"))
+	case !ar.IsProjectScoped() && !hasApp:
+		errs = append(errs, field.Required(field.NewPath("spec", "application", "name"), "application or project must be provided"))
+	}
+	durationPath := field.NewPath("spec", "duration")
+	duration := ar.Spec.Duration.Duration
+	if duration <= 0 {
+		errs = append(errs, field.Invalid(durationPath, duration.String(), "duration must be positive"))
+	}
+	if v.maxDuration > 0 && duration > v.maxDuration {
+		errs = append(errs, field.Invalid(durationPath, duration.String(), fmt.Sprintf("duration must not exceed %s", v.maxDuration)))
+	}
+	return errs
+}
+
+// validateDuplicates will verify if there is another AccessRequest for the
+// same subject principal (user or group), target (application or project) and
+// role template that is pending or granted.
+func (v *AccessRequestValidator) validateDuplicates(ctx context.Context, ar *api.AccessRequest) (*field.Error, error) {
+	list := &api.AccessRequestList{}
+	err := v.reader.List(ctx, list, client.InNamespace(ar.GetNamespace()))
+	if err != nil {
+		return nil, fmt.Errorf("error listing AccessRequests: %w", err)
+	}
+	for _, existing := range list.Items {
+		if existing.GetName() == ar.GetName() ||
+			existing.Spec.Subject.Principal() != ar.Spec.Subject.Principal() ||
+			existing.Spec.Application != ar.Spec.Application ||
+			targetProjectName(&existing) != targetProjectName(ar) ||
+			existing.Spec.Role.TemplateRef != ar.Spec.Role.TemplateRef {
+			continue
+		}
+		switch existing.Status.RequestState {
+		case "", api.RequestedStatus, api.ScheduledStatus, api.GrantedStatus:
+			state := string(existing.Status.RequestState)
+			if state == "" {
+				state = "pending"
+			}
+			msg := fmt.Sprintf("found existing AccessRequest (%s/%s) in %s state", existing.GetNamespace(), existing.GetName(), state)
+			return field.Forbidden(field.NewPath("spec"), msg), nil
+		}
+	}
+	return nil, nil
+}
+
+// targetProjectName returns the AppProject name targeted by the given
+// project-scoped ar or empty otherwise.
+func targetProjectName(ar *api.AccessRequest) string {
+	if !ar.IsProjectScoped() {
+		return ""
+	}
+	return ar.Spec.Project.Name
+}
+
+func toInvalidError(ar *api.AccessRequest, errs field.ErrorList) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(api.GroupVersion.WithKind("AccessRequest").GroupKind(), ar.GetName(), errs)
+}
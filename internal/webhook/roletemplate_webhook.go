@@ -0,0 +1,49 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	api "github.com/argoproj-labs/ephemeral-access/api/ephemeral-access/v1alpha1"
+)
+
+// +kubebuilder:webhook:path=/validate-ephemeral-access-argoproj-labs-io-v1alpha1-roletemplate,mutating=false,failurePolicy=fail,sideEffects=None,groups=ephemeral-access.argoproj-labs.io,resources=roletemplates,verbs=create;update,versions=v1alpha1,name=vroletemplate.ephemeral-access.argoproj-labs.io,admissionReviewVersions=v1
+
+// RoleTemplateValidator rejects RoleTemplates with templates that can not
+// be rendered or that produce invalid Argo CD policies.
+type RoleTemplateValidator struct{}
+
+var _ admission.CustomValidator = &RoleTemplateValidator{}
+
+// ValidateCreate implements admission.CustomValidator
+func (v *RoleTemplateValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, v.validate(obj)
+}
+
+// ValidateUpdate implements admission.CustomValidator
+func (v *RoleTemplateValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	return nil, v.validate(newObj)
+}
+
+// ValidateDelete implements admission.CustomValidator
+func (v *RoleTemplateValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *RoleTemplateValidator) validate(obj runtime.Object) error {
+	rt, ok := obj.(*api.RoleTemplate)
+	if !ok {
+		return fmt.Errorf("expected a RoleTemplate but got %T", obj)
+	}
+	err := rt.Validate()
+	if err != nil {
+		errs := field.ErrorList{field.Invalid(field.NewPath("spec"), rt.Spec.Name, err.Error())}
+		return apierrors.NewInvalid(api.GroupVersion.WithKind("RoleTemplate").GroupKind(), rt.GetName(), errs)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+// Package webhook provides the validating admission webhooks for the
+// ephemeral-access resources.
+package webhook
+
+import (
+	"fmt"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	api "github.com/argoproj-labs/ephemeral-access/api/ephemeral-access/v1alpha1"
+)
+
+// SetupWithManager will register the validating webhooks for AccessRequests,
+// RoleTemplates and AccessBindings in the given mgr webhook server. The given
+// maxDuration defines the maximum duration allowed in AccessRequests. If
+// zero, the duration will not be limited.
+func SetupWithManager(mgr ctrl.Manager, maxDuration time.Duration) error {
+	err := ctrl.NewWebhookManagedBy(mgr).
+		For(&api.AccessRequest{}).
+		WithValidator(NewAccessRequestValidator(mgr.GetClient(), maxDuration)).
+		Complete()
+	if err != nil {
+		return fmt.Errorf("error creating AccessRequest webhook: %w", err)
+	}
+	err = ctrl.NewWebhookManagedBy(mgr).
+		For(&api.RoleTemplate{}).
+		WithValidator(&RoleTemplateValidator{}).
+		Complete()
+	if err != nil {
+		return fmt.Errorf("error creating RoleTemplate webhook: %w", err)
+	}
+	err = ctrl.NewWebhookManagedBy(mgr).
+		For(&api.AccessBinding{}).
+		WithValidator(&AccessBindingValidator{}).
+		Complete()
+	if err != nil {
+		return fmt.Errorf("error creating AccessBinding webhook: %w", err)
+	}
+	return nil
+}
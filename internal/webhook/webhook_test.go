@@ -0,0 +1,324 @@
+package webhook_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	api "github.com/argoproj-labs/ephemeral-access/api/ephemeral-access/v1alpha1"
+	"github.com/argoproj-labs/ephemeral-access/internal/webhook"
+	"github.com/argoproj-labs/ephemeral-access/test/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newFakeClient(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, api.AddToScheme(scheme))
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+}
+
+func newAccessRequest() *api.AccessRequest {
+	ar := utils.NewAccessRequestCreated()
+	ar.Spec.Duration = metav1.Duration{Duration: time.Minute}
+	return ar
+}
+
+func TestAccessRequestValidator(t *testing.T) {
+	t.Run("will allow valid AccessRequest", func(t *testing.T) {
+		// Given
+		validator := webhook.NewAccessRequestValidator(newFakeClient(t), time.Hour)
+		ar := newAccessRequest()
+
+		// When
+		_, err := validator.ValidateCreate(context.Background(), ar)
+
+		// Then
+		assert.NoError(t, err)
+	})
+	t.Run("will reject AccessRequest without subject", func(t *testing.T) {
+		// Given
+		validator := webhook.NewAccessRequestValidator(newFakeClient(t), time.Hour)
+		ar := newAccessRequest()
+		ar.Spec.Subject.Username = ""
+
+		// When
+		_, err := validator.ValidateCreate(context.Background(), ar)
+
+		// Then
+		assert.Error(t, err)
+		assert.True(t, apierrors.IsInvalid(err))
+		assert.Contains(t, err.Error(), "spec.subject.username")
+	})
+	t.Run("will allow project-scoped AccessRequest", func(t *testing.T) {
+		// Given
+		validator := webhook.NewAccessRequestValidator(newFakeClient(t), time.Hour)
+		ar := newAccessRequest()
+		ar.Spec.Application = api.TargetApplication{}
+		ar.Spec.Project = &api.TargetAppProject{Name: "some-project"}
+
+		// When
+		_, err := validator.ValidateCreate(context.Background(), ar)
+
+		// Then
+		assert.NoError(t, err)
+	})
+	t.Run("will reject AccessRequest with both application and project", func(t *testing.T) {
+		// Given
+		validator := webhook.NewAccessRequestValidator(newFakeClient(t), time.Hour)
+		ar := newAccessRequest()
+		ar.Spec.Project = &api.TargetAppProject{Name: "some-project"}
+
+		// When
+		_, err := validator.ValidateCreate(context.Background(), ar)
+
+		// Then
+		assert.Error(t, err)
+		assert.True(t, apierrors.IsInvalid(err))
+		assert.Contains(t, err.Error(), "project can not be provided together with application")
+	})
+	t.Run("will reject AccessRequest without application and project", func(t *testing.T) {
+		// Given
+		validator := webhook.NewAccessRequestValidator(newFakeClient(t), time.Hour)
+		ar := newAccessRequest()
+		ar.Spec.Application = api.TargetApplication{}
+
+		// When
+		_, err := validator.ValidateCreate(context.Background(), ar)
+
+		// Then
+		assert.Error(t, err)
+		assert.True(t, apierrors.IsInvalid(err))
+		assert.Contains(t, err.Error(), "application or project must be provided")
+	})
+	t.Run("will reject AccessRequest with non-positive duration", func(t *testing.T) {
+		// Given
+		validator := webhook.NewAccessRequestValidator(newFakeClient(t), time.Hour)
+		ar := newAccessRequest()
+		ar.Spec.Duration = metav1.Duration{Duration: 0}
+
+		// When
+		_, err := validator.ValidateCreate(context.Background(), ar)
+
+		// Then
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "duration must be positive")
+	})
+	t.Run("will reject AccessRequest exceeding the max duration", func(t *testing.T) {
+		// Given
+		validator := webhook.NewAccessRequestValidator(newFakeClient(t), time.Hour)
+		ar := newAccessRequest()
+		ar.Spec.Duration = metav1.Duration{Duration: 2 * time.Hour}
+
+		// When
+		_, err := validator.ValidateCreate(context.Background(), ar)
+
+		// Then
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "duration must not exceed 1h0m0s")
+	})
+	t.Run("will not limit the duration if max duration is zero", func(t *testing.T) {
+		// Given
+		validator := webhook.NewAccessRequestValidator(newFakeClient(t), 0)
+		ar := newAccessRequest()
+		ar.Spec.Duration = metav1.Duration{Duration: 1000 * time.Hour}
+
+		// When
+		_, err := validator.ValidateCreate(context.Background(), ar)
+
+		// Then
+		assert.NoError(t, err)
+	})
+	t.Run("will reject duplicated in-flight AccessRequest", func(t *testing.T) {
+		// Given
+		existing := utils.NewAccessRequestGranted(utils.WithName("existing"))
+		validator := webhook.NewAccessRequestValidator(newFakeClient(t, existing), time.Hour)
+		ar := newAccessRequest()
+
+		// When
+		_, err := validator.ValidateCreate(context.Background(), ar)
+
+		// Then
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "found existing AccessRequest")
+		assert.Contains(t, err.Error(), "in granted state")
+	})
+	t.Run("will allow AccessRequest if existing one is concluded", func(t *testing.T) {
+		// Given
+		existing := utils.NewAccessRequestExpired(utils.WithName("existing"))
+		validator := webhook.NewAccessRequestValidator(newFakeClient(t, existing), time.Hour)
+		ar := newAccessRequest()
+
+		// When
+		_, err := validator.ValidateCreate(context.Background(), ar)
+
+		// Then
+		assert.NoError(t, err)
+	})
+	t.Run("will allow AccessRequest if existing one is for a different role", func(t *testing.T) {
+		// Given
+		existing := utils.NewAccessRequestRequested(utils.WithName("existing"))
+		existing.Spec.Role.TemplateRef.Name = "other-role"
+		validator := webhook.NewAccessRequestValidator(newFakeClient(t, existing), time.Hour)
+		ar := newAccessRequest()
+
+		// When
+		_, err := validator.ValidateCreate(context.Background(), ar)
+
+		// Then
+		assert.NoError(t, err)
+	})
+	t.Run("will allow project-scoped AccessRequest if existing one is for a different project", func(t *testing.T) {
+		// Given
+		existing := utils.NewAccessRequestRequested(utils.WithName("existing"))
+		existing.Spec.Application = api.TargetApplication{}
+		existing.Spec.Project = &api.TargetAppProject{Name: "other-project"}
+		validator := webhook.NewAccessRequestValidator(newFakeClient(t, existing), time.Hour)
+		ar := newAccessRequest()
+		ar.Spec.Application = api.TargetApplication{}
+		ar.Spec.Project = &api.TargetAppProject{Name: "some-project"}
+
+		// When
+		_, err := validator.ValidateCreate(context.Background(), ar)
+
+		// Then
+		assert.NoError(t, err)
+	})
+	t.Run("will allow group AccessRequest if existing one targets the user", func(t *testing.T) {
+		// Given
+		existing := utils.NewAccessRequestRequested(utils.WithName("existing"))
+		validator := webhook.NewAccessRequestValidator(newFakeClient(t, existing), time.Hour)
+		ar := newAccessRequest()
+		ar.Spec.Subject.Group = "on-call"
+
+		// When
+		_, err := validator.ValidateCreate(context.Background(), ar)
+
+		// Then
+		assert.NoError(t, err)
+	})
+	t.Run("will allow updates that do not change subject and duration", func(t *testing.T) {
+		// Given
+		validator := webhook.NewAccessRequestValidator(newFakeClient(t), time.Hour)
+		oldAr := utils.NewAccessRequestGranted()
+		oldAr.Spec.Duration = metav1.Duration{Duration: 2 * time.Hour}
+		ar := oldAr.DeepCopy()
+		ar.Spec.Revocation = &api.Revocation{Revoker: "admin"}
+
+		// When
+		_, err := validator.ValidateUpdate(context.Background(), oldAr, ar)
+
+		// Then
+		assert.NoError(t, err)
+	})
+}
+
+func TestRoleTemplateValidator(t *testing.T) {
+	t.Run("will allow valid RoleTemplate", func(t *testing.T) {
+		// Given
+		validator := &webhook.RoleTemplateValidator{}
+		rt := utils.NewRoleTemplate("test", "ns", "role", []string{
+			"p, {{.role}}, applications, sync, {{.project}}/{{.application}}, allow",
+		})
+
+		// When
+		_, err := validator.ValidateCreate(context.Background(), rt)
+
+		// Then
+		assert.NoError(t, err)
+	})
+	t.Run("will reject RoleTemplate with unparseable template", func(t *testing.T) {
+		// Given
+		validator := &webhook.RoleTemplateValidator{}
+		rt := utils.NewRoleTemplate("test", "ns", "role", []string{
+			"p, {{.role, applications, sync, {{.project}}/{{.application}}, allow",
+		})
+
+		// When
+		_, err := validator.ValidateUpdate(context.Background(), rt, rt)
+
+		// Then
+		assert.Error(t, err)
+		assert.True(t, apierrors.IsInvalid(err))
+		assert.Contains(t, err.Error(), "error parsing RoleTemplate policies")
+	})
+}
+
+func TestAccessBindingValidator(t *testing.T) {
+	newAccessBinding := func(subjects []string, condition *string) *api.AccessBinding {
+		return &api.AccessBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: "test", Namespace: "ns"},
+			Spec: api.AccessBindingSpec{
+				RoleTemplateRef: api.RoleTemplateReference{Name: "role"},
+				Subjects:        subjects,
+				If:              condition,
+			},
+		}
+	}
+	t.Run("will allow valid AccessBinding", func(t *testing.T) {
+		// Given
+		validator := &webhook.AccessBindingValidator{}
+		ab := newAccessBinding([]string{"group"}, ptr.To("app.metadata.name == 'test'"))
+
+		// When
+		_, err := validator.ValidateCreate(context.Background(), ab)
+
+		// Then
+		assert.NoError(t, err)
+	})
+	t.Run("will reject AccessBinding without subjects", func(t *testing.T) {
+		// Given
+		validator := &webhook.AccessBindingValidator{}
+		ab := newAccessBinding(nil, nil)
+
+		// When
+		_, err := validator.ValidateCreate(context.Background(), ab)
+
+		// Then
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "at least one subject must be provided")
+	})
+	t.Run("will reject AccessBinding with empty subject", func(t *testing.T) {
+		// Given
+		validator := &webhook.AccessBindingValidator{}
+		ab := newAccessBinding([]string{"group", " "}, nil)
+
+		// When
+		_, err := validator.ValidateCreate(context.Background(), ab)
+
+		// Then
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "spec.subjects[1]")
+	})
+	t.Run("will reject AccessBinding with invalid condition", func(t *testing.T) {
+		// Given
+		validator := &webhook.AccessBindingValidator{}
+		ab := newAccessBinding([]string{"group"}, ptr.To("app.metadata.name =="))
+
+		// When
+		_, err := validator.ValidateUpdate(context.Background(), ab, ab)
+
+		// Then
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "error compiling condition")
+	})
+	t.Run("will reject AccessBinding with non-boolean condition", func(t *testing.T) {
+		// Given
+		validator := &webhook.AccessBindingValidator{}
+		ab := newAccessBinding([]string{"group"}, ptr.To("'string'"))
+
+		// When
+		_, err := validator.ValidateCreate(context.Background(), ab)
+
+		// Then
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "error compiling condition")
+	})
+}
@@ -40,7 +40,7 @@ func (pe *PluginError) Error() string {
 }
 
 // AccessRequester defines the main interface that should be implemented by
-// ephemeral access plugins.
+// ephemeral access plugins. The app is nil for project-scoped AccessRequests.
 type AccessRequester interface {
 	Init() error
 	GrantAccess(ar *api.AccessRequest, app *argocd.Application) (*GrantResponse, error)
@@ -153,6 +153,31 @@ func TestAccessRequesterRPC(t *testing.T) {
 		f.accessRequesterMock.AssertNumberOfCalls(t, "GrantAccess", 1)
 		f.accessRequesterMock.AssertNumberOfCalls(t, "RevokeAccess", 0)
 	})
+	t.Run("will send the AccessRequest justification and ticket reference to GrantAccess", func(t *testing.T) {
+		// Given
+		f := newFixture(t)
+		defer f.cancel()
+		ar := newAccessRequest("some-ar", "some-ns", "some-roletmpl", "some-user")
+		ar.Spec.Justification = "incident response"
+		ar.Spec.TicketRef = "INC-123"
+		app := newApplication("some-project")
+		var receivedAr *api.AccessRequest
+		runFn := func(ar *api.AccessRequest, app *argocd.Application) (*plugin.GrantResponse, error) {
+			receivedAr = ar
+			return &plugin.GrantResponse{Status: plugin.Granted}, nil
+		}
+		f.accessRequesterMock.EXPECT().GrantAccess(ar, app).
+			RunAndReturn(runFn)
+
+		// When
+		_, err := f.client.GrantAccess(ar, app)
+
+		// Then
+		assert.NoError(t, err)
+		assert.NotNil(t, receivedAr)
+		assert.Equal(t, "incident response", receivedAr.Spec.Justification)
+		assert.Equal(t, "INC-123", receivedAr.Spec.TicketRef)
+	})
 	t.Run("will validate GrantAccess properly returns error", func(t *testing.T) {
 		// Given
 		f := newFixture(t)
@@ -21,6 +21,186 @@ func (_m *MockConfigurer) EXPECT() *MockConfigurer_Expecter {
 	return &MockConfigurer_Expecter{mock: &_m.Mock}
 }
 
+// ControllerAllowUnsigned provides a mock function with given fields:
+func (_m *MockConfigurer) ControllerAllowUnsigned() bool {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ControllerAllowUnsigned")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// MockConfigurer_ControllerAllowUnsigned_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ControllerAllowUnsigned'
+type MockConfigurer_ControllerAllowUnsigned_Call struct {
+	*mock.Call
+}
+
+// ControllerAllowUnsigned is a helper method to define mock.On call
+func (_e *MockConfigurer_Expecter) ControllerAllowUnsigned() *MockConfigurer_ControllerAllowUnsigned_Call {
+	return &MockConfigurer_ControllerAllowUnsigned_Call{Call: _e.mock.On("ControllerAllowUnsigned")}
+}
+
+func (_c *MockConfigurer_ControllerAllowUnsigned_Call) Run(run func()) *MockConfigurer_ControllerAllowUnsigned_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockConfigurer_ControllerAllowUnsigned_Call) Return(_a0 bool) *MockConfigurer_ControllerAllowUnsigned_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockConfigurer_ControllerAllowUnsigned_Call) RunAndReturn(run func() bool) *MockConfigurer_ControllerAllowUnsigned_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ControllerArchiveDir provides a mock function with given fields:
+func (_m *MockConfigurer) ControllerArchiveDir() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ControllerArchiveDir")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// MockConfigurer_ControllerArchiveDir_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ControllerArchiveDir'
+type MockConfigurer_ControllerArchiveDir_Call struct {
+	*mock.Call
+}
+
+// ControllerArchiveDir is a helper method to define mock.On call
+func (_e *MockConfigurer_Expecter) ControllerArchiveDir() *MockConfigurer_ControllerArchiveDir_Call {
+	return &MockConfigurer_ControllerArchiveDir_Call{Call: _e.mock.On("ControllerArchiveDir")}
+}
+
+func (_c *MockConfigurer_ControllerArchiveDir_Call) Run(run func()) *MockConfigurer_ControllerArchiveDir_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockConfigurer_ControllerArchiveDir_Call) Return(_a0 string) *MockConfigurer_ControllerArchiveDir_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockConfigurer_ControllerArchiveDir_Call) RunAndReturn(run func() string) *MockConfigurer_ControllerArchiveDir_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ControllerArchiveURL provides a mock function with given fields:
+func (_m *MockConfigurer) ControllerArchiveURL() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ControllerArchiveURL")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// MockConfigurer_ControllerArchiveURL_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ControllerArchiveURL'
+type MockConfigurer_ControllerArchiveURL_Call struct {
+	*mock.Call
+}
+
+// ControllerArchiveURL is a helper method to define mock.On call
+func (_e *MockConfigurer_Expecter) ControllerArchiveURL() *MockConfigurer_ControllerArchiveURL_Call {
+	return &MockConfigurer_ControllerArchiveURL_Call{Call: _e.mock.On("ControllerArchiveURL")}
+}
+
+func (_c *MockConfigurer_ControllerArchiveURL_Call) Run(run func()) *MockConfigurer_ControllerArchiveURL_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockConfigurer_ControllerArchiveURL_Call) Return(_a0 string) *MockConfigurer_ControllerArchiveURL_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockConfigurer_ControllerArchiveURL_Call) RunAndReturn(run func() string) *MockConfigurer_ControllerArchiveURL_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ControllerConcludedTTL provides a mock function with given fields:
+func (_m *MockConfigurer) ControllerConcludedTTL() time.Duration {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ControllerConcludedTTL")
+	}
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// MockConfigurer_ControllerConcludedTTL_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ControllerConcludedTTL'
+type MockConfigurer_ControllerConcludedTTL_Call struct {
+	*mock.Call
+}
+
+// ControllerConcludedTTL is a helper method to define mock.On call
+func (_e *MockConfigurer_Expecter) ControllerConcludedTTL() *MockConfigurer_ControllerConcludedTTL_Call {
+	return &MockConfigurer_ControllerConcludedTTL_Call{Call: _e.mock.On("ControllerConcludedTTL")}
+}
+
+func (_c *MockConfigurer_ControllerConcludedTTL_Call) Run(run func()) *MockConfigurer_ControllerConcludedTTL_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockConfigurer_ControllerConcludedTTL_Call) Return(_a0 time.Duration) *MockConfigurer_ControllerConcludedTTL_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockConfigurer_ControllerConcludedTTL_Call) RunAndReturn(run func() time.Duration) *MockConfigurer_ControllerConcludedTTL_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ControllerEnableHTTP2 provides a mock function with given fields:
 func (_m *MockConfigurer) ControllerEnableHTTP2() bool {
 	ret := _m.Called()
@@ -201,6 +381,233 @@ func (_c *MockConfigurer_ControllerRequeueInterval_Call) RunAndReturn(run func()
 	return _c
 }
 
+// ControllerRequireSignature provides a mock function with given fields:
+func (_m *MockConfigurer) ControllerRequireSignature() bool {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ControllerRequireSignature")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// MockConfigurer_ControllerRequireSignature_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ControllerRequireSignature'
+type MockConfigurer_ControllerRequireSignature_Call struct {
+	*mock.Call
+}
+
+// ControllerRequireSignature is a helper method to define mock.On call
+func (_e *MockConfigurer_Expecter) ControllerRequireSignature() *MockConfigurer_ControllerRequireSignature_Call {
+	return &MockConfigurer_ControllerRequireSignature_Call{Call: _e.mock.On("ControllerRequireSignature")}
+}
+
+func (_c *MockConfigurer_ControllerRequireSignature_Call) Run(run func()) *MockConfigurer_ControllerRequireSignature_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockConfigurer_ControllerRequireSignature_Call) Return(_a0 bool) *MockConfigurer_ControllerRequireSignature_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockConfigurer_ControllerRequireSignature_Call) RunAndReturn(run func() bool) *MockConfigurer_ControllerRequireSignature_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ControllerRoleSweeperDryRun provides a mock function with given fields:
+func (_m *MockConfigurer) ControllerRoleSweeperDryRun() bool {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ControllerRoleSweeperDryRun")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// MockConfigurer_ControllerRoleSweeperDryRun_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ControllerRoleSweeperDryRun'
+type MockConfigurer_ControllerRoleSweeperDryRun_Call struct {
+	*mock.Call
+}
+
+// ControllerRoleSweeperDryRun is a helper method to define mock.On call
+func (_e *MockConfigurer_Expecter) ControllerRoleSweeperDryRun() *MockConfigurer_ControllerRoleSweeperDryRun_Call {
+	return &MockConfigurer_ControllerRoleSweeperDryRun_Call{Call: _e.mock.On("ControllerRoleSweeperDryRun")}
+}
+
+func (_c *MockConfigurer_ControllerRoleSweeperDryRun_Call) Run(run func()) *MockConfigurer_ControllerRoleSweeperDryRun_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockConfigurer_ControllerRoleSweeperDryRun_Call) Return(_a0 bool) *MockConfigurer_ControllerRoleSweeperDryRun_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockConfigurer_ControllerRoleSweeperDryRun_Call) RunAndReturn(run func() bool) *MockConfigurer_ControllerRoleSweeperDryRun_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ControllerRoleSweeperInterval provides a mock function with given fields:
+func (_m *MockConfigurer) ControllerRoleSweeperInterval() time.Duration {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ControllerRoleSweeperInterval")
+	}
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// MockConfigurer_ControllerRoleSweeperInterval_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ControllerRoleSweeperInterval'
+type MockConfigurer_ControllerRoleSweeperInterval_Call struct {
+	*mock.Call
+}
+
+// ControllerRoleSweeperInterval is a helper method to define mock.On call
+func (_e *MockConfigurer_Expecter) ControllerRoleSweeperInterval() *MockConfigurer_ControllerRoleSweeperInterval_Call {
+	return &MockConfigurer_ControllerRoleSweeperInterval_Call{Call: _e.mock.On("ControllerRoleSweeperInterval")}
+}
+
+func (_c *MockConfigurer_ControllerRoleSweeperInterval_Call) Run(run func()) *MockConfigurer_ControllerRoleSweeperInterval_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockConfigurer_ControllerRoleSweeperInterval_Call) Return(_a0 time.Duration) *MockConfigurer_ControllerRoleSweeperInterval_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockConfigurer_ControllerRoleSweeperInterval_Call) RunAndReturn(run func() time.Duration) *MockConfigurer_ControllerRoleSweeperInterval_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ControllerServerSideApply provides a mock function with given fields:
+func (_m *MockConfigurer) ControllerServerSideApply() bool {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ControllerServerSideApply")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// MockConfigurer_ControllerServerSideApply_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ControllerServerSideApply'
+type MockConfigurer_ControllerServerSideApply_Call struct {
+	*mock.Call
+}
+
+// ControllerServerSideApply is a helper method to define mock.On call
+func (_e *MockConfigurer_Expecter) ControllerServerSideApply() *MockConfigurer_ControllerServerSideApply_Call {
+	return &MockConfigurer_ControllerServerSideApply_Call{Call: _e.mock.On("ControllerServerSideApply")}
+}
+
+func (_c *MockConfigurer_ControllerServerSideApply_Call) Run(run func()) *MockConfigurer_ControllerServerSideApply_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockConfigurer_ControllerServerSideApply_Call) Return(_a0 bool) *MockConfigurer_ControllerServerSideApply_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockConfigurer_ControllerServerSideApply_Call) RunAndReturn(run func() bool) *MockConfigurer_ControllerServerSideApply_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ControllerSigningKey provides a mock function with given fields:
+func (_m *MockConfigurer) ControllerSigningKey() []byte {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for ControllerSigningKey")
+	}
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func() []byte); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	return r0
+}
+
+// MockConfigurer_ControllerSigningKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ControllerSigningKey'
+type MockConfigurer_ControllerSigningKey_Call struct {
+	*mock.Call
+}
+
+// ControllerSigningKey is a helper method to define mock.On call
+func (_e *MockConfigurer_Expecter) ControllerSigningKey() *MockConfigurer_ControllerSigningKey_Call {
+	return &MockConfigurer_ControllerSigningKey_Call{Call: _e.mock.On("ControllerSigningKey")}
+}
+
+func (_c *MockConfigurer_ControllerSigningKey_Call) Run(run func()) *MockConfigurer_ControllerSigningKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockConfigurer_ControllerSigningKey_Call) Return(_a0 []byte) *MockConfigurer_ControllerSigningKey_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockConfigurer_ControllerSigningKey_Call) RunAndReturn(run func() []byte) *MockConfigurer_ControllerSigningKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // EnableLeaderElection provides a mock function with given fields:
 func (_m *MockConfigurer) EnableLeaderElection() bool {
 	ret := _m.Called()
@@ -426,6 +833,141 @@ func (_c *MockConfigurer_MetricsSecure_Call) RunAndReturn(run func() bool) *Mock
 	return _c
 }
 
+// PluginPath provides a mock function with given fields:
+func (_m *MockConfigurer) PluginPath() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for PluginPath")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// MockConfigurer_PluginPath_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PluginPath'
+type MockConfigurer_PluginPath_Call struct {
+	*mock.Call
+}
+
+// PluginPath is a helper method to define mock.On call
+func (_e *MockConfigurer_Expecter) PluginPath() *MockConfigurer_PluginPath_Call {
+	return &MockConfigurer_PluginPath_Call{Call: _e.mock.On("PluginPath")}
+}
+
+func (_c *MockConfigurer_PluginPath_Call) Run(run func()) *MockConfigurer_PluginPath_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockConfigurer_PluginPath_Call) Return(_a0 string) *MockConfigurer_PluginPath_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockConfigurer_PluginPath_Call) RunAndReturn(run func() string) *MockConfigurer_PluginPath_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// WebhookEnabled provides a mock function with given fields:
+func (_m *MockConfigurer) WebhookEnabled() bool {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for WebhookEnabled")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// MockConfigurer_WebhookEnabled_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WebhookEnabled'
+type MockConfigurer_WebhookEnabled_Call struct {
+	*mock.Call
+}
+
+// WebhookEnabled is a helper method to define mock.On call
+func (_e *MockConfigurer_Expecter) WebhookEnabled() *MockConfigurer_WebhookEnabled_Call {
+	return &MockConfigurer_WebhookEnabled_Call{Call: _e.mock.On("WebhookEnabled")}
+}
+
+func (_c *MockConfigurer_WebhookEnabled_Call) Run(run func()) *MockConfigurer_WebhookEnabled_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockConfigurer_WebhookEnabled_Call) Return(_a0 bool) *MockConfigurer_WebhookEnabled_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockConfigurer_WebhookEnabled_Call) RunAndReturn(run func() bool) *MockConfigurer_WebhookEnabled_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// WebhookMaxAccessDuration provides a mock function with given fields:
+func (_m *MockConfigurer) WebhookMaxAccessDuration() time.Duration {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for WebhookMaxAccessDuration")
+	}
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// MockConfigurer_WebhookMaxAccessDuration_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WebhookMaxAccessDuration'
+type MockConfigurer_WebhookMaxAccessDuration_Call struct {
+	*mock.Call
+}
+
+// WebhookMaxAccessDuration is a helper method to define mock.On call
+func (_e *MockConfigurer_Expecter) WebhookMaxAccessDuration() *MockConfigurer_WebhookMaxAccessDuration_Call {
+	return &MockConfigurer_WebhookMaxAccessDuration_Call{Call: _e.mock.On("WebhookMaxAccessDuration")}
+}
+
+func (_c *MockConfigurer_WebhookMaxAccessDuration_Call) Run(run func()) *MockConfigurer_WebhookMaxAccessDuration_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockConfigurer_WebhookMaxAccessDuration_Call) Return(_a0 time.Duration) *MockConfigurer_WebhookMaxAccessDuration_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockConfigurer_WebhookMaxAccessDuration_Call) RunAndReturn(run func() time.Duration) *MockConfigurer_WebhookMaxAccessDuration_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockConfigurer creates a new instance of MockConfigurer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockConfigurer(t interface {
@@ -88,6 +88,68 @@ func (_c *MockK8sClient_Get_Call) RunAndReturn(run func(context.Context, types.N
 	return _c
 }
 
+// List provides a mock function with given fields: ctx, list, opts
+func (_m *MockK8sClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, list)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, client.ObjectList, ...client.ListOption) error); ok {
+		r0 = rf(ctx, list, opts...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockK8sClient_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type MockK8sClient_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+//   - list client.ObjectList
+//   - opts ...client.ListOption
+func (_e *MockK8sClient_Expecter) List(ctx interface{}, list interface{}, opts ...interface{}) *MockK8sClient_List_Call {
+	return &MockK8sClient_List_Call{Call: _e.mock.On("List",
+		append([]interface{}{ctx, list}, opts...)...)}
+}
+
+func (_c *MockK8sClient_List_Call) Run(run func(ctx context.Context, list client.ObjectList, opts ...client.ListOption)) *MockK8sClient_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]client.ListOption, len(args)-2)
+		for i, a := range args[2:] {
+			if a != nil {
+				variadicArgs[i] = a.(client.ListOption)
+			}
+		}
+		run(args[0].(context.Context), args[1].(client.ObjectList), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockK8sClient_List_Call) Return(_a0 error) *MockK8sClient_List_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockK8sClient_List_Call) RunAndReturn(run func(context.Context, client.ObjectList, ...client.ListOption) error) *MockK8sClient_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Patch provides a mock function with given fields: ctx, obj, patch, opts
 func (_m *MockK8sClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
 	_va := make([]interface{}, len(opts))
@@ -86,6 +86,66 @@ func (_c *MockPersister_CreateAccessRequest_Call) RunAndReturn(run func(context.
 	return _c
 }
 
+// GetAccessRequest provides a mock function with given fields: ctx, name, namespace
+func (_m *MockPersister) GetAccessRequest(ctx context.Context, name string, namespace string) (*v1alpha1.AccessRequest, error) {
+	ret := _m.Called(ctx, name, namespace)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAccessRequest")
+	}
+
+	var r0 *v1alpha1.AccessRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*v1alpha1.AccessRequest, error)); ok {
+		return rf(ctx, name, namespace)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *v1alpha1.AccessRequest); ok {
+		r0 = rf(ctx, name, namespace)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1alpha1.AccessRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, name, namespace)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPersister_GetAccessRequest_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAccessRequest'
+type MockPersister_GetAccessRequest_Call struct {
+	*mock.Call
+}
+
+// GetAccessRequest is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+//   - namespace string
+func (_e *MockPersister_Expecter) GetAccessRequest(ctx interface{}, name interface{}, namespace interface{}) *MockPersister_GetAccessRequest_Call {
+	return &MockPersister_GetAccessRequest_Call{Call: _e.mock.On("GetAccessRequest", ctx, name, namespace)}
+}
+
+func (_c *MockPersister_GetAccessRequest_Call) Run(run func(ctx context.Context, name string, namespace string)) *MockPersister_GetAccessRequest_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockPersister_GetAccessRequest_Call) Return(_a0 *v1alpha1.AccessRequest, _a1 error) *MockPersister_GetAccessRequest_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPersister_GetAccessRequest_Call) RunAndReturn(run func(context.Context, string, string) (*v1alpha1.AccessRequest, error)) *MockPersister_GetAccessRequest_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetAppProject provides a mock function with given fields: ctx, name, namespace
 func (_m *MockPersister) GetAppProject(ctx context.Context, name string, namespace string) (*unstructured.Unstructured, error) {
 	ret := _m.Called(ctx, name, namespace)
@@ -206,6 +266,66 @@ func (_c *MockPersister_GetApplication_Call) RunAndReturn(run func(context.Conte
 	return _c
 }
 
+// GetRoleTemplate provides a mock function with given fields: ctx, name, namespace
+func (_m *MockPersister) GetRoleTemplate(ctx context.Context, name string, namespace string) (*v1alpha1.RoleTemplate, error) {
+	ret := _m.Called(ctx, name, namespace)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRoleTemplate")
+	}
+
+	var r0 *v1alpha1.RoleTemplate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*v1alpha1.RoleTemplate, error)); ok {
+		return rf(ctx, name, namespace)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *v1alpha1.RoleTemplate); ok {
+		r0 = rf(ctx, name, namespace)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1alpha1.RoleTemplate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, name, namespace)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPersister_GetRoleTemplate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRoleTemplate'
+type MockPersister_GetRoleTemplate_Call struct {
+	*mock.Call
+}
+
+// GetRoleTemplate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+//   - namespace string
+func (_e *MockPersister_Expecter) GetRoleTemplate(ctx interface{}, name interface{}, namespace interface{}) *MockPersister_GetRoleTemplate_Call {
+	return &MockPersister_GetRoleTemplate_Call{Call: _e.mock.On("GetRoleTemplate", ctx, name, namespace)}
+}
+
+func (_c *MockPersister_GetRoleTemplate_Call) Run(run func(ctx context.Context, name string, namespace string)) *MockPersister_GetRoleTemplate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockPersister_GetRoleTemplate_Call) Return(_a0 *v1alpha1.RoleTemplate, _a1 error) *MockPersister_GetRoleTemplate_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPersister_GetRoleTemplate_Call) RunAndReturn(run func(context.Context, string, string) (*v1alpha1.RoleTemplate, error)) *MockPersister_GetRoleTemplate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ListAccessBindings provides a mock function with given fields: ctx, roleName, namespace
 func (_m *MockPersister) ListAccessBindings(ctx context.Context, roleName string, namespace string) (*v1alpha1.AccessBindingList, error) {
 	ret := _m.Called(ctx, roleName, namespace)
@@ -325,6 +445,125 @@ func (_c *MockPersister_ListAccessRequests_Call) RunAndReturn(run func(context.C
 	return _c
 }
 
+// ListAccessRequestsByRole provides a mock function with given fields: ctx, roleName, namespace
+func (_m *MockPersister) ListAccessRequestsByRole(ctx context.Context, roleName string, namespace string) (*v1alpha1.AccessRequestList, error) {
+	ret := _m.Called(ctx, roleName, namespace)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAccessRequestsByRole")
+	}
+
+	var r0 *v1alpha1.AccessRequestList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*v1alpha1.AccessRequestList, error)); ok {
+		return rf(ctx, roleName, namespace)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *v1alpha1.AccessRequestList); ok {
+		r0 = rf(ctx, roleName, namespace)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1alpha1.AccessRequestList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, roleName, namespace)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPersister_ListAccessRequestsByRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAccessRequestsByRole'
+type MockPersister_ListAccessRequestsByRole_Call struct {
+	*mock.Call
+}
+
+// ListAccessRequestsByRole is a helper method to define mock.On call
+//   - ctx context.Context
+//   - roleName string
+//   - namespace string
+func (_e *MockPersister_Expecter) ListAccessRequestsByRole(ctx interface{}, roleName interface{}, namespace interface{}) *MockPersister_ListAccessRequestsByRole_Call {
+	return &MockPersister_ListAccessRequestsByRole_Call{Call: _e.mock.On("ListAccessRequestsByRole", ctx, roleName, namespace)}
+}
+
+func (_c *MockPersister_ListAccessRequestsByRole_Call) Run(run func(ctx context.Context, roleName string, namespace string)) *MockPersister_ListAccessRequestsByRole_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockPersister_ListAccessRequestsByRole_Call) Return(_a0 *v1alpha1.AccessRequestList, _a1 error) *MockPersister_ListAccessRequestsByRole_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPersister_ListAccessRequestsByRole_Call) RunAndReturn(run func(context.Context, string, string) (*v1alpha1.AccessRequestList, error)) *MockPersister_ListAccessRequestsByRole_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateAccessRequest provides a mock function with given fields: ctx, ar
+func (_m *MockPersister) UpdateAccessRequest(ctx context.Context, ar *v1alpha1.AccessRequest) (*v1alpha1.AccessRequest, error) {
+	ret := _m.Called(ctx, ar)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateAccessRequest")
+	}
+
+	var r0 *v1alpha1.AccessRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *v1alpha1.AccessRequest) (*v1alpha1.AccessRequest, error)); ok {
+		return rf(ctx, ar)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *v1alpha1.AccessRequest) *v1alpha1.AccessRequest); ok {
+		r0 = rf(ctx, ar)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1alpha1.AccessRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *v1alpha1.AccessRequest) error); ok {
+		r1 = rf(ctx, ar)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPersister_UpdateAccessRequest_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateAccessRequest'
+type MockPersister_UpdateAccessRequest_Call struct {
+	*mock.Call
+}
+
+// UpdateAccessRequest is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ar *v1alpha1.AccessRequest
+func (_e *MockPersister_Expecter) UpdateAccessRequest(ctx interface{}, ar interface{}) *MockPersister_UpdateAccessRequest_Call {
+	return &MockPersister_UpdateAccessRequest_Call{Call: _e.mock.On("UpdateAccessRequest", ctx, ar)}
+}
+
+func (_c *MockPersister_UpdateAccessRequest_Call) Run(run func(ctx context.Context, ar *v1alpha1.AccessRequest)) *MockPersister_UpdateAccessRequest_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*v1alpha1.AccessRequest))
+	})
+	return _c
+}
+
+func (_c *MockPersister_UpdateAccessRequest_Call) Return(_a0 *v1alpha1.AccessRequest, _a1 error) *MockPersister_UpdateAccessRequest_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPersister_UpdateAccessRequest_Call) RunAndReturn(run func(context.Context, *v1alpha1.AccessRequest) (*v1alpha1.AccessRequest, error)) *MockPersister_UpdateAccessRequest_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockPersister creates a new instance of MockPersister. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockPersister(t interface {
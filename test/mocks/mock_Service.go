@@ -27,9 +27,9 @@ func (_m *MockService) EXPECT() *MockService_Expecter {
 	return &MockService_Expecter{mock: &_m.Mock}
 }
 
-// CreateAccessRequest provides a mock function with given fields: ctx, key, binding
-func (_m *MockService) CreateAccessRequest(ctx context.Context, key *backend.AccessRequestKey, binding *v1alpha1.AccessBinding) (*v1alpha1.AccessRequest, error) {
-	ret := _m.Called(ctx, key, binding)
+// CreateAccessRequest provides a mock function with given fields: ctx, key, binding, details
+func (_m *MockService) CreateAccessRequest(ctx context.Context, key *backend.AccessRequestKey, binding *v1alpha1.AccessBinding, details backend.AccessRequestDetails) (*v1alpha1.AccessRequest, error) {
+	ret := _m.Called(ctx, key, binding, details)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateAccessRequest")
@@ -37,19 +37,19 @@ func (_m *MockService) CreateAccessRequest(ctx context.Context, key *backend.Acc
 
 	var r0 *v1alpha1.AccessRequest
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, *backend.AccessRequestKey, *v1alpha1.AccessBinding) (*v1alpha1.AccessRequest, error)); ok {
-		return rf(ctx, key, binding)
+	if rf, ok := ret.Get(0).(func(context.Context, *backend.AccessRequestKey, *v1alpha1.AccessBinding, backend.AccessRequestDetails) (*v1alpha1.AccessRequest, error)); ok {
+		return rf(ctx, key, binding, details)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, *backend.AccessRequestKey, *v1alpha1.AccessBinding) *v1alpha1.AccessRequest); ok {
-		r0 = rf(ctx, key, binding)
+	if rf, ok := ret.Get(0).(func(context.Context, *backend.AccessRequestKey, *v1alpha1.AccessBinding, backend.AccessRequestDetails) *v1alpha1.AccessRequest); ok {
+		r0 = rf(ctx, key, binding, details)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*v1alpha1.AccessRequest)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, *backend.AccessRequestKey, *v1alpha1.AccessBinding) error); ok {
-		r1 = rf(ctx, key, binding)
+	if rf, ok := ret.Get(1).(func(context.Context, *backend.AccessRequestKey, *v1alpha1.AccessBinding, backend.AccessRequestDetails) error); ok {
+		r1 = rf(ctx, key, binding, details)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -66,13 +66,14 @@ type MockService_CreateAccessRequest_Call struct {
 //   - ctx context.Context
 //   - key *backend.AccessRequestKey
 //   - binding *v1alpha1.AccessBinding
-func (_e *MockService_Expecter) CreateAccessRequest(ctx interface{}, key interface{}, binding interface{}) *MockService_CreateAccessRequest_Call {
-	return &MockService_CreateAccessRequest_Call{Call: _e.mock.On("CreateAccessRequest", ctx, key, binding)}
+//   - details backend.AccessRequestDetails
+func (_e *MockService_Expecter) CreateAccessRequest(ctx interface{}, key interface{}, binding interface{}, details interface{}) *MockService_CreateAccessRequest_Call {
+	return &MockService_CreateAccessRequest_Call{Call: _e.mock.On("CreateAccessRequest", ctx, key, binding, details)}
 }
 
-func (_c *MockService_CreateAccessRequest_Call) Run(run func(ctx context.Context, key *backend.AccessRequestKey, binding *v1alpha1.AccessBinding)) *MockService_CreateAccessRequest_Call {
+func (_c *MockService_CreateAccessRequest_Call) Run(run func(ctx context.Context, key *backend.AccessRequestKey, binding *v1alpha1.AccessBinding, details backend.AccessRequestDetails)) *MockService_CreateAccessRequest_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(*backend.AccessRequestKey), args[2].(*v1alpha1.AccessBinding))
+		run(args[0].(context.Context), args[1].(*backend.AccessRequestKey), args[2].(*v1alpha1.AccessBinding), args[3].(backend.AccessRequestDetails))
 	})
 	return _c
 }
@@ -82,7 +83,127 @@ func (_c *MockService_CreateAccessRequest_Call) Return(_a0 *v1alpha1.AccessReque
 	return _c
 }
 
-func (_c *MockService_CreateAccessRequest_Call) RunAndReturn(run func(context.Context, *backend.AccessRequestKey, *v1alpha1.AccessBinding) (*v1alpha1.AccessRequest, error)) *MockService_CreateAccessRequest_Call {
+func (_c *MockService_CreateAccessRequest_Call) RunAndReturn(run func(context.Context, *backend.AccessRequestKey, *v1alpha1.AccessBinding, backend.AccessRequestDetails) (*v1alpha1.AccessRequest, error)) *MockService_CreateAccessRequest_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ExtendAccessRequest provides a mock function with given fields: ctx, ar, extension
+func (_m *MockService) ExtendAccessRequest(ctx context.Context, ar *v1alpha1.AccessRequest, extension *v1alpha1.Extension) (*v1alpha1.AccessRequest, error) {
+	ret := _m.Called(ctx, ar, extension)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExtendAccessRequest")
+	}
+
+	var r0 *v1alpha1.AccessRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *v1alpha1.AccessRequest, *v1alpha1.Extension) (*v1alpha1.AccessRequest, error)); ok {
+		return rf(ctx, ar, extension)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *v1alpha1.AccessRequest, *v1alpha1.Extension) *v1alpha1.AccessRequest); ok {
+		r0 = rf(ctx, ar, extension)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1alpha1.AccessRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *v1alpha1.AccessRequest, *v1alpha1.Extension) error); ok {
+		r1 = rf(ctx, ar, extension)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_ExtendAccessRequest_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExtendAccessRequest'
+type MockService_ExtendAccessRequest_Call struct {
+	*mock.Call
+}
+
+// ExtendAccessRequest is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ar *v1alpha1.AccessRequest
+//   - extension *v1alpha1.Extension
+func (_e *MockService_Expecter) ExtendAccessRequest(ctx interface{}, ar interface{}, extension interface{}) *MockService_ExtendAccessRequest_Call {
+	return &MockService_ExtendAccessRequest_Call{Call: _e.mock.On("ExtendAccessRequest", ctx, ar, extension)}
+}
+
+func (_c *MockService_ExtendAccessRequest_Call) Run(run func(ctx context.Context, ar *v1alpha1.AccessRequest, extension *v1alpha1.Extension)) *MockService_ExtendAccessRequest_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*v1alpha1.AccessRequest), args[2].(*v1alpha1.Extension))
+	})
+	return _c
+}
+
+func (_c *MockService_ExtendAccessRequest_Call) Return(_a0 *v1alpha1.AccessRequest, _a1 error) *MockService_ExtendAccessRequest_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_ExtendAccessRequest_Call) RunAndReturn(run func(context.Context, *v1alpha1.AccessRequest, *v1alpha1.Extension) (*v1alpha1.AccessRequest, error)) *MockService_ExtendAccessRequest_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAccessRequest provides a mock function with given fields: ctx, name, namespace
+func (_m *MockService) GetAccessRequest(ctx context.Context, name string, namespace string) (*v1alpha1.AccessRequest, error) {
+	ret := _m.Called(ctx, name, namespace)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAccessRequest")
+	}
+
+	var r0 *v1alpha1.AccessRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*v1alpha1.AccessRequest, error)); ok {
+		return rf(ctx, name, namespace)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *v1alpha1.AccessRequest); ok {
+		r0 = rf(ctx, name, namespace)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1alpha1.AccessRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, name, namespace)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_GetAccessRequest_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAccessRequest'
+type MockService_GetAccessRequest_Call struct {
+	*mock.Call
+}
+
+// GetAccessRequest is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+//   - namespace string
+func (_e *MockService_Expecter) GetAccessRequest(ctx interface{}, name interface{}, namespace interface{}) *MockService_GetAccessRequest_Call {
+	return &MockService_GetAccessRequest_Call{Call: _e.mock.On("GetAccessRequest", ctx, name, namespace)}
+}
+
+func (_c *MockService_GetAccessRequest_Call) Run(run func(ctx context.Context, name string, namespace string)) *MockService_GetAccessRequest_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockService_GetAccessRequest_Call) Return(_a0 *v1alpha1.AccessRequest, _a1 error) *MockService_GetAccessRequest_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_GetAccessRequest_Call) RunAndReturn(run func(context.Context, string, string) (*v1alpha1.AccessRequest, error)) *MockService_GetAccessRequest_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -267,9 +388,9 @@ func (_c *MockService_GetApplication_Call) RunAndReturn(run func(context.Context
 	return _c
 }
 
-// GetGrantingAccessBinding provides a mock function with given fields: ctx, roleName, namespace, groups, app, project
-func (_m *MockService) GetGrantingAccessBinding(ctx context.Context, roleName string, namespace string, groups []string, app *unstructured.Unstructured, project *unstructured.Unstructured) (*v1alpha1.AccessBinding, error) {
-	ret := _m.Called(ctx, roleName, namespace, groups, app, project)
+// GetGrantingAccessBinding provides a mock function with given fields: ctx, roleName, namespace, groups, targetGroup, breakGlass, app, project
+func (_m *MockService) GetGrantingAccessBinding(ctx context.Context, roleName string, namespace string, groups []string, targetGroup string, breakGlass bool, app *unstructured.Unstructured, project *unstructured.Unstructured) (*v1alpha1.AccessBinding, error) {
+	ret := _m.Called(ctx, roleName, namespace, groups, targetGroup, breakGlass, app, project)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetGrantingAccessBinding")
@@ -277,19 +398,19 @@ func (_m *MockService) GetGrantingAccessBinding(ctx context.Context, roleName st
 
 	var r0 *v1alpha1.AccessBinding
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string, *unstructured.Unstructured, *unstructured.Unstructured) (*v1alpha1.AccessBinding, error)); ok {
-		return rf(ctx, roleName, namespace, groups, app, project)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string, string, bool, *unstructured.Unstructured, *unstructured.Unstructured) (*v1alpha1.AccessBinding, error)); ok {
+		return rf(ctx, roleName, namespace, groups, targetGroup, breakGlass, app, project)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string, *unstructured.Unstructured, *unstructured.Unstructured) *v1alpha1.AccessBinding); ok {
-		r0 = rf(ctx, roleName, namespace, groups, app, project)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string, string, bool, *unstructured.Unstructured, *unstructured.Unstructured) *v1alpha1.AccessBinding); ok {
+		r0 = rf(ctx, roleName, namespace, groups, targetGroup, breakGlass, app, project)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*v1alpha1.AccessBinding)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string, []string, *unstructured.Unstructured, *unstructured.Unstructured) error); ok {
-		r1 = rf(ctx, roleName, namespace, groups, app, project)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, []string, string, bool, *unstructured.Unstructured, *unstructured.Unstructured) error); ok {
+		r1 = rf(ctx, roleName, namespace, groups, targetGroup, breakGlass, app, project)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -307,15 +428,17 @@ type MockService_GetGrantingAccessBinding_Call struct {
 //   - roleName string
 //   - namespace string
 //   - groups []string
+//   - targetGroup string
+//   - breakGlass bool
 //   - app *unstructured.Unstructured
 //   - project *unstructured.Unstructured
-func (_e *MockService_Expecter) GetGrantingAccessBinding(ctx interface{}, roleName interface{}, namespace interface{}, groups interface{}, app interface{}, project interface{}) *MockService_GetGrantingAccessBinding_Call {
-	return &MockService_GetGrantingAccessBinding_Call{Call: _e.mock.On("GetGrantingAccessBinding", ctx, roleName, namespace, groups, app, project)}
+func (_e *MockService_Expecter) GetGrantingAccessBinding(ctx interface{}, roleName interface{}, namespace interface{}, groups interface{}, targetGroup interface{}, breakGlass interface{}, app interface{}, project interface{}) *MockService_GetGrantingAccessBinding_Call {
+	return &MockService_GetGrantingAccessBinding_Call{Call: _e.mock.On("GetGrantingAccessBinding", ctx, roleName, namespace, groups, targetGroup, breakGlass, app, project)}
 }
 
-func (_c *MockService_GetGrantingAccessBinding_Call) Run(run func(ctx context.Context, roleName string, namespace string, groups []string, app *unstructured.Unstructured, project *unstructured.Unstructured)) *MockService_GetGrantingAccessBinding_Call {
+func (_c *MockService_GetGrantingAccessBinding_Call) Run(run func(ctx context.Context, roleName string, namespace string, groups []string, targetGroup string, breakGlass bool, app *unstructured.Unstructured, project *unstructured.Unstructured)) *MockService_GetGrantingAccessBinding_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].([]string), args[4].(*unstructured.Unstructured), args[5].(*unstructured.Unstructured))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].([]string), args[4].(string), args[5].(bool), args[6].(*unstructured.Unstructured), args[7].(*unstructured.Unstructured))
 	})
 	return _c
 }
@@ -325,7 +448,67 @@ func (_c *MockService_GetGrantingAccessBinding_Call) Return(_a0 *v1alpha1.Access
 	return _c
 }
 
-func (_c *MockService_GetGrantingAccessBinding_Call) RunAndReturn(run func(context.Context, string, string, []string, *unstructured.Unstructured, *unstructured.Unstructured) (*v1alpha1.AccessBinding, error)) *MockService_GetGrantingAccessBinding_Call {
+func (_c *MockService_GetGrantingAccessBinding_Call) RunAndReturn(run func(context.Context, string, string, []string, string, bool, *unstructured.Unstructured, *unstructured.Unstructured) (*v1alpha1.AccessBinding, error)) *MockService_GetGrantingAccessBinding_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRoleTemplate provides a mock function with given fields: ctx, name, namespace
+func (_m *MockService) GetRoleTemplate(ctx context.Context, name string, namespace string) (*v1alpha1.RoleTemplate, error) {
+	ret := _m.Called(ctx, name, namespace)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRoleTemplate")
+	}
+
+	var r0 *v1alpha1.RoleTemplate
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*v1alpha1.RoleTemplate, error)); ok {
+		return rf(ctx, name, namespace)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *v1alpha1.RoleTemplate); ok {
+		r0 = rf(ctx, name, namespace)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1alpha1.RoleTemplate)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, name, namespace)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_GetRoleTemplate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRoleTemplate'
+type MockService_GetRoleTemplate_Call struct {
+	*mock.Call
+}
+
+// GetRoleTemplate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+//   - namespace string
+func (_e *MockService_Expecter) GetRoleTemplate(ctx interface{}, name interface{}, namespace interface{}) *MockService_GetRoleTemplate_Call {
+	return &MockService_GetRoleTemplate_Call{Call: _e.mock.On("GetRoleTemplate", ctx, name, namespace)}
+}
+
+func (_c *MockService_GetRoleTemplate_Call) Run(run func(ctx context.Context, name string, namespace string)) *MockService_GetRoleTemplate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockService_GetRoleTemplate_Call) Return(_a0 *v1alpha1.RoleTemplate, _a1 error) *MockService_GetRoleTemplate_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_GetRoleTemplate_Call) RunAndReturn(run func(context.Context, string, string) (*v1alpha1.RoleTemplate, error)) *MockService_GetRoleTemplate_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -390,6 +573,248 @@ func (_c *MockService_ListAccessRequests_Call) RunAndReturn(run func(context.Con
 	return _c
 }
 
+// ListGrantableRoles provides a mock function with given fields: ctx, namespace, groups, app, project
+func (_m *MockService) ListGrantableRoles(ctx context.Context, namespace string, groups []string, app *unstructured.Unstructured, project *unstructured.Unstructured) ([]backend.GrantableRole, error) {
+	ret := _m.Called(ctx, namespace, groups, app, project)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListGrantableRoles")
+	}
+
+	var r0 []backend.GrantableRole
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, *unstructured.Unstructured, *unstructured.Unstructured) ([]backend.GrantableRole, error)); ok {
+		return rf(ctx, namespace, groups, app, project)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string, *unstructured.Unstructured, *unstructured.Unstructured) []backend.GrantableRole); ok {
+		r0 = rf(ctx, namespace, groups, app, project)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]backend.GrantableRole)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string, *unstructured.Unstructured, *unstructured.Unstructured) error); ok {
+		r1 = rf(ctx, namespace, groups, app, project)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_ListGrantableRoles_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListGrantableRoles'
+type MockService_ListGrantableRoles_Call struct {
+	*mock.Call
+}
+
+// ListGrantableRoles is a helper method to define mock.On call
+//   - ctx context.Context
+//   - namespace string
+//   - groups []string
+//   - app *unstructured.Unstructured
+//   - project *unstructured.Unstructured
+func (_e *MockService_Expecter) ListGrantableRoles(ctx interface{}, namespace interface{}, groups interface{}, app interface{}, project interface{}) *MockService_ListGrantableRoles_Call {
+	return &MockService_ListGrantableRoles_Call{Call: _e.mock.On("ListGrantableRoles", ctx, namespace, groups, app, project)}
+}
+
+func (_c *MockService_ListGrantableRoles_Call) Run(run func(ctx context.Context, namespace string, groups []string, app *unstructured.Unstructured, project *unstructured.Unstructured)) *MockService_ListGrantableRoles_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].([]string), args[3].(*unstructured.Unstructured), args[4].(*unstructured.Unstructured))
+	})
+	return _c
+}
+
+func (_c *MockService_ListGrantableRoles_Call) Return(_a0 []backend.GrantableRole, _a1 error) *MockService_ListGrantableRoles_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_ListGrantableRoles_Call) RunAndReturn(run func(context.Context, string, []string, *unstructured.Unstructured, *unstructured.Unstructured) ([]backend.GrantableRole, error)) *MockService_ListGrantableRoles_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReviewAccessRequest provides a mock function with given fields: ctx, ar, review
+func (_m *MockService) ReviewAccessRequest(ctx context.Context, ar *v1alpha1.AccessRequest, review *v1alpha1.Review) (*v1alpha1.AccessRequest, error) {
+	ret := _m.Called(ctx, ar, review)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReviewAccessRequest")
+	}
+
+	var r0 *v1alpha1.AccessRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *v1alpha1.AccessRequest, *v1alpha1.Review) (*v1alpha1.AccessRequest, error)); ok {
+		return rf(ctx, ar, review)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *v1alpha1.AccessRequest, *v1alpha1.Review) *v1alpha1.AccessRequest); ok {
+		r0 = rf(ctx, ar, review)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1alpha1.AccessRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *v1alpha1.AccessRequest, *v1alpha1.Review) error); ok {
+		r1 = rf(ctx, ar, review)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_ReviewAccessRequest_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReviewAccessRequest'
+type MockService_ReviewAccessRequest_Call struct {
+	*mock.Call
+}
+
+// ReviewAccessRequest is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ar *v1alpha1.AccessRequest
+//   - review *v1alpha1.Review
+func (_e *MockService_Expecter) ReviewAccessRequest(ctx interface{}, ar interface{}, review interface{}) *MockService_ReviewAccessRequest_Call {
+	return &MockService_ReviewAccessRequest_Call{Call: _e.mock.On("ReviewAccessRequest", ctx, ar, review)}
+}
+
+func (_c *MockService_ReviewAccessRequest_Call) Run(run func(ctx context.Context, ar *v1alpha1.AccessRequest, review *v1alpha1.Review)) *MockService_ReviewAccessRequest_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*v1alpha1.AccessRequest), args[2].(*v1alpha1.Review))
+	})
+	return _c
+}
+
+func (_c *MockService_ReviewAccessRequest_Call) Return(_a0 *v1alpha1.AccessRequest, _a1 error) *MockService_ReviewAccessRequest_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_ReviewAccessRequest_Call) RunAndReturn(run func(context.Context, *v1alpha1.AccessRequest, *v1alpha1.Review) (*v1alpha1.AccessRequest, error)) *MockService_ReviewAccessRequest_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeAccessRequest provides a mock function with given fields: ctx, ar, revocation
+func (_m *MockService) RevokeAccessRequest(ctx context.Context, ar *v1alpha1.AccessRequest, revocation *v1alpha1.Revocation) (*v1alpha1.AccessRequest, error) {
+	ret := _m.Called(ctx, ar, revocation)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeAccessRequest")
+	}
+
+	var r0 *v1alpha1.AccessRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *v1alpha1.AccessRequest, *v1alpha1.Revocation) (*v1alpha1.AccessRequest, error)); ok {
+		return rf(ctx, ar, revocation)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *v1alpha1.AccessRequest, *v1alpha1.Revocation) *v1alpha1.AccessRequest); ok {
+		r0 = rf(ctx, ar, revocation)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1alpha1.AccessRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *v1alpha1.AccessRequest, *v1alpha1.Revocation) error); ok {
+		r1 = rf(ctx, ar, revocation)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_RevokeAccessRequest_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeAccessRequest'
+type MockService_RevokeAccessRequest_Call struct {
+	*mock.Call
+}
+
+// RevokeAccessRequest is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ar *v1alpha1.AccessRequest
+//   - revocation *v1alpha1.Revocation
+func (_e *MockService_Expecter) RevokeAccessRequest(ctx interface{}, ar interface{}, revocation interface{}) *MockService_RevokeAccessRequest_Call {
+	return &MockService_RevokeAccessRequest_Call{Call: _e.mock.On("RevokeAccessRequest", ctx, ar, revocation)}
+}
+
+func (_c *MockService_RevokeAccessRequest_Call) Run(run func(ctx context.Context, ar *v1alpha1.AccessRequest, revocation *v1alpha1.Revocation)) *MockService_RevokeAccessRequest_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*v1alpha1.AccessRequest), args[2].(*v1alpha1.Revocation))
+	})
+	return _c
+}
+
+func (_c *MockService_RevokeAccessRequest_Call) Return(_a0 *v1alpha1.AccessRequest, _a1 error) *MockService_RevokeAccessRequest_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_RevokeAccessRequest_Call) RunAndReturn(run func(context.Context, *v1alpha1.AccessRequest, *v1alpha1.Revocation) (*v1alpha1.AccessRequest, error)) *MockService_RevokeAccessRequest_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateAccessRequestApproval provides a mock function with given fields: ctx, ar, approval
+func (_m *MockService) UpdateAccessRequestApproval(ctx context.Context, ar *v1alpha1.AccessRequest, approval *v1alpha1.Approval) (*v1alpha1.AccessRequest, error) {
+	ret := _m.Called(ctx, ar, approval)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateAccessRequestApproval")
+	}
+
+	var r0 *v1alpha1.AccessRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *v1alpha1.AccessRequest, *v1alpha1.Approval) (*v1alpha1.AccessRequest, error)); ok {
+		return rf(ctx, ar, approval)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *v1alpha1.AccessRequest, *v1alpha1.Approval) *v1alpha1.AccessRequest); ok {
+		r0 = rf(ctx, ar, approval)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1alpha1.AccessRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *v1alpha1.AccessRequest, *v1alpha1.Approval) error); ok {
+		r1 = rf(ctx, ar, approval)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockService_UpdateAccessRequestApproval_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateAccessRequestApproval'
+type MockService_UpdateAccessRequestApproval_Call struct {
+	*mock.Call
+}
+
+// UpdateAccessRequestApproval is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ar *v1alpha1.AccessRequest
+//   - approval *v1alpha1.Approval
+func (_e *MockService_Expecter) UpdateAccessRequestApproval(ctx interface{}, ar interface{}, approval interface{}) *MockService_UpdateAccessRequestApproval_Call {
+	return &MockService_UpdateAccessRequestApproval_Call{Call: _e.mock.On("UpdateAccessRequestApproval", ctx, ar, approval)}
+}
+
+func (_c *MockService_UpdateAccessRequestApproval_Call) Run(run func(ctx context.Context, ar *v1alpha1.AccessRequest, approval *v1alpha1.Approval)) *MockService_UpdateAccessRequestApproval_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*v1alpha1.AccessRequest), args[2].(*v1alpha1.Approval))
+	})
+	return _c
+}
+
+func (_c *MockService_UpdateAccessRequestApproval_Call) Return(_a0 *v1alpha1.AccessRequest, _a1 error) *MockService_UpdateAccessRequestApproval_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockService_UpdateAccessRequestApproval_Call) RunAndReturn(run func(context.Context, *v1alpha1.AccessRequest, *v1alpha1.Approval) (*v1alpha1.AccessRequest, error)) *MockService_UpdateAccessRequestApproval_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockService creates a new instance of MockService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockService(t interface {
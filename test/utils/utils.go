@@ -374,6 +374,25 @@ func NewRoleTemplate(templateName, namespace, roleName string, policies []string
 	}
 }
 
+func NewAccessBinding(name, namespace, roleTemplateName string, subjects []string) *api.AccessBinding {
+	return &api.AccessBinding{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "AccessBinding",
+			APIVersion: "v1alpha1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: api.AccessBindingSpec{
+			RoleTemplateRef: api.RoleTemplateReference{
+				Name: roleTemplateName,
+			},
+			Subjects: subjects,
+		},
+	}
+}
+
 func NewNamespace(name string) *corev1.Namespace {
 	return &corev1.Namespace{
 		TypeMeta: metav1.TypeMeta{